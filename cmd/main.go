@@ -1,19 +1,40 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"QueueBot/config"
+	"QueueBot/internal/controller/api"
+	"QueueBot/internal/controller/dashboard"
+	"QueueBot/internal/controller/scheduler"
 	"QueueBot/internal/controller/telegram"
 	"QueueBot/internal/controller/telegram/client"
+	"QueueBot/internal/health"
+	"QueueBot/internal/metrics"
 	"QueueBot/internal/usecase"
+	"QueueBot/internal/usecase/storage"
+	"QueueBot/internal/usecase/storage/memory"
+	"QueueBot/internal/usecase/storage/postgres"
 	"QueueBot/internal/usecase/storage/sqlite"
 )
 
+const (
+	httpReadHeaderTimeout = 10 * time.Second
+	httpShutdownTimeout   = 5 * time.Second
+)
+
 func main() {
 	cfg, err := config.NewConfig()
 	if err != nil {
@@ -28,39 +49,189 @@ func main() {
 	h := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: programLevel})
 	slog.SetDefault(slog.New(h))
 
-	botAPI, err := tgbotapi.NewBotAPI(cfg.BotToken)
+	botAPI, err := tgbotapi.NewBotAPIWithClient(cfg.BotToken, tgbotapi.APIEndpoint, metrics.NewHTTPClient(&http.Client{}))
 	if err != nil {
 		log.Fatalf("Couldn't initialize bot with error: %s", err.Error())
 	}
 
 	botAPI.Debug = cfg.IsTelegramDebug
 
-	storage, err := sqlite.NewDatabase(cfg.DatabasePath)
+	db, err := newStorage(cfg)
 	if err != nil {
 		log.Fatalf("Couldn't initialize storage: %s", err)
 	}
 
-	defer func(storage *sqlite.Database) {
-		err := storage.Close()
+	db = metrics.NewStorage(db)
+	prometheus.MustRegister(metrics.NewActiveCollector(db))
+
+	defer func(db storage.Storage) {
+		err := db.Close()
 		if err != nil {
 			log.Fatalf("couldn't close storage")
 		}
-	}(storage)
+	}(db)
 
-	botUseCase := usecase.NewBotUseCase(storage)
-	bot := client.NewTelegramBot(botAPI, botUseCase)
-	server := telegram.NewBotServer(bot)
+	botUseCase := usecase.NewBotUseCase(db, client.NewNotifier(botAPI))
+	edits := client.NewEditScheduler(botAPI, client.DefaultEditDebounce)
+	defer edits.Close()
 
-	updateConfig := tgbotapi.NewUpdate(0)
-	updateConfig.Timeout = 30
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// workers are HTTP listeners and the scheduler. They are awaited before storage is closed, so their changes
+	// don't hit closed storage.
+	var workers sync.WaitGroup
+
+	serve := func(name string, addr string, handler http.Handler) {
+		workers.Add(1)
+
+		go func() {
+			defer workers.Done()
+
+			if err := serveHTTP(ctx, name, addr, handler); err != nil {
+				slog.Error(err.Error())
+			}
+		}()
+	}
+
+	var dashboardLinks client.DashboardLinks
+
+	if cfg.DashboardListenAddr != "" {
+		hub := dashboard.NewHub()
+		botUseCase.Observer = hub
+		// Viewers stay connected for long, so their streams end with the bot instead of delaying shutdown.
+		context.AfterFunc(ctx, hub.Close)
+
+		links := dashboard.NewLinks(cfg.DashboardURL, cfg.DashboardSecret)
+		dashboardLinks = links
+
+		serve("dashboard", cfg.DashboardListenAddr, dashboard.NewServer(botUseCase, hub, links))
+	}
+
+	bot := client.NewTelegramBot(botAPI, botUseCase, edits, dashboardLinks)
+	server := telegram.NewBotServer(bot, cfg.ShutdownTimeout)
+
+	workers.Add(1)
+
+	go func() {
+		defer workers.Done()
+		scheduler.NewScheduler(botUseCase, server, time.Now, scheduler.DefaultInterval, cfg.ShutdownTimeout).Run(ctx)
+	}()
+
+	source := newUpdateSource(cfg, botAPI)
+
+	if cfg.MetricsListenAddr != "" {
+		monitoring := newMonitoringHandler(health.Ready(readinessChecks(cfg, db, source)...))
+		serve("monitoring", cfg.MetricsListenAddr, monitoring)
+	} else {
+		slog.Info("Metrics and health checks aren't served, set METRICS_LISTEN_ADDR to serve them")
+	}
+
+	if cfg.APIListenAddr != "" {
+		serve("api", cfg.APIListenAddr, api.NewServer(botUseCase, server, cfg.APITokens))
+	}
 
 	errChan := make(chan error)
 
-	go server.Listen(updateConfig, errChan)
+	go func() {
+		server.Listen(ctx, source, errChan)
+		close(errChan)
+	}()
 
 	for err := range errChan {
 		if err != nil {
 			slog.Error(err.Error())
 		}
 	}
+
+	// Listen also returns when updates couldn't be received at all, then the rest of the bot is stopped too.
+	stop()
+	workers.Wait()
+}
+
+func newMonitoringHandler(ready http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.HandleFunc("/healthz", health.Live)
+	mux.Handle("/readyz", ready)
+
+	return mux
+}
+
+// serveHTTP serves handler at addr until ctx is done. Then it returns once running requests have finished.
+func serveHTTP(ctx context.Context, name string, addr string, handler http.Handler) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: httpReadHeaderTimeout,
+	}
+
+	serveErr := make(chan error, 1)
+
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	slog.Info("Started listener", "name", name, "addr", addr)
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("listener %s stopped: %w", name, err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+	defer cancel()
+
+	// ListenAndServe returns as soon as Shutdown starts, so only Shutdown waits for the requests.
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("couldn't shut listener %s down gracefully: %w", name, err)
+	}
+
+	return nil
+}
+
+// readinessChecks checks storage and, in polling mode, that Telegram answers getUpdates.
+func readinessChecks(cfg *config.Config, db storage.Storage, source telegram.UpdateSource) []health.Check {
+	checks := []health.Check{{Name: "storage", Check: db.Ping}}
+
+	if polling, ok := source.(*telegram.Polling); ok {
+		checks = append(checks, health.Check{
+			Name: "polling",
+			Check: func(context.Context) error {
+				return polling.CheckFresh(cfg.PollingStaleAfter)
+			},
+		})
+	}
+
+	return checks
+}
+
+func newStorage(cfg *config.Config) (storage.Storage, error) {
+	switch cfg.StorageDriver {
+	case config.PostgresDriver:
+		return postgres.NewDatabase(cfg.PostgresDSN)
+	case config.MemoryDriver:
+		return memory.NewStorage(), nil
+	default:
+		return sqlite.NewDatabase(cfg.DatabasePath)
+	}
+}
+
+func newUpdateSource(cfg *config.Config, botAPI *tgbotapi.BotAPI) telegram.UpdateSource {
+	if cfg.UpdatesMode == config.WebhookMode {
+		return telegram.NewWebhook(botAPI, telegram.WebhookConfig{
+			URL:         cfg.WebhookURL,
+			ListenAddr:  cfg.WebhookListenAddr,
+			SecretToken: cfg.WebhookSecretToken,
+			CertFile:    cfg.WebhookCertFile,
+			KeyFile:     cfg.WebhookKeyFile,
+			UploadCert:  cfg.WebhookUploadCert,
+		})
+	}
+
+	updateConfig := tgbotapi.NewUpdate(0)
+	updateConfig.Timeout = 30
+
+	return telegram.NewPolling(botAPI, updateConfig)
 }
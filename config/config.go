@@ -1,16 +1,75 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
+const (
+	SqliteDriver   = "sqlite"
+	PostgresDriver = "postgres"
+	MemoryDriver   = "memory"
+)
+
+const (
+	PollingMode = "polling"
+	WebhookMode = "webhook"
+)
+
+var (
+	ErrUnknownStorageDriver = errors.New("unknown storage driver")
+	ErrMissingDatabasePath  = errors.New("DATABASE_PATH is required for sqlite storage")
+	ErrMissingPostgresDSN   = errors.New("POSTGRES_DSN is required for postgres storage")
+
+	ErrUnknownUpdatesMode    = errors.New("unknown updates mode")
+	ErrMissingWebhookURL     = errors.New("WEBHOOK_URL is required for webhook mode")
+	ErrMissingWebhookSecret  = errors.New("WEBHOOK_SECRET_TOKEN is required for webhook mode")
+	ErrIncompleteWebhookCert = errors.New("WEBHOOK_CERT_FILE and WEBHOOK_KEY_FILE must be set together")
+
+	ErrMissingAPITokens = errors.New("API_TOKENS is required when API_LISTEN_ADDR is set")
+
+	ErrMissingDashboardURL    = errors.New("DASHBOARD_URL is required when DASHBOARD_LISTEN_ADDR is set")
+	ErrMissingDashboardSecret = errors.New("DASHBOARD_SECRET is required when DASHBOARD_LISTEN_ADDR is set")
+)
+
 type Config struct {
 	BotToken        string `env:"BOT_TOKEN" env-required:"true"`
 	IsTelegramDebug bool   `env:"TELEGRAM_DEBUG" env-default:"false"`
 	IsAppDebug      bool   `env:"APP_DEBUG" env-default:"false"`
-	DatabasePath    string `env:"DATABASE_PATH" env-required:"true"`
+	StorageDriver   string `env:"STORAGE_DRIVER" env-default:"sqlite"`
+	DatabasePath    string `env:"DATABASE_PATH"`
+	PostgresDSN     string `env:"POSTGRES_DSN"`
+	// ShutdownTimeout is how long running handlers may finish after SIGTERM or SIGINT.
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" env-default:"10s"`
+	// MetricsListenAddr serves /metrics and the /healthz and /readyz probes, e.g. ":9090".
+	// It's empty by default, then neither metrics nor probes are served.
+	MetricsListenAddr string `env:"METRICS_LISTEN_ADDR"`
+	// PollingStaleAfter makes the bot not ready when getUpdates hasn't succeeded for longer. It must exceed the long poll timeout.
+	PollingStaleAfter time.Duration `env:"POLLING_STALE_AFTER" env-default:"90s"`
+
+	UpdatesMode        string `env:"UPDATES_MODE" env-default:"polling"`
+	WebhookURL         string `env:"WEBHOOK_URL"`
+	WebhookListenAddr  string `env:"WEBHOOK_LISTEN_ADDR" env-default:":8443"`
+	WebhookSecretToken string `env:"WEBHOOK_SECRET_TOKEN"`
+	// Without certificate the listener serves plain HTTP behind a reverse proxy.
+	WebhookCertFile   string `env:"WEBHOOK_CERT_FILE"`
+	WebhookKeyFile    string `env:"WEBHOOK_KEY_FILE"`
+	WebhookUploadCert bool   `env:"WEBHOOK_UPLOAD_CERT" env-default:"false"`
+
+	// APIListenAddr serves the queue management API. The API is disabled when it is empty.
+	APIListenAddr string `env:"API_LISTEN_ADDR"`
+	// APITokens maps API tokens to Telegram IDs of users they act for, e.g. "token1:123,token2:456".
+	APITokens map[string]int64 `env:"API_TOKENS"`
+
+	// DashboardListenAddr serves live pages of queues. Dashboards are disabled when it is empty.
+	DashboardListenAddr string `env:"DASHBOARD_LISTEN_ADDR"`
+	// DashboardURL is the public address of the dashboard listener, which links start with.
+	DashboardURL string `env:"DASHBOARD_URL"`
+	// DashboardSecret signs links, so the link of one queue doesn't open others.
+	DashboardSecret string `env:"DASHBOARD_SECRET"`
 }
 
 func NewConfig() (*Config, error) {
@@ -21,5 +80,76 @@ func NewConfig() (*Config, error) {
 		return nil, fmt.Errorf("couldn't read env: %w", err)
 	}
 
+	if err = cfg.validateStorage(); err != nil {
+		return nil, fmt.Errorf("invalid storage config: %w", err)
+	}
+
+	if err = cfg.validateUpdates(); err != nil {
+		return nil, fmt.Errorf("invalid updates config: %w", err)
+	}
+
+	if cfg.APIListenAddr != "" && len(cfg.APITokens) == 0 {
+		return nil, fmt.Errorf("invalid api config: %w", ErrMissingAPITokens)
+	}
+
+	if err = cfg.validateDashboard(); err != nil {
+		return nil, fmt.Errorf("invalid dashboard config: %w", err)
+	}
+
 	return cfg, nil
 }
+
+func (c *Config) validateStorage() error {
+	switch c.StorageDriver {
+	case SqliteDriver:
+		if c.DatabasePath == "" {
+			return ErrMissingDatabasePath
+		}
+	case PostgresDriver:
+		if c.PostgresDSN == "" {
+			return ErrMissingPostgresDSN
+		}
+	case MemoryDriver:
+		// Nothing to configure.
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownStorageDriver, c.StorageDriver)
+	}
+
+	return nil
+}
+
+func (c *Config) validateUpdates() error {
+	switch c.UpdatesMode {
+	case PollingMode:
+		// Nothing to configure.
+	case WebhookMode:
+		if c.WebhookURL == "" {
+			return ErrMissingWebhookURL
+		}
+
+		if c.WebhookSecretToken == "" {
+			return ErrMissingWebhookSecret
+		}
+
+		if (c.WebhookCertFile == "") != (c.WebhookKeyFile == "") {
+			return ErrIncompleteWebhookCert
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownUpdatesMode, c.UpdatesMode)
+	}
+
+	return nil
+}
+
+func (c *Config) validateDashboard() error {
+	switch {
+	case c.DashboardListenAddr == "":
+		// Dashboards are disabled.
+	case c.DashboardURL == "":
+		return ErrMissingDashboardURL
+	case c.DashboardSecret == "":
+		return ErrMissingDashboardSecret
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+// Package api serves a JSON API for managing queues outside Telegram.
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"QueueBot/internal/usecase"
+	"QueueBot/internal/usecase/storage"
+)
+
+// QueuesPath is the prefix of all API routes.
+const QueuesPath = "/api/queues"
+
+var (
+	errUnauthorized     = errors.New("missing or unknown API token")
+	errNotFound         = errors.New("not found")
+	errMethodNotAllowed = errors.New("method not allowed")
+	errInvalidUserID    = errors.New("user id must be a number")
+	errInternal         = errors.New("internal error")
+)
+
+// QueueUpdater applies changes of a queue in order with Telegram updates and re-renders its inline message.
+type QueueUpdater interface {
+	UpdateQueue(ctx context.Context, messageID string, change func(ctx context.Context) error) error
+}
+
+// Server serves these routes:
+//
+//	GET    /api/queues                                  queues the user administers
+//	GET    /api/queues/{id}                             the queue
+//	POST   /api/queues/{id}/start[?shuffle=true]        start the queue
+//	POST   /api/queues/{id}/next                        advance to the next person
+//	DELETE /api/queues/{id}/participants/{user id}      remove the participant
+//
+// Requests are authenticated with "Authorization: Bearer <token>" and made on behalf of the user the token belongs to.
+type Server struct {
+	u       usecase.Bot
+	updater QueueUpdater
+	// tokens maps API tokens to Telegram IDs of users they act for.
+	tokens map[string]int64
+}
+
+func NewServer(u usecase.Bot, updater QueueUpdater, tokens map[string]int64) *Server {
+	return &Server{u: u, updater: updater, tokens: tokens}
+}
+
+func (s *Server) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	userID, ok := s.authenticate(r)
+	if !ok {
+		writeError(rw, http.StatusUnauthorized, errUnauthorized)
+
+		return
+	}
+
+	rest, ok := strings.CutPrefix(r.URL.Path, QueuesPath)
+	if !ok {
+		writeError(rw, http.StatusNotFound, errNotFound)
+
+		return
+	}
+
+	var parts []string
+	if rest = strings.Trim(rest, "/"); rest != "" {
+		parts = strings.Split(rest, "/")
+	}
+
+	switch {
+	case len(parts) == 0:
+		s.handle(rw, r, http.MethodGet, func() (any, error) {
+			return s.listQueues(r.Context(), userID)
+		})
+	case len(parts) == 1:
+		s.handle(rw, r, http.MethodGet, func() (any, error) {
+			return s.getQueue(r.Context(), parts[0], userID)
+		})
+	case len(parts) == 2 && parts[1] == "start":
+		shuffle := r.URL.Query().Get("shuffle") == "true"
+		s.handle(rw, r, http.MethodPost, func() (any, error) {
+			return s.updateQueue(r.Context(), parts[0], userID, func(ctx context.Context) error {
+				return s.u.StartQueue(ctx, parts[0], userID, shuffle)
+			})
+		})
+	case len(parts) == 2 && parts[1] == "next":
+		s.handle(rw, r, http.MethodPost, func() (any, error) {
+			return s.updateQueue(r.Context(), parts[0], userID, func(ctx context.Context) error {
+				return s.u.SetNextPersonToQueue(ctx, parts[0], userID)
+			})
+		})
+	case len(parts) == 3 && parts[1] == "participants":
+		s.handle(rw, r, http.MethodDelete, func() (any, error) {
+			return s.removeParticipant(r.Context(), parts[0], userID, parts[2])
+		})
+	default:
+		writeError(rw, http.StatusNotFound, errNotFound)
+	}
+}
+
+// authenticate returns the user the bearer token of the request belongs to.
+func (s *Server) authenticate(r *http.Request) (int64, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return 0, false
+	}
+
+	// Every token is compared, so response time doesn't tell which one is close.
+	var userID int64
+
+	found := false
+
+	for knownToken, knownUserID := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(knownToken)) == 1 {
+			userID, found = knownUserID, true
+		}
+	}
+
+	return userID, found
+}
+
+// handle checks the method of the request, runs action and writes its result.
+func (s *Server) handle(rw http.ResponseWriter, r *http.Request, method string, action func() (any, error)) {
+	if r.Method != method {
+		rw.Header().Set("Allow", method)
+		writeError(rw, http.StatusMethodNotAllowed, errMethodNotAllowed)
+
+		return
+	}
+
+	result, err := action()
+	if err != nil {
+		status := errorStatus(err)
+		if status == http.StatusInternalServerError {
+			slog.Error("Couldn't handle API request", "method", r.Method, "path", r.URL.Path, "reason", err)
+			err = errInternal
+		}
+
+		writeError(rw, status, err)
+
+		return
+	}
+
+	writeJSON(rw, http.StatusOK, result)
+}
+
+func (s *Server) listQueues(ctx context.Context, userID int64) ([]queueResponse, error) {
+	queues, err := s.u.GetAdminQueues(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]queueResponse, 0, len(queues))
+	for _, queue := range queues {
+		response = append(response, newQueueResponse(queue))
+	}
+
+	return response, nil
+}
+
+// getQueue returns the queue if the user administers it.
+func (s *Server) getQueue(ctx context.Context, messageID string, userID int64) (queueResponse, error) {
+	if err := s.u.CheckAdmin(ctx, messageID, userID); err != nil {
+		return queueResponse{}, err
+	}
+
+	queue, err := s.u.GetQueue(ctx, messageID)
+	if err != nil {
+		return queueResponse{}, err
+	}
+
+	return newQueueResponse(queue), nil
+}
+
+func (s *Server) updateQueue(
+	ctx context.Context,
+	messageID string,
+	userID int64,
+	change func(ctx context.Context) error,
+) (queueResponse, error) {
+	if err := s.updater.UpdateQueue(ctx, messageID, change); err != nil {
+		return queueResponse{}, err
+	}
+
+	return s.getQueue(ctx, messageID, userID)
+}
+
+func (s *Server) removeParticipant(ctx context.Context, messageID string, adminID int64, rawUserID string) (queueResponse, error) {
+	userID, err := strconv.ParseInt(rawUserID, 10, 64)
+	if err != nil {
+		return queueResponse{}, errInvalidUserID
+	}
+
+	return s.updateQueue(ctx, messageID, adminID, func(ctx context.Context) error {
+		return s.u.RemoveParticipant(ctx, messageID, adminID, userID)
+	})
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, errInvalidUserID):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrNotAdmin), errors.Is(err, usecase.ErrNotOwner):
+		return http.StatusForbidden
+	case errors.Is(err, storage.ErrQueueNotFound), errors.Is(err, usecase.ErrNotParticipant):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrNoCurrentPerson), errors.Is(err, usecase.ErrHasSlots), errors.Is(err, usecase.ErrAlreadyStarted):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeError(rw http.ResponseWriter, status int, err error) {
+	writeJSON(rw, status, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(rw http.ResponseWriter, status int, body any) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+
+	if err := json.NewEncoder(rw).Encode(body); err != nil {
+		slog.Warn("Couldn't write API response", "reason", err)
+	}
+}
@@ -0,0 +1,268 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase"
+	"QueueBot/internal/usecase/storage/memory"
+)
+
+const (
+	adminToken = "admin-token"
+	userToken  = "user-token"
+	adminID    = 1
+	userID     = 2
+)
+
+// fakeUpdater applies changes right away and remembers re-rendered queues.
+type fakeUpdater struct {
+	rendered []string
+}
+
+func (f *fakeUpdater) UpdateQueue(ctx context.Context, messageID string, change func(ctx context.Context) error) error {
+	if err := change(ctx); err != nil {
+		return err
+	}
+
+	f.rendered = append(f.rendered, messageID)
+
+	return nil
+}
+
+func newTestServer(t *testing.T) (*Server, *fakeUpdater) {
+	t.Helper()
+
+	ctx := context.Background()
+	u := usecase.NewBotUseCase(memory.NewStorage(), nil)
+
+	require.NoError(t, u.CreateQueue(ctx, "queue", "Лаба 3", adminID, "ru", 0))
+	require.NoError(t, u.LogInOutToQueue(ctx, "queue", entity.User{ID: adminID, Name: "Admin"}))
+	require.NoError(t, u.LogInOutToQueue(ctx, "queue", entity.User{ID: userID, Name: "User"}))
+
+	updater := &fakeUpdater{}
+
+	return NewServer(u, updater, map[string]int64{adminToken: adminID, userToken: userID}), updater
+}
+
+func TestServer_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name         string
+		before       []string // paths posted by the admin before the request
+		method       string
+		path         string
+		token        string
+		wantCode     int
+		wantRendered bool
+		check        func(t *testing.T, body []byte)
+	}{
+		{
+			name:     "Without token",
+			method:   http.MethodGet,
+			path:     "/api/queues",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "Unknown token",
+			method:   http.MethodGet,
+			path:     "/api/queues",
+			token:    "unknown",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "List queues",
+			method:   http.MethodGet,
+			path:     "/api/queues",
+			token:    adminToken,
+			wantCode: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				var queues []queueResponse
+				require.NoError(t, json.Unmarshal(body, &queues))
+				require.Len(t, queues, 1)
+				assert.Equal(t, "Лаба 3", queues[0].Description)
+			},
+		},
+		{
+			name:     "List queues of not admin",
+			method:   http.MethodGet,
+			path:     "/api/queues",
+			token:    userToken,
+			wantCode: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				assert.JSONEq(t, "[]", string(body))
+			},
+		},
+		{
+			name:     "Get queue",
+			method:   http.MethodGet,
+			path:     "/api/queues/queue",
+			token:    adminToken,
+			wantCode: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				assert.JSONEq(t, `{
+					"message_id": "queue",
+					"description": "Лаба 3",
+					"participants": [{"id": 1, "name": "Admin"}, {"id": 2, "name": "User"}],
+					"current_index": 0,
+					"is_started": false,
+					"owner_id": 1,
+					"admin_ids": [],
+					"notify_count": 1,
+					"capacity": 0,
+					"waitlist": []
+				}`, string(body))
+			},
+		},
+		{
+			name:     "Get queue by not admin",
+			method:   http.MethodGet,
+			path:     "/api/queues/queue",
+			token:    userToken,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "Get unknown queue",
+			method:   http.MethodGet,
+			path:     "/api/queues/unknown",
+			token:    adminToken,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:         "Start queue",
+			method:       http.MethodPost,
+			path:         "/api/queues/queue/start",
+			token:        adminToken,
+			wantCode:     http.StatusOK,
+			wantRendered: true,
+			check: func(t *testing.T, body []byte) {
+				var queue queueResponse
+				require.NoError(t, json.Unmarshal(body, &queue))
+				assert.True(t, queue.IsStarted)
+			},
+		},
+		{
+			name:     "Start started queue",
+			before:   []string{"/api/queues/queue/start"},
+			method:   http.MethodPost,
+			path:     "/api/queues/queue/start",
+			token:    adminToken,
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:     "Start queue with wrong method",
+			method:   http.MethodGet,
+			path:     "/api/queues/queue/start",
+			token:    adminToken,
+			wantCode: http.StatusMethodNotAllowed,
+		},
+		{
+			name:         "Next person",
+			before:       []string{"/api/queues/queue/start"},
+			method:       http.MethodPost,
+			path:         "/api/queues/queue/next",
+			token:        adminToken,
+			wantCode:     http.StatusOK,
+			wantRendered: true,
+			check: func(t *testing.T, body []byte) {
+				var queue queueResponse
+				require.NoError(t, json.Unmarshal(body, &queue))
+				assert.Equal(t, 1, queue.CurrentIndex)
+			},
+		},
+		{
+			name:     "Next person in not started queue",
+			method:   http.MethodPost,
+			path:     "/api/queues/queue/next",
+			token:    adminToken,
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:     "Next person in passed queue",
+			before:   []string{"/api/queues/queue/start", "/api/queues/queue/next", "/api/queues/queue/next"},
+			method:   http.MethodPost,
+			path:     "/api/queues/queue/next",
+			token:    adminToken,
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:     "Next person by not admin",
+			method:   http.MethodPost,
+			path:     "/api/queues/queue/next",
+			token:    userToken,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:         "Remove participant",
+			method:       http.MethodDelete,
+			path:         "/api/queues/queue/participants/2",
+			token:        adminToken,
+			wantCode:     http.StatusOK,
+			wantRendered: true,
+			check: func(t *testing.T, body []byte) {
+				var queue queueResponse
+				require.NoError(t, json.Unmarshal(body, &queue))
+				assert.Equal(t, []participantResponse{{ID: adminID, Name: "Admin"}}, queue.Participants)
+			},
+		},
+		{
+			name:     "Remove unknown participant",
+			method:   http.MethodDelete,
+			path:     "/api/queues/queue/participants/3",
+			token:    adminToken,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Remove participant with invalid id",
+			method:   http.MethodDelete,
+			path:     "/api/queues/queue/participants/user",
+			token:    adminToken,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Unknown route",
+			method:   http.MethodPost,
+			path:     "/api/queues/queue/finish",
+			token:    adminToken,
+			wantCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, updater := newTestServer(t)
+
+			for _, path := range tt.before {
+				r := httptest.NewRequest(http.MethodPost, path, nil)
+				r.Header.Set("Authorization", "Bearer "+adminToken)
+
+				rw := httptest.NewRecorder()
+				server.ServeHTTP(rw, r)
+				require.Equal(t, http.StatusOK, rw.Code, rw.Body.String())
+			}
+
+			updater.rendered = nil
+
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			rw := httptest.NewRecorder()
+			server.ServeHTTP(rw, r)
+
+			assert.Equal(t, tt.wantCode, rw.Code, rw.Body.String())
+			assert.Equal(t, "application/json", rw.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantRendered, len(updater.rendered) == 1, "queue isn't re-rendered exactly once")
+
+			if tt.check != nil {
+				tt.check(t, rw.Body.Bytes())
+			}
+		})
+	}
+}
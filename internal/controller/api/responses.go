@@ -0,0 +1,60 @@
+package api
+
+import (
+	"QueueBot/internal/entity"
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+type participantResponse struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type queueResponse struct {
+	MessageID    string                `json:"message_id"`
+	Description  string                `json:"description"`
+	Participants []participantResponse `json:"participants"`
+	// CurrentIndex is the position of the current person in participants.
+	// It equals the number of participants when everyone has passed.
+	CurrentIndex int     `json:"current_index"`
+	IsStarted    bool    `json:"is_started"`
+	OwnerID      int64   `json:"owner_id"`
+	AdminIDs     []int64 `json:"admin_ids"`
+	NotifyCount  int     `json:"notify_count"`
+	// Capacity is zero when the queue is unlimited.
+	Capacity int                   `json:"capacity"`
+	Waitlist []participantResponse `json:"waitlist"`
+}
+
+func newQueueResponse(queue entity.Queue) queueResponse {
+	participants := make([]participantResponse, 0, len(queue.Users))
+	for _, user := range queue.Users {
+		participants = append(participants, participantResponse{ID: user.ID, Name: user.Name})
+	}
+
+	waitlist := make([]participantResponse, 0, len(queue.Waitlist))
+	for _, user := range queue.Waitlist {
+		waitlist = append(waitlist, participantResponse{ID: user.ID, Name: user.Name})
+	}
+
+	adminIDs := queue.AdminIDs
+	if adminIDs == nil {
+		adminIDs = []int64{}
+	}
+
+	return queueResponse{
+		MessageID:    queue.MessageID,
+		Description:  queue.Description,
+		Participants: participants,
+		CurrentIndex: queue.CurrentPersonIdx,
+		IsStarted:    queue.IsStarted,
+		OwnerID:      queue.OwnerID,
+		AdminIDs:     adminIDs,
+		NotifyCount:  queue.NotifyCount,
+		Capacity:     queue.Capacity,
+		Waitlist:     waitlist,
+	}
+}
@@ -0,0 +1,287 @@
+// Package dashboard serves read-only pages which show queues live, e.g. on a projector.
+package dashboard
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/i18n"
+	"QueueBot/internal/usecase"
+	"QueueBot/internal/usecase/storage"
+)
+
+// eventsSuffix ends the path of the stream of the dashboard.
+const eventsSuffix = "/events"
+
+// keepAliveInterval is how often an idle stream sends a comment, so proxies don't close it.
+const keepAliveInterval = 30 * time.Second
+
+//go:embed dashboard.html
+var pageTemplate string
+
+var page = template.Must(template.New("dashboard").Parse(pageTemplate))
+
+// Server serves these routes:
+//
+//	GET /dashboard/{token}         the page of the queue
+//	GET /dashboard/{token}/events  server-sent events with the state of the queue after every change
+//
+// Tokens are made by Links, nothing else is needed to view the queue.
+type Server struct {
+	u     usecase.Bot
+	hub   *Hub
+	links *Links
+}
+
+func NewServer(u usecase.Bot, hub *Hub, links *Links) *Server {
+	return &Server{u: u, hub: hub, links: links}
+}
+
+// state is what the dashboard shows.
+type state struct {
+	Description string `json:"description"`
+	IsStarted   bool   `json:"is_started"`
+	// Current is the name of the current person, or names of people at slots separated by commas.
+	// It is empty before the start and after the end of the queue.
+	Current string `json:"current"`
+	// Upcoming are names of people after the current one, or of everybody before the start.
+	Upcoming   []string `json:"upcoming"`
+	IsFinished bool     `json:"is_finished"`
+	// language of the queue, the page is shown in.
+	language i18n.Language
+}
+
+// pageTexts are texts the script of the page shows.
+func pageTexts(lang i18n.Language) map[string]string {
+	texts := make(map[string]string)
+	for name, key := range map[string]i18n.Key{
+		"reconnecting": i18n.DashboardReconnecting,
+		"notStarted":   i18n.DashboardNotStarted,
+		"joined":       i18n.DashboardJoined,
+		"current":      i18n.DashboardCurrent,
+		"next":         i18n.DashboardNext,
+		"allPassed":    i18n.DashboardAllPassed,
+		"finished":     i18n.DashboardFinished,
+	} {
+		texts[name] = lang.Text(key)
+	}
+
+	return texts
+}
+
+func newState(queue entity.Queue) state {
+	s := state{
+		Description: queue.Description,
+		IsStarted:   queue.IsStarted,
+		Upcoming:    []string{},
+		language:    i18n.Parse(queue.Language),
+	}
+
+	upcoming := queue.Users
+	switch {
+	case queue.IsStarted && queue.HasSlots():
+		s.Current = slotNames(queue.Slots)
+		upcoming = queue.Users[min(queue.CurrentPersonIdx, len(queue.Users)):]
+	case queue.IsStarted:
+		if queue.CurrentPersonIdx < len(queue.Users) {
+			s.Current = queue.Users[queue.CurrentPersonIdx].Name
+			upcoming = queue.Users[queue.CurrentPersonIdx+1:]
+		} else {
+			upcoming = nil
+		}
+	}
+
+	for _, user := range upcoming {
+		s.Upcoming = append(s.Upcoming, user.Name)
+	}
+
+	return s
+}
+
+// slotNames joins names of people at busy slots.
+func slotNames(slots []entity.User) string {
+	var names []string
+	for _, user := range slots {
+		if user.ID != 0 {
+			names = append(names, user.Name)
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func (s *Server) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		rw.Header().Set("Allow", http.MethodGet)
+		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	token, ok := strings.CutPrefix(r.URL.Path, Path)
+	if !ok {
+		http.NotFound(rw, r)
+
+		return
+	}
+
+	token, isStream := strings.CutSuffix(token, eventsSuffix)
+
+	messageID, ok := s.links.messageID(token)
+	if !ok {
+		http.NotFound(rw, r)
+
+		return
+	}
+
+	if isStream {
+		s.stream(rw, r, messageID)
+
+		return
+	}
+
+	s.page(rw, r, Path+token+eventsSuffix, messageID)
+}
+
+func (s *Server) page(rw http.ResponseWriter, r *http.Request, eventsPath string, messageID string) {
+	current, err := s.state(r.Context(), messageID)
+	if err != nil {
+		slog.Error("Couldn't get queue for dashboard", "messageId", messageID, "reason", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return
+	}
+
+	if current.IsFinished {
+		http.Error(rw, i18n.Default.Text(i18n.DashboardNotFound), http.StatusNotFound)
+
+		return
+	}
+
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err = page.Execute(rw, struct {
+		Language    i18n.Language
+		Description string
+		EventsPath  string
+		Connecting  string
+		Texts       map[string]string
+	}{
+		Language:    current.language,
+		Description: current.Description,
+		EventsPath:  eventsPath,
+		Connecting:  current.language.Text(i18n.DashboardConnecting),
+		Texts:       pageTexts(current.language),
+	}); err != nil {
+		slog.Warn("Couldn't write dashboard page", "reason", err)
+	}
+}
+
+// stream sends the state of the queue right away and after every change, until the queue is finished.
+func (s *Server) stream(rw http.ResponseWriter, r *http.Request, messageID string) {
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		http.Error(rw, "streaming is not supported", http.StatusInternalServerError)
+
+		return
+	}
+
+	// Subscribing before reading the state, so a change between them isn't missed.
+	changes, unsubscribe := s.hub.Subscribe(messageID)
+	defer unsubscribe()
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		current, err := s.state(r.Context(), messageID)
+		if err != nil {
+			slog.Error("Couldn't get queue for dashboard", "messageId", messageID, "reason", err)
+
+			return
+		}
+
+		if err = writeEvent(rw, current); err != nil {
+			slog.Debug("Dashboard viewer left", "messageId", messageID, "reason", err)
+
+			return
+		}
+
+		flusher.Flush()
+
+		if current.IsFinished {
+			return
+		}
+
+		if !s.waitForChange(r.Context(), rw, flusher, changes, keepAlive.C) {
+			return
+		}
+	}
+}
+
+// waitForChange keeps the stream alive until the queue changes. It reports false when the stream must end.
+func (s *Server) waitForChange(
+	ctx context.Context,
+	rw http.ResponseWriter,
+	flusher http.Flusher,
+	changes <-chan struct{},
+	keepAlive <-chan time.Time,
+) bool {
+	for {
+		select {
+		case <-changes:
+			return true
+		case <-keepAlive:
+			if _, err := fmt.Fprint(rw, ": keep-alive\n\n"); err != nil {
+				return false
+			}
+
+			flusher.Flush()
+		case <-ctx.Done():
+			return false
+		case <-s.hub.Done():
+			return false
+		}
+	}
+}
+
+// state returns the state of the queue. Finished queues are no longer stored, so they are reported as finished.
+func (s *Server) state(ctx context.Context, messageID string) (state, error) {
+	queue, err := s.u.GetQueue(ctx, messageID)
+
+	switch {
+	case errors.Is(err, storage.ErrQueueNotFound):
+		return state{Upcoming: []string{}, IsFinished: true}, nil
+	case err != nil:
+		return state{}, err
+	}
+
+	return newState(queue), nil
+}
+
+func writeEvent(rw http.ResponseWriter, current state) error {
+	data, err := json.Marshal(current)
+	if err != nil {
+		return fmt.Errorf("couldn't marshal dashboard state with error: %w", err)
+	}
+
+	if _, err = fmt.Fprintf(rw, "data: %s\n\n", data); err != nil {
+		return fmt.Errorf("couldn't write dashboard event with error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package dashboard
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/i18n"
+	"QueueBot/internal/usecase"
+	"QueueBot/internal/usecase/storage/memory"
+)
+
+const ownerID = 1
+
+func newTestServer(t *testing.T) (*Server, *usecase.BotUseCase, *Links) {
+	t.Helper()
+
+	ctx := context.Background()
+	hub := NewHub()
+	links := NewLinks("https://queue.example.com", "secret")
+
+	u := usecase.NewBotUseCase(memory.NewStorage(), nil)
+	u.Observer = hub
+
+	require.NoError(t, u.CreateQueue(ctx, "queue", "Лаба 3", ownerID, "ru", 0))
+	require.NoError(t, u.LogInOutToQueue(ctx, "queue", entity.User{ID: ownerID, Name: "Owner"}))
+	require.NoError(t, u.LogInOutToQueue(ctx, "queue", entity.User{ID: 2, Name: "<b>Second</b>"}))
+
+	t.Cleanup(hub.Close)
+
+	return NewServer(u, hub, links), u, links
+}
+
+func TestServer_Page(t *testing.T) {
+	server, _, links := newTestServer(t)
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		wantCode int
+	}{
+		{name: "Page", method: http.MethodGet, path: Path + links.token("queue"), wantCode: http.StatusOK},
+		{name: "Unknown queue", method: http.MethodGet, path: Path + links.token("unknown"), wantCode: http.StatusNotFound},
+		{name: "Invalid token", method: http.MethodGet, path: Path + "queue", wantCode: http.StatusNotFound},
+		{name: "Wrong method", method: http.MethodPost, path: Path + links.token("queue"), wantCode: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			server.ServeHTTP(rw, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantCode, rw.Code, rw.Body.String())
+
+			if tt.wantCode == http.StatusOK {
+				assert.Contains(t, rw.Body.String(), "<title>Лаба 3</title>")
+				assert.Contains(t, rw.Body.String(), links.token("queue"))
+				assert.Contains(t, rw.Body.String(), `<html lang="ru">`)
+				assert.Contains(t, rw.Body.String(), i18n.Russian.Text(i18n.DashboardConnecting))
+			}
+		})
+	}
+}
+
+func TestServer_Stream(t *testing.T) {
+	server, u, links := newTestServer(t)
+	ctx := context.Background()
+
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	resp, err := http.Get(httpServer.URL + Path + links.token("queue") + eventsSuffix)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	events := bufio.NewScanner(resp.Body)
+	next := func() state {
+		t.Helper()
+
+		for events.Scan() {
+			if data, ok := strings.CutPrefix(events.Text(), "data: "); ok {
+				var s state
+				require.NoError(t, json.Unmarshal([]byte(data), &s))
+
+				return s
+			}
+		}
+
+		require.NoError(t, events.Err())
+		require.Fail(t, "stream ended")
+
+		return state{}
+	}
+
+	assert.Equal(t, state{
+		Description: "Лаба 3",
+		Upcoming:    []string{"Owner", "<b>Second</b>"},
+	}, next())
+
+	require.NoError(t, u.StartQueue(ctx, "queue", ownerID, false))
+	assert.Equal(t, state{
+		Description: "Лаба 3",
+		IsStarted:   true,
+		Current:     "Owner",
+		Upcoming:    []string{"<b>Second</b>"},
+	}, next())
+
+	require.NoError(t, u.SetNextPersonToQueue(ctx, "queue", ownerID))
+	assert.Equal(t, "<b>Second</b>", next().Current)
+
+	require.NoError(t, u.FinishQueue(ctx, "queue", ownerID))
+	assert.Equal(t, state{Upcoming: []string{}, IsFinished: true}, next())
+
+	// The stream ends with the queue, only the end of the last event is left.
+	for events.Scan() {
+		assert.Empty(t, events.Text())
+	}
+}
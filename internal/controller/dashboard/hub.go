@@ -0,0 +1,71 @@
+package dashboard
+
+import "sync"
+
+// Hub tells dashboards that their queue has changed. It is a usecase.Observer.
+type Hub struct {
+	mu sync.Mutex
+	// subscribers by inline message ID of the queue.
+	subscribers map[string]map[chan struct{}]struct{}
+	closed      chan struct{}
+	once        sync.Once
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[string]map[chan struct{}]struct{}),
+		closed:      make(chan struct{}),
+	}
+}
+
+// QueueChanged wakes up subscribers of the queue without waiting for them.
+func (h *Hub) QueueChanged(messageID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for changes := range h.subscribers[messageID] {
+		select {
+		case changes <- struct{}{}:
+		default:
+			// The subscriber hasn't read the previous change yet, it will read the latest state anyway.
+		}
+	}
+}
+
+// Subscribe returns a channel which receives a value after the queue changes.
+// Changes made while the subscriber is busy are merged into one. Unsubscribe must be called when done.
+func (h *Hub) Subscribe(messageID string) (changes <-chan struct{}, unsubscribe func()) {
+	ch := make(chan struct{}, 1)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.subscribers[messageID] == nil {
+		h.subscribers[messageID] = make(map[chan struct{}]struct{})
+	}
+
+	h.subscribers[messageID][ch] = struct{}{}
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		delete(h.subscribers[messageID], ch)
+
+		if len(h.subscribers[messageID]) == 0 {
+			delete(h.subscribers, messageID)
+		}
+	}
+}
+
+// Close ends all streams, so the server can shut down without waiting for viewers to leave.
+func (h *Hub) Close() {
+	h.once.Do(func() {
+		close(h.closed)
+	})
+}
+
+// Done is closed by Close.
+func (h *Hub) Done() <-chan struct{} {
+	return h.closed
+}
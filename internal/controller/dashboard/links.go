@@ -0,0 +1,66 @@
+package dashboard
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+)
+
+// Path is the prefix of dashboard routes.
+const Path = "/dashboard/"
+
+// signatureSize is how many bytes of HMAC-SHA256 are kept in links.
+const signatureSize = 16
+
+// tokenSeparator splits the encoded message ID and its signature in a token.
+const tokenSeparator = "."
+
+// Links makes read-only links to dashboards of queues. Links are signed, so nobody can guess the link of another queue.
+type Links struct {
+	baseURL string
+	secret  []byte
+}
+
+// NewLinks makes links starting with baseURL, which is the public address of the dashboard server.
+func NewLinks(baseURL string, secret string) *Links {
+	return &Links{baseURL: strings.TrimSuffix(baseURL, "/"), secret: []byte(secret)}
+}
+
+// Link returns the link to the dashboard of the queue.
+func (l *Links) Link(messageID string) string {
+	return l.baseURL + Path + l.token(messageID)
+}
+
+func (l *Links) token(messageID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(messageID)) +
+		tokenSeparator +
+		base64.RawURLEncoding.EncodeToString(l.sign(messageID))
+}
+
+// messageID returns the inline message ID of the queue the token was made for, if the token is valid.
+func (l *Links) messageID(token string) (string, bool) {
+	encodedID, encodedSignature, ok := strings.Cut(token, tokenSeparator)
+	if !ok {
+		return "", false
+	}
+
+	messageID, err := base64.RawURLEncoding.DecodeString(encodedID)
+	if err != nil {
+		return "", false
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, l.sign(string(messageID))) {
+		return "", false
+	}
+
+	return string(messageID), true
+}
+
+func (l *Links) sign(messageID string) []byte {
+	mac := hmac.New(sha256.New, l.secret)
+	mac.Write([]byte(messageID))
+
+	return mac.Sum(nil)[:signatureSize]
+}
@@ -0,0 +1,45 @@
+package dashboard
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinks_Link(t *testing.T) {
+	links := NewLinks("https://queue.example.com/", "secret")
+
+	link := links.Link("AgAAAF8uAQBX")
+	require.True(t, strings.HasPrefix(link, "https://queue.example.com/dashboard/"), link)
+
+	messageID, ok := links.messageID(strings.TrimPrefix(link, "https://queue.example.com"+Path))
+	require.True(t, ok)
+	assert.Equal(t, "AgAAAF8uAQBX", messageID)
+}
+
+func TestLinks_messageID(t *testing.T) {
+	links := NewLinks("https://queue.example.com", "secret")
+	token := links.token("queue")
+	encodedID, _, _ := strings.Cut(token, tokenSeparator)
+
+	tests := []struct {
+		name  string
+		token string
+		want  bool
+	}{
+		{name: "Valid token", token: token, want: true},
+		{name: "Without signature", token: encodedID, want: false},
+		{name: "Other queue", token: links.token("other")[:len(encodedID)] + token[len(encodedID):], want: false},
+		{name: "Other secret", token: NewLinks("https://queue.example.com", "other").token("queue"), want: false},
+		{name: "Not base64", token: "queue.signature!", want: false},
+		{name: "Empty", token: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := links.messageID(tt.token)
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+// Package scheduler starts scheduled queues, advances queues with timed turns when the time of the current person is over
+// and asks people close to their turn to confirm their presence.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"QueueBot/internal/usecase"
+)
+
+// DefaultInterval is how often timed queues are checked.
+const DefaultInterval = 10 * time.Second
+
+// Clock returns the current time. Tests pass a fake one.
+type Clock func() time.Time
+
+// QueueUpdater applies changes of a queue in order with Telegram updates and re-renders its inline message.
+type QueueUpdater interface {
+	UpdateQueue(ctx context.Context, messageID string, change func(ctx context.Context) error) error
+}
+
+// Scheduler checks scheduled, timed and check-in queues every interval. All are kept in storage, so they survive restarts.
+type Scheduler struct {
+	u        usecase.Bot
+	updater  QueueUpdater
+	now      Clock
+	interval time.Duration
+	// shutdownTimeout is how long the running check may finish after ctx of Run is done.
+	shutdownTimeout time.Duration
+}
+
+func NewScheduler(u usecase.Bot, updater QueueUpdater, now Clock, interval time.Duration, shutdownTimeout time.Duration) *Scheduler {
+	return &Scheduler{u: u, updater: updater, now: now, interval: interval, shutdownTimeout: shutdownTimeout}
+}
+
+// Run checks queues until ctx is done and returns once the running check has returned.
+func (s *Scheduler) Run(ctx context.Context) {
+	// Checks aren't cancelled with ctx, so a shutdown doesn't leave a queue changed halfway.
+	checkCtx, cancelChecks := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelChecks()
+
+	context.AfterFunc(ctx, func() {
+		time.AfterFunc(s.shutdownTimeout, cancelChecks)
+	})
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.tick(checkCtx)
+		}
+	}
+}
+
+// tick starts due queues, then checks every timed queue and every queue with check-in once.
+// Started queues go first, so their first turn is timed and their first people are asked right away.
+// Errors are only logged, so one broken queue doesn't stop the others.
+func (s *Scheduler) tick(ctx context.Context) {
+	now := s.now()
+
+	s.startDueQueues(ctx, now)
+	s.checkTimedQueues(ctx, now)
+	s.checkCheckInQueues(ctx, now)
+}
+
+func (s *Scheduler) checkTimedQueues(ctx context.Context, now time.Time) {
+	messageIDs, err := s.u.GetTimedQueueIDs(ctx)
+	if err != nil {
+		slog.Warn("Couldn't get timed queues", "reason", err)
+
+		return
+	}
+
+	for _, messageID := range messageIDs {
+		if err = s.checkTurn(ctx, messageID, now); err != nil {
+			slog.Warn("Couldn't check turn", "messageId", messageID, "reason", err)
+		}
+	}
+}
+
+func (s *Scheduler) checkTurn(ctx context.Context, messageID string, now time.Time) error {
+	isDue, err := s.u.CheckTurn(ctx, messageID, now)
+	if err != nil {
+		return fmt.Errorf("couldn't check turn with error: %w", err)
+	}
+
+	if !isDue {
+		return nil
+	}
+
+	// The turn is changed in order with presses of the queue buttons, so it's never stored for a stale person.
+	err = s.updater.UpdateQueue(ctx, messageID, func(ctx context.Context) error {
+		return s.u.UpdateTurn(ctx, messageID, now)
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't update turn with error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Scheduler) checkCheckInQueues(ctx context.Context, now time.Time) {
+	messageIDs, err := s.u.GetCheckInQueueIDs(ctx)
+	if err != nil {
+		slog.Warn("Couldn't get check-in queues", "reason", err)
+
+		return
+	}
+
+	for _, messageID := range messageIDs {
+		if err = s.checkIn(ctx, messageID, now); err != nil {
+			slog.Warn("Couldn't check presence", "messageId", messageID, "reason", err)
+		}
+	}
+}
+
+func (s *Scheduler) checkIn(ctx context.Context, messageID string, now time.Time) error {
+	isDue, err := s.u.IsCheckInDue(ctx, messageID, now)
+	if err != nil {
+		return fmt.Errorf("couldn't check presence with error: %w", err)
+	}
+
+	if !isDue {
+		return nil
+	}
+
+	err = s.updater.UpdateQueue(ctx, messageID, func(ctx context.Context) error {
+		return s.u.UpdateCheckIns(ctx, messageID, now)
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't update check-ins with error: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Scheduler) startDueQueues(ctx context.Context, now time.Time) {
+	messageIDs, err := s.u.GetDueQueueIDs(ctx, now)
+	if err != nil {
+		slog.Warn("Couldn't get due queues", "reason", err)
+
+		return
+	}
+
+	for _, messageID := range messageIDs {
+		err = s.updater.UpdateQueue(ctx, messageID, func(ctx context.Context) error {
+			return s.u.StartScheduledQueue(ctx, messageID, now)
+		})
+		if err != nil {
+			slog.Warn("Couldn't start scheduled queue", "messageId", messageID, "reason", err)
+
+			continue
+		}
+
+		slog.Info("Started scheduled queue", "messageId", messageID)
+	}
+}
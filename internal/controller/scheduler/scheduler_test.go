@@ -0,0 +1,175 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase"
+	"QueueBot/internal/usecase/storage/memory"
+)
+
+// fakeUpdater applies changes right away and remembers queues it would re-render.
+type fakeUpdater struct {
+	rendered []string
+}
+
+func (u *fakeUpdater) UpdateQueue(ctx context.Context, messageID string, change func(ctx context.Context) error) error {
+	if err := change(ctx); err != nil {
+		return err
+	}
+
+	u.rendered = append(u.rendered, messageID)
+
+	return nil
+}
+
+func TestScheduler_AdvancesTimedQueues(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStorage()
+	u := usecase.NewBotUseCase(s, nil)
+
+	const owner = int64(1)
+
+	for _, messageID := range []string{"timed", "untimed"} {
+		require.NoError(t, u.CreateQueue(ctx, messageID, "Test", owner, "ru", 0))
+		require.NoError(t, u.LogInOutToQueue(ctx, messageID, entity.User{ID: 1}))
+		require.NoError(t, u.LogInOutToQueue(ctx, messageID, entity.User{ID: 2}))
+	}
+
+	require.NoError(t, u.SwitchTurnDuration(ctx, "timed", owner))
+	require.NoError(t, u.StartQueue(ctx, "timed", owner, false))
+	require.NoError(t, u.StartQueue(ctx, "untimed", owner, false))
+
+	now := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	updater := &fakeUpdater{}
+
+	start := now
+
+	NewScheduler(u, updater, clock, DefaultInterval, time.Second).tick(ctx)
+	assert.Equal(t, []string{"timed"}, updater.rendered, "the first turn isn't timed")
+
+	now = start.Add(usecase.TurnDurations[1] - usecase.TurnWarning - time.Second)
+	NewScheduler(u, updater, clock, DefaultInterval, time.Second).tick(ctx)
+	assert.Len(t, updater.rendered, 1)
+
+	// A new scheduler, e.g. after a restart, continues the stored turn.
+	now = start.Add(usecase.TurnDurations[1])
+	NewScheduler(u, updater, clock, DefaultInterval, time.Second).tick(ctx)
+	assert.Equal(t, []string{"timed", "timed"}, updater.rendered)
+
+	for messageID, want := range map[string]int{"timed": 1, "untimed": 0} {
+		queue, err := u.GetQueue(ctx, messageID)
+		require.NoError(t, err)
+		assert.Equal(t, want, queue.CurrentPersonIdx, messageID)
+	}
+}
+
+func TestScheduler_StartsDueQueues(t *testing.T) {
+	ctx := context.Background()
+	u := usecase.NewBotUseCase(memory.NewStorage(), nil)
+
+	const owner = int64(1)
+
+	startsAt := time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", owner, "ru", 0))
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: 1}))
+	require.NoError(t, u.ScheduleQueue(ctx, "123", owner, entity.Schedule{StartsAt: startsAt}))
+	require.NoError(t, u.SwitchTurnDuration(ctx, "123", owner))
+
+	now := startsAt.Add(-time.Second)
+	updater := &fakeUpdater{}
+	scheduler := NewScheduler(u, updater, func() time.Time { return now }, DefaultInterval, time.Second)
+
+	scheduler.tick(ctx)
+	assert.Empty(t, updater.rendered)
+
+	// The queue is started, then its first turn is timed in the same tick.
+	now = startsAt
+	scheduler.tick(ctx)
+	scheduler.tick(ctx)
+	assert.Equal(t, []string{"123", "123"}, updater.rendered)
+
+	queue, err := u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.True(t, queue.IsStarted)
+	assert.Equal(t, entity.Turn{UserID: 1, StartedAt: startsAt}, queue.Turn)
+}
+
+func TestScheduler_MovesAbsentPeople(t *testing.T) {
+	ctx := context.Background()
+	u := usecase.NewBotUseCase(memory.NewStorage(), nil)
+
+	const owner = int64(1)
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", owner, "ru", 0))
+
+	for id := int64(1); id <= 3; id++ {
+		require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: id}))
+	}
+
+	require.NoError(t, u.SwitchCheckInTimeout(ctx, "123", owner))
+	require.NoError(t, u.StartQueue(ctx, "123", owner, false))
+
+	now := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	updater := &fakeUpdater{}
+	scheduler := NewScheduler(u, updater, func() time.Time { return now }, DefaultInterval, time.Second)
+
+	// The current person and the next one are asked.
+	scheduler.tick(ctx)
+	scheduler.tick(ctx)
+	assert.Equal(t, []string{"123"}, updater.rendered)
+	require.NoError(t, u.ConfirmCheckIn(ctx, "123", 1))
+
+	now = now.Add(usecase.CheckInTimeouts[1])
+	scheduler.tick(ctx)
+	assert.Equal(t, []string{"123", "123"}, updater.rendered)
+
+	queue, err := u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 3, 2}, []int64{queue.Users[0].ID, queue.Users[1].ID, queue.Users[2].ID})
+	assert.Equal(t, entity.CheckIn{AskedAt: now}, queue.CheckIns[3])
+}
+
+// cancellingUpdater cancels the context of Run while the change is being applied.
+type cancellingUpdater struct {
+	cancel context.CancelFunc
+	errs   []error
+}
+
+func (u *cancellingUpdater) UpdateQueue(ctx context.Context, _ string, change func(ctx context.Context) error) error {
+	u.cancel()
+	u.errs = append(u.errs, ctx.Err())
+
+	return change(ctx)
+}
+
+func TestScheduler_RunFinishesChangeOnShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	u := usecase.NewBotUseCase(memory.NewStorage(), nil)
+
+	const owner = int64(1)
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", owner, "ru", 0))
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: 1}))
+	require.NoError(t, u.SwitchTurnDuration(ctx, "123", owner))
+	require.NoError(t, u.StartQueue(ctx, "123", owner, false))
+
+	now := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	updater := &cancellingUpdater{cancel: cancel}
+
+	// Run returns only after the change interrupted by the shutdown is applied.
+	NewScheduler(u, updater, func() time.Time { return now }, time.Millisecond, time.Second).Run(ctx)
+
+	assert.Equal(t, []error{nil}, updater.errs)
+
+	queue, err := u.GetQueue(context.Background(), "123")
+	require.NoError(t, err)
+	assert.Equal(t, entity.Turn{UserID: 1, StartedAt: now}, queue.Turn)
+}
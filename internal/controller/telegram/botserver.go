@@ -1,46 +1,150 @@
 package telegram
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"QueueBot/internal/controller/telegram/client"
+	"QueueBot/internal/metrics"
 )
 
 type BotServer struct {
-	bot *client.TelegramBot
+	bot             *client.TelegramBot
+	dispatcher      *Dispatcher
+	shutdownTimeout time.Duration
 }
 
-func NewBotServer(bot *client.TelegramBot) *BotServer {
-	return &BotServer{bot: bot}
+func NewBotServer(bot *client.TelegramBot, shutdownTimeout time.Duration) *BotServer {
+	return &BotServer{bot: bot, dispatcher: NewDispatcher(), shutdownTimeout: shutdownTimeout}
 }
 
-func (s BotServer) Listen(config tgbotapi.UpdateConfig, errChan chan<- error) {
-	updates := s.bot.TgBot.GetUpdatesChan(config)
+// Listen handles updates from source until ctx is done or the update channel is closed.
+// Updates of the same inline message are handled one after another, so the message is never rendered from stale state.
+// Handlers which are running when receiving stops get shutdownTimeout to finish, then their context is cancelled.
+// Listen returns only after all handlers have returned.
+func (s BotServer) Listen(ctx context.Context, source UpdateSource, errChan chan<- error) {
+	// Handlers aren't cancelled with ctx, so a shutdown doesn't interrupt them in the middle of a transaction.
+	handlerCtx, cancelHandlers := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelHandlers()
+
+	updates, err := source.Start(ctx, errChan)
+	if err != nil {
+		errChan <- fmt.Errorf("couldn't start receiving updates: %w", err)
+
+		return
+	}
+
 	slog.Info("Started listening update channel")
 
 	for update := range updates {
-		go func(update tgbotapi.Update) {
-			switch {
-			case update.Message != nil:
-				if err := s.HandleMessage(update.Message); err != nil {
-					errChan <- fmt.Errorf("couldn't handle message: %w", err)
-				}
-			case update.CallbackQuery != nil:
-				if err := s.HandleCallbackQuery(update.CallbackQuery); err != nil {
-					errChan <- fmt.Errorf("couldn't handle callback query: %w", err)
-				}
-			case update.InlineQuery != nil:
-				if err := s.HandleInlineQuery(update.InlineQuery); err != nil {
-					errChan <- fmt.Errorf("couldn't handle inline query: %w", err)
-				}
-			case update.ChosenInlineResult != nil:
-				if err := s.HandleChosenInlineResult(update.ChosenInlineResult); err != nil {
-					errChan <- fmt.Errorf("couldn't handle chosen inline result: %w", err)
-				}
-			}
-		}(update)
+		update := update
+		metrics.Updates.WithLabelValues(updateType(update)).Inc()
+
+		err = s.dispatcher.Dispatch(updateKey(update), func() {
+			s.handleUpdate(handlerCtx, update, errChan)
+		})
+		if err != nil {
+			errChan <- fmt.Errorf("couldn't dispatch update %d: %w", update.UpdateID, err)
+		}
+	}
+
+	s.drain(cancelHandlers)
+}
+
+// drain waits for running handlers. If they don't finish in shutdownTimeout, they are cancelled.
+// Changes through UpdateQueue are rejected from now on, so nothing is dispatched while waiting.
+func (s BotServer) drain(cancelHandlers context.CancelFunc) {
+	s.dispatcher.Close()
+
+	done := make(chan struct{})
+
+	go func() {
+		s.dispatcher.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(s.shutdownTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		slog.Warn("Handlers didn't finish in time, cancelling them", "timeout", s.shutdownTimeout.String())
+		cancelHandlers()
+		<-done
+	}
+
+	slog.Info("Stopped listening update channel")
+}
+
+// UpdateQueue applies change to the queue in order with Telegram updates of its inline message and re-renders the message.
+func (s BotServer) UpdateQueue(ctx context.Context, messageID string, change func(ctx context.Context) error) error {
+	done := make(chan error, 1)
+
+	err := s.dispatcher.Dispatch(messageID, func() {
+		if err := change(ctx); err != nil {
+			done <- err
+
+			return
+		}
+
+		done <- s.bot.RenderQueue(ctx, messageID)
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't update queue %s: %w", messageID, err)
+	}
+
+	return <-done
+}
+
+// updateKey is the inline message the update changes. Updates without one are handled in parallel.
+func updateKey(update tgbotapi.Update) string {
+	switch {
+	case update.CallbackQuery != nil:
+		return update.CallbackQuery.InlineMessageID
+	case update.ChosenInlineResult != nil:
+		return update.ChosenInlineResult.InlineMessageID
+	default:
+		return ""
+	}
+}
+
+func updateType(update tgbotapi.Update) string {
+	switch {
+	case update.Message != nil:
+		return metrics.UpdateMessage
+	case update.CallbackQuery != nil:
+		return metrics.UpdateCallbackQuery
+	case update.InlineQuery != nil:
+		return metrics.UpdateInlineQuery
+	case update.ChosenInlineResult != nil:
+		return metrics.UpdateChosenInlineResult
+	default:
+		return metrics.UpdateOther
+	}
+}
+
+func (s BotServer) handleUpdate(ctx context.Context, update tgbotapi.Update, errChan chan<- error) {
+	switch {
+	case update.Message != nil:
+		if err := s.HandleMessage(ctx, update.Message); err != nil {
+			errChan <- fmt.Errorf("couldn't handle message: %w", err)
+		}
+	case update.CallbackQuery != nil:
+		if err := s.HandleCallbackQuery(ctx, update.CallbackQuery); err != nil {
+			errChan <- fmt.Errorf("couldn't handle callback query: %w", err)
+		}
+	case update.InlineQuery != nil:
+		if err := s.HandleInlineQuery(ctx, update.InlineQuery); err != nil {
+			errChan <- fmt.Errorf("couldn't handle inline query: %w", err)
+		}
+	case update.ChosenInlineResult != nil:
+		if err := s.HandleChosenInlineResult(ctx, update.ChosenInlineResult); err != nil {
+			errChan <- fmt.Errorf("couldn't handle chosen inline result: %w", err)
+		}
 	}
 }
@@ -0,0 +1,197 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"QueueBot/internal/controller/telegram/client"
+	"QueueBot/internal/entity"
+	"QueueBot/internal/i18n"
+	"QueueBot/internal/usecase"
+	"QueueBot/internal/usecase/storage"
+	"QueueBot/internal/usecase/storage/memory"
+)
+
+func TestBotServer_Listen_ConcurrentCallbacks(t *testing.T) {
+	const participants = 30
+
+	ctx := context.Background()
+	s := memory.NewStorage()
+	server, fake, edits := newTestServer(t, s)
+
+	messageIDs := []string{"first", "second", "third"}
+	for _, messageID := range messageIDs {
+		require.NoError(t, s.CreateQueue(ctx, messageID, "Queue "+messageID, 1, "ru", 0))
+	}
+
+	errs := listen(server, func(updates chan<- tgbotapi.Update) {
+		for userID := int64(1); userID <= participants; userID++ {
+			for _, messageID := range messageIDs {
+				updates <- callbackUpdate(messageID, userID, client.LogInOurOutData)
+			}
+		}
+
+		// The last participant leaves right after joining.
+		for _, messageID := range messageIDs {
+			updates <- callbackUpdate(messageID, participants, client.LogInOurOutData)
+		}
+	})
+	assert.Empty(t, errs)
+
+	edits.Close()
+
+	for _, messageID := range messageIDs {
+		queue, err := s.GetQueue(ctx, messageID)
+		require.NoError(t, err)
+
+		require.Len(t, queue.Users, participants-1)
+
+		for i, user := range queue.Users {
+			assert.Equal(t, int64(i+1), user.ID, fmt.Sprintf("participants of %s are out of order", messageID))
+		}
+
+		// Edits are coalesced, but the last one always shows the final state.
+		assert.Equal(t, renderedUsers(queue, participants-1), fake.lastEdit(messageID), fmt.Sprintf("%s shows stale list", messageID))
+		assert.Less(t, len(fake.edits[messageID]), participants+1, fmt.Sprintf("edits of %s aren't coalesced", messageID))
+	}
+}
+
+// renderedUsers renders the queue menu with first n participants.
+func renderedUsers(queue entity.Queue, n int) string {
+	users := make([]entity.User, 0, n)
+	for id := int64(1); id <= int64(n); id++ {
+		users = append(users, entity.New(id, "", "User"))
+	}
+
+	queue.Users = users
+
+	return client.GetUpdatedQueueMessage(client.QueueLanguage(queue), queue.MessageID, queue).Text
+}
+
+// slowStorage holds joining the queue for delay or until the context is cancelled.
+type slowStorage struct {
+	storage.Storage
+	delay   time.Duration
+	started chan struct{}
+}
+
+func (s slowStorage) LogInOutToQueue(ctx context.Context, messageID string, user entity.User) error {
+	close(s.started)
+
+	select {
+	case <-time.After(s.delay):
+		return s.Storage.LogInOutToQueue(ctx, messageID, user)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestBotServer_Listen_Shutdown(t *testing.T) {
+	tests := []struct {
+		name       string
+		delay      time.Duration
+		wantJoined bool
+	}{
+		{
+			name:       "Running handler is drained",
+			delay:      20 * time.Millisecond,
+			wantJoined: true,
+		},
+		{
+			name:       "Handler is cancelled after timeout",
+			delay:      time.Hour,
+			wantJoined: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := memory.NewStorage()
+			require.NoError(t, s.CreateQueue(context.Background(), "queue", "Queue", 1, "ru", 0))
+
+			slow := slowStorage{Storage: s, delay: tt.delay, started: make(chan struct{})}
+			server, _, _ := newTestServer(t, slow)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			updates := make(channelSource)
+			errChan := make(chan error, 1)
+
+			go func() {
+				updates <- callbackUpdate("queue", 2, client.LogInOurOutData)
+				<-slow.started
+
+				// The source closes its channel once shutdown begins.
+				cancel()
+				close(updates)
+			}()
+
+			server.Listen(ctx, updates, errChan)
+
+			queue, err := s.GetQueue(context.Background(), "queue")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantJoined, len(queue.Users) == 1)
+
+			// Changes from the API and the scheduler are rejected once shutdown has started.
+			err = server.UpdateQueue(context.Background(), "queue", func(context.Context) error {
+				return nil
+			})
+			assert.ErrorIs(t, err, ErrDispatcherClosed)
+		})
+	}
+}
+
+func TestBotServer_UpdateQueue(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStorage()
+	server, fake, edits := newTestServer(t, s)
+	u := usecase.NewBotUseCase(s, nil)
+
+	require.NoError(t, u.CreateQueue(ctx, "queue", "Queue", 1, "ru", 0))
+	require.NoError(t, u.LogInOutToQueue(ctx, "queue", entity.New(1, "", "User")))
+
+	err := server.UpdateQueue(ctx, "queue", func(ctx context.Context) error {
+		return u.SetNextPersonToQueue(ctx, "queue", 2)
+	})
+	assert.ErrorIs(t, err, usecase.ErrNotAdmin)
+
+	require.NoError(t, server.UpdateQueue(ctx, "queue", func(ctx context.Context) error {
+		return u.StartQueue(ctx, "queue", 1, false)
+	}))
+
+	edits.Close()
+
+	queue, err := s.GetQueue(ctx, "queue")
+	require.NoError(t, err)
+
+	want := client.GetQueueAfterStartMessage(client.QueueLanguage(queue), queue.MessageID, queue).Text
+	assert.Equal(t, []string{want}, fake.edits["queue"], "queue isn't re-rendered only after successful change")
+}
+
+func TestBotServer_SwitchLanguage(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStorage()
+	server, fake, edits := newTestServer(t, s)
+
+	require.NoError(t, s.CreateQueue(ctx, "queue", "Queue", 1, string(i18n.Russian), 0))
+
+	errs := listen(server, func(updates chan<- tgbotapi.Update) {
+		// Only admins switch the language.
+		updates <- callbackUpdate("queue", 2, client.LanguageData)
+		updates <- callbackUpdate("queue", 1, client.LanguageData)
+	})
+	assert.Empty(t, errs)
+
+	edits.Close()
+
+	queue, err := s.GetQueue(ctx, "queue")
+	require.NoError(t, err)
+	assert.Equal(t, string(i18n.English), queue.Language)
+
+	want := client.GetQueueMessage(i18n.English, "queue", queue).Text
+	assert.Equal(t, []string{want}, fake.edits["queue"])
+}
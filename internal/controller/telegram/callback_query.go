@@ -2,58 +2,274 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"QueueBot/internal/controller/telegram/client"
+	"QueueBot/internal/i18n"
+	"QueueBot/internal/metrics"
+	"QueueBot/internal/usecase"
 )
 
-const (
-	ActionCompleted = "Действие выполнено!"
-	ActionError     = "Произошла ошибка"
-)
+// callbackHandler processes callback query of one action. Arg is the part of callback data after the action.
+type callbackHandler func(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery, arg string) error
 
-func (s BotServer) handleCallbackData(callbackQuery *tgbotapi.CallbackQuery) error {
-	switch callbackQuery.Data {
-	case client.LogInOurOutData:
-		if err := s.bot.LogInOurOut(context.Background(), callbackQuery); err != nil {
-			return fmt.Errorf("couldn't login or logout with error: %w", err)
-		}
-	case client.StartQueueData:
-		if err := s.bot.Start(context.Background(), callbackQuery, false); err != nil {
-			return fmt.Errorf("couldn't start queue with error: %w", err)
-		}
-	case client.StartQueueShuffleData:
-		if err := s.bot.Start(context.Background(), callbackQuery, true); err != nil {
-			return fmt.Errorf("couldn't start queue with shuffle with error: %w", err)
-		}
-	case client.NextData:
-		if err := s.bot.Next(context.Background(), callbackQuery); err != nil {
-			return fmt.Errorf("couldn't go to next person with error: %w", err)
-		}
-	case client.GoToMenuData:
-		if err := s.bot.GoToMenu(context.Background(), callbackQuery); err != nil {
-			return fmt.Errorf("couldn't go to menu with error: %w", err)
+func (s BotServer) callbackHandlers() map[string]callbackHandler {
+	return map[string]callbackHandler{
+		client.LogInOurOutData: func(ctx context.Context, cq *tgbotapi.CallbackQuery, _ string) error {
+			if err := s.bot.LogInOurOut(ctx, cq); err != nil {
+				return fmt.Errorf("couldn't login or logout with error: %w", err)
+			}
+
+			return nil
+		},
+		client.StartQueueData: func(ctx context.Context, cq *tgbotapi.CallbackQuery, _ string) error {
+			if err := s.bot.Start(ctx, cq, false); err != nil {
+				return fmt.Errorf("couldn't start queue with error: %w", err)
+			}
+
+			return nil
+		},
+		client.StartQueueShuffleData: func(ctx context.Context, cq *tgbotapi.CallbackQuery, _ string) error {
+			if err := s.bot.Start(ctx, cq, true); err != nil {
+				return fmt.Errorf("couldn't start queue with shuffle with error: %w", err)
+			}
+
+			return nil
+		},
+		client.NextData: func(ctx context.Context, cq *tgbotapi.CallbackQuery, _ string) error {
+			if err := s.bot.Next(ctx, cq); err != nil {
+				return fmt.Errorf("couldn't go to next person with error: %w", err)
+			}
+
+			return nil
+		},
+		client.PreviousData: func(ctx context.Context, cq *tgbotapi.CallbackQuery, _ string) error {
+			if err := s.bot.Previous(ctx, cq); err != nil {
+				return fmt.Errorf("couldn't go to previous person with error: %w", err)
+			}
+
+			return nil
+		},
+		client.SkipData: s.handleSkip,
+		client.UndoData: func(ctx context.Context, cq *tgbotapi.CallbackQuery, _ string) error {
+			if err := s.bot.Undo(ctx, cq); err != nil {
+				return fmt.Errorf("couldn't undo last action with error: %w", err)
+			}
+
+			return nil
+		},
+		client.GoToMenuData: func(ctx context.Context, cq *tgbotapi.CallbackQuery, _ string) error {
+			if err := s.bot.GoToMenu(ctx, cq); err != nil {
+				return fmt.Errorf("couldn't go to menu with error: %w", err)
+			}
+
+			return nil
+		},
+		client.ShowMenuData: func(ctx context.Context, cq *tgbotapi.CallbackQuery, _ string) error {
+			if err := s.bot.ShowMenu(ctx, cq); err != nil {
+				return fmt.Errorf("couldn't show menu with error: %w", err)
+			}
+
+			return nil
+		},
+		client.FinishQueueData: func(ctx context.Context, cq *tgbotapi.CallbackQuery, _ string) error {
+			if err := s.bot.FinishQueue(ctx, cq); err != nil {
+				return fmt.Errorf("couldn't finish queue with error: %w", err)
+			}
+
+			return nil
+		},
+		client.ManageAdminsData: func(ctx context.Context, cq *tgbotapi.CallbackQuery, _ string) error {
+			if err := s.bot.ManageAdmins(ctx, cq); err != nil {
+				return fmt.Errorf("couldn't open admins menu with error: %w", err)
+			}
+
+			return nil
+		},
+		client.ToggleAdminData: s.handleToggleAdmin,
+		client.NotifyCountData: func(ctx context.Context, cq *tgbotapi.CallbackQuery, _ string) error {
+			if err := s.bot.SwitchNotifyCount(ctx, cq); err != nil {
+				return fmt.Errorf("couldn't switch notify count with error: %w", err)
+			}
+
+			return nil
+		},
+		client.TurnDurationData: func(ctx context.Context, cq *tgbotapi.CallbackQuery, _ string) error {
+			if err := s.bot.SwitchTurnDuration(ctx, cq); err != nil {
+				return fmt.Errorf("couldn't switch turn duration with error: %w", err)
+			}
+
+			return nil
+		},
+		client.SlotCountData: func(ctx context.Context, cq *tgbotapi.CallbackQuery, _ string) error {
+			if err := s.bot.SwitchSlotCount(ctx, cq); err != nil {
+				return fmt.Errorf("couldn't switch slot count with error: %w", err)
+			}
+
+			return nil
+		},
+		client.SlotDoneData: s.handleSlotDone,
+		client.CheckInTimeoutData: func(ctx context.Context, cq *tgbotapi.CallbackQuery, _ string) error {
+			if err := s.bot.SwitchCheckInTimeout(ctx, cq); err != nil {
+				return fmt.Errorf("couldn't switch check-in timeout with error: %w", err)
+			}
+
+			return nil
+		},
+		client.CheckInData: s.handleCheckIn,
+		client.LanguageData: func(ctx context.Context, cq *tgbotapi.CallbackQuery, _ string) error {
+			if err := s.bot.SwitchLanguage(ctx, cq); err != nil {
+				return fmt.Errorf("couldn't switch language with error: %w", err)
+			}
+
+			return nil
+		},
+		// Events are sent in private chat, the link to it is in the callback answer.
+		client.EventsData: func(context.Context, *tgbotapi.CallbackQuery, string) error {
+			return nil
+		},
+	}
+}
+
+func (s BotServer) handleSkip(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery, arg string) error {
+	positions := usecase.SkipToEnd
+	if arg != client.SkipToEndArg {
+		var err error
+		if positions, err = strconv.Atoi(arg); err != nil {
+			return fmt.Errorf("couldn't parse positions to skip %q: %w", arg, err)
 		}
-	case client.FinishQueueData:
-		if err := s.bot.FinishQueue(context.Background(), callbackQuery); err != nil {
-			return fmt.Errorf("couldn't finish queue with error: %w", err)
+	}
+
+	if err := s.bot.Skip(ctx, callbackQuery, positions); err != nil {
+		return fmt.Errorf("couldn't skip current person with error: %w", err)
+	}
+
+	return nil
+}
+
+func (s BotServer) handleSlotDone(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery, arg string) error {
+	slot, err := strconv.Atoi(arg)
+	if err != nil {
+		return fmt.Errorf("couldn't parse slot %q: %w", arg, err)
+	}
+
+	if err = s.bot.FinishSlot(ctx, callbackQuery, slot); err != nil {
+		return fmt.Errorf("couldn't finish slot with error: %w", err)
+	}
+
+	return nil
+}
+
+// handleCheckIn confirms the presence from the queue message or from a private message, where arg is the queue.
+// Private messages aren't ordered with updates of the queue, so the change goes through UpdateQueue.
+func (s BotServer) handleCheckIn(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery, arg string) error {
+	if arg == "" {
+		if err := s.bot.ConfirmCheckIn(ctx, callbackQuery, callbackQuery.InlineMessageID); err != nil {
+			return fmt.Errorf("couldn't check in with error: %w", err)
 		}
+
+		return s.bot.RenderQueue(ctx, callbackQuery.InlineMessageID)
+	}
+
+	err := s.UpdateQueue(ctx, arg, func(ctx context.Context) error {
+		return s.bot.ConfirmCheckIn(ctx, callbackQuery, arg)
+	})
+	if err != nil {
+		return fmt.Errorf("couldn't check in from private message with error: %w", err)
 	}
 
 	return nil
 }
 
-func (s BotServer) HandleCallbackQuery(callbackQuery *tgbotapi.CallbackQuery) error {
+func (s BotServer) handleToggleAdmin(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery, arg string) error {
+	userID, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return fmt.Errorf("couldn't parse user id %q: %w", arg, err)
+	}
+
+	if err := s.bot.ToggleAdmin(ctx, callbackQuery, userID); err != nil {
+		return fmt.Errorf("couldn't toggle admin with error: %w", err)
+	}
+
+	return nil
+}
+
+func (s BotServer) handleCallbackData(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery) error {
+	action, arg := client.ParseCallbackData(callbackQuery.Data)
+
+	handler, ok := s.callbackHandlers()[action]
+	if !ok {
+		slog.Warn("Unknown callback data", "data", callbackQuery.Data)
+
+		return nil
+	}
+
+	return handler(ctx, callbackQuery, arg)
+}
+
+// callbackAction is the action of callback data used as a metric label. Unknown actions are merged into one label.
+func (s BotServer) callbackAction(data string) string {
+	action, _ := client.ParseCallbackData(data)
+	if _, ok := s.callbackHandlers()[action]; !ok {
+		return "unknown"
+	}
+
+	return action
+}
+
+// callbackAnswer tells user why the action failed when it was rejected by permissions, the schedule
+// or the state of the queue, e.g. after pressing a button of a stale keyboard.
+// The answer is seen only by the user, so it's in their language.
+func callbackAnswer(lang i18n.Language, callbackQueryID string, err error) tgbotapi.CallbackConfig {
+	switch {
+	case err == nil:
+		return tgbotapi.NewCallback(callbackQueryID, lang.Text(i18n.ActionCompleted))
+	case errors.Is(err, usecase.ErrNotAdmin):
+		return tgbotapi.NewCallbackWithAlert(callbackQueryID, lang.Text(i18n.NotAdminError))
+	case errors.Is(err, usecase.ErrNotOwner):
+		return tgbotapi.NewCallbackWithAlert(callbackQueryID, lang.Text(i18n.NotOwnerError))
+	case errors.Is(err, usecase.ErrNothingToUndo):
+		return tgbotapi.NewCallback(callbackQueryID, lang.Text(i18n.NothingToUndo))
+	case errors.Is(err, usecase.ErrJoinNotOpen):
+		return tgbotapi.NewCallbackWithAlert(callbackQueryID, lang.Text(i18n.JoinNotOpenError))
+	case errors.Is(err, usecase.ErrJoinClosed):
+		return tgbotapi.NewCallbackWithAlert(callbackQueryID, lang.Text(i18n.JoinClosedError))
+	case errors.Is(err, usecase.ErrCheckInNotAsked):
+		return tgbotapi.NewCallbackWithAlert(callbackQueryID, lang.Text(i18n.CheckInNotAskedError))
+	case errors.Is(err, usecase.ErrHasSlots):
+		return tgbotapi.NewCallbackWithAlert(callbackQueryID, lang.Text(i18n.HasSlotsError))
+	case errors.Is(err, usecase.ErrNoCurrentPerson):
+		return tgbotapi.NewCallbackWithAlert(callbackQueryID, lang.Text(i18n.NoCurrentPersonError))
+	case errors.Is(err, usecase.ErrAlreadyStarted):
+		return tgbotapi.NewCallbackWithAlert(callbackQueryID, lang.Text(i18n.AlreadyStartedError))
+	default:
+		return tgbotapi.NewCallback(callbackQueryID, lang.Text(i18n.ActionError))
+	}
+}
+
+func (s BotServer) HandleCallbackQuery(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery) error {
 	// Сверяемся со скрытыми данными, заложенными в сообщении для определения команды
 	startTime := time.Now()
 	slog.Debug("Got callback query with data: ", "data", callbackQuery.Data)
 
-	err := s.handleCallbackData(callbackQuery)
-	if err != nil {
+	defer func() {
+		metrics.CallbackDuration.WithLabelValues(s.callbackAction(callbackQuery.Data)).Observe(time.Since(startTime).Seconds())
+	}()
+
+	err := s.handleCallbackData(ctx, callbackQuery)
+
+	switch {
+	case errors.Is(err, usecase.ErrNotAdmin), errors.Is(err, usecase.ErrNotOwner), errors.Is(err, usecase.ErrNothingToUndo),
+		errors.Is(err, usecase.ErrJoinNotOpen), errors.Is(err, usecase.ErrJoinClosed), errors.Is(err, usecase.ErrCheckInNotAsked),
+		errors.Is(err, usecase.ErrHasSlots), errors.Is(err, usecase.ErrNoCurrentPerson), errors.Is(err, usecase.ErrAlreadyStarted):
+		slog.Info("Callback query rejected", "reason", err, "data", callbackQuery.Data, "user_id", callbackQuery.From.ID)
+	case err != nil:
 		slog.Error(
 			"Couldn't handle callback query",
 			"reason",
@@ -63,14 +279,13 @@ func (s BotServer) HandleCallbackQuery(callbackQuery *tgbotapi.CallbackQuery) er
 		)
 	}
 
-	var callback tgbotapi.CallbackConfig
-	if err != nil {
-		callback = tgbotapi.NewCallback(callbackQuery.ID, ActionError)
-	} else {
-		callback = tgbotapi.NewCallback(callbackQuery.ID, ActionCompleted)
+	answer := callbackAnswer(client.UserLanguage(callbackQuery.From), callbackQuery.ID, err)
+	if action, _ := client.ParseCallbackData(callbackQuery.Data); action == client.EventsData && err == nil {
+		answer.Text = ""
+		answer.URL = s.bot.EventsLink(callbackQuery.InlineMessageID)
 	}
 
-	if _, err = s.bot.TgBot.Request(callback); err != nil {
+	if _, err = s.bot.TgBot.Request(answer); err != nil {
 		return fmt.Errorf("couldn't process next_data callback with error: %w", err)
 	}
 
@@ -0,0 +1,35 @@
+package telegram
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"QueueBot/internal/i18n"
+	"QueueBot/internal/usecase"
+)
+
+func TestCallbackAnswer(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantText  i18n.Key
+		wantAlert bool
+	}{
+		{name: "Completed", wantText: i18n.ActionCompleted},
+		{name: "Not admin", err: usecase.ErrNotAdmin, wantText: i18n.NotAdminError, wantAlert: true},
+		{name: "Has slots", err: usecase.ErrHasSlots, wantText: i18n.HasSlotsError, wantAlert: true},
+		{name: "No current person", err: usecase.ErrNoCurrentPerson, wantText: i18n.NoCurrentPersonError, wantAlert: true},
+		{name: "Already started", err: usecase.ErrAlreadyStarted, wantText: i18n.AlreadyStartedError, wantAlert: true},
+		{name: "Unexpected", err: errors.New("storage is down"), wantText: i18n.ActionError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answer := callbackAnswer(i18n.English, "1", tt.err)
+
+			assert.Equal(t, i18n.English.Text(tt.wantText), answer.Text)
+			assert.Equal(t, tt.wantAlert, answer.ShowAlert)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -9,33 +10,210 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"QueueBot/internal/entity"
+	"QueueBot/internal/i18n"
 	"QueueBot/internal/usecase"
+	"QueueBot/internal/usecase/storage"
 )
 
-const HelloMessage = `Привет! Я бот, предназначенный для создания очередей. 
-Введи описание своей очереди, а я тебе ее создам`
+// UserLanguage is the language of private messages to the user.
+func UserLanguage(user *tgbotapi.User) i18n.Language {
+	if user == nil {
+		return i18n.Default
+	}
+
+	return i18n.Parse(user.LanguageCode)
+}
+
+// QueueLanguage is the language of the inline message of the queue, which is seen by everybody in the chat.
+func QueueLanguage(queue entity.Queue) i18n.Language {
+	return i18n.Language(queue.Language)
+}
+
+// DashboardLinks makes read-only links to live pages of queues.
+type DashboardLinks interface {
+	Link(messageID string) string
+}
 
 type TelegramBot struct {
 	TgBot *tgbotapi.BotAPI
 	u     usecase.Bot
+	edits *EditScheduler
+	// dashboard is nil when dashboards are disabled.
+	dashboard DashboardLinks
 }
 
-func NewTelegramBot(tgBot *tgbotapi.BotAPI, u usecase.Bot) *TelegramBot {
-	return &TelegramBot{TgBot: tgBot, u: u}
+func NewTelegramBot(tgBot *tgbotapi.BotAPI, u usecase.Bot, edits *EditScheduler, dashboard DashboardLinks) *TelegramBot {
+	return &TelegramBot{TgBot: tgBot, u: u, edits: edits, dashboard: dashboard}
 }
 
-func (b TelegramBot) SendHelloMessage(message *tgbotapi.Message) error {
-	msg := tgbotapi.NewMessage(message.Chat.ID, HelloMessage)
+// Subscribe lets the bot send private messages about turns to the user who started it.
+func (b TelegramBot) Subscribe(ctx context.Context, message *tgbotapi.Message) error {
+	if !message.Chat.IsPrivate() {
+		return nil
+	}
 
-	if _, err := b.TgBot.Send(msg); err != nil {
+	if err := b.u.Subscribe(ctx, message.From.ID); err != nil {
+		return fmt.Errorf("couldn't subscribe user with error: %w", err)
+	}
+
+	return nil
+}
+
+func (b TelegramBot) SwitchNotifyCount(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery) error {
+	if err := b.u.SwitchNotifyCount(ctx, callbackQuery.InlineMessageID, callbackQuery.From.ID); err != nil {
+		return fmt.Errorf("couldn't switch notify count with error: %w", err)
+	}
+
+	slog.Info("Switched notify count", "messageId", callbackQuery.InlineMessageID)
+
+	return b.sendMenuMessage(ctx, callbackQuery)
+}
+
+func (b TelegramBot) SwitchTurnDuration(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery) error {
+	if err := b.u.SwitchTurnDuration(ctx, callbackQuery.InlineMessageID, callbackQuery.From.ID); err != nil {
+		return fmt.Errorf("couldn't switch turn duration with error: %w", err)
+	}
+
+	slog.Info("Switched turn duration", "messageId", callbackQuery.InlineMessageID)
+
+	return b.sendMenuMessage(ctx, callbackQuery)
+}
+
+func (b TelegramBot) SwitchSlotCount(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery) error {
+	if err := b.u.SwitchSlotCount(ctx, callbackQuery.InlineMessageID, callbackQuery.From.ID); err != nil {
+		return fmt.Errorf("couldn't switch slot count with error: %w", err)
+	}
+
+	slog.Info("Switched slot count", "messageId", callbackQuery.InlineMessageID)
+
+	return b.sendMenuMessage(ctx, callbackQuery)
+}
+
+func (b TelegramBot) SwitchCheckInTimeout(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery) error {
+	if err := b.u.SwitchCheckInTimeout(ctx, callbackQuery.InlineMessageID, callbackQuery.From.ID); err != nil {
+		return fmt.Errorf("couldn't switch check-in timeout with error: %w", err)
+	}
+
+	slog.Info("Switched check-in timeout", "messageId", callbackQuery.InlineMessageID)
+
+	return b.sendMenuMessage(ctx, callbackQuery)
+}
+
+// ConfirmCheckIn records the presence of the user in the queue. The button can be pressed in a private message,
+// so the queue is given by messageID and isn't rendered here.
+func (b TelegramBot) ConfirmCheckIn(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery, messageID string) error {
+	if err := b.u.ConfirmCheckIn(ctx, messageID, callbackQuery.From.ID); err != nil {
+		return fmt.Errorf("couldn't confirm check-in in queue %s with error: %w", messageID, err)
+	}
+
+	slog.Info("Confirmed check-in", "messageId", messageID, "userId", callbackQuery.From.ID)
+
+	return nil
+}
+
+func (b TelegramBot) SwitchLanguage(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery) error {
+	if err := b.u.SwitchLanguage(ctx, callbackQuery.InlineMessageID, callbackQuery.From.ID); err != nil {
+		return fmt.Errorf("couldn't switch language with error: %w", err)
+	}
+
+	slog.Info("Switched language", "messageId", callbackQuery.InlineMessageID)
+
+	return b.sendMenuMessage(ctx, callbackQuery)
+}
+
+func (b TelegramBot) SendHelloMessage(message *tgbotapi.Message) error {
+	if _, err := b.TgBot.Send(GetHelloMessage(UserLanguage(message.From), message.Chat.ID)); err != nil {
 		return fmt.Errorf("couldn't send hello message in telegram with error: %w", err)
 	}
 
 	return nil
 }
 
+// EventsStartPrefix starts the /start payload of the link which opens events of the queue in private chat.
+const EventsStartPrefix = "events_"
+
+// EventsLink opens private chat with the bot, which then sends events of the queue.
+func (b TelegramBot) EventsLink(messageID string) string {
+	return fmt.Sprintf("https://t.me/%s?start=%s%s", b.TgBot.Self.UserName, EventsStartPrefix, messageID)
+}
+
+func (b TelegramBot) SendEvents(ctx context.Context, message *tgbotapi.Message, messageID string, limit int) error {
+	events, err := b.u.GetEvents(ctx, messageID, limit)
+	if err != nil {
+		return fmt.Errorf("couldn't get events with error: %w", err)
+	}
+
+	lang := UserLanguage(message.From)
+
+	if _, err = b.TgBot.Send(GetEventsMessage(lang, message.Chat.ID, events)); err != nil {
+		return fmt.Errorf("couldn't send events message in telegram with error: %w", err)
+	}
+
+	if b.dashboard == nil {
+		return nil
+	}
+
+	return b.sendDashboardLink(ctx, lang, message.Chat.ID, messageID)
+}
+
+// sendDashboardLink sends the link to the live page of the queue, unless the queue is already finished.
+func (b TelegramBot) sendDashboardLink(ctx context.Context, lang i18n.Language, chatID int64, messageID string) error {
+	queue, err := b.u.GetQueue(ctx, messageID)
+	if errors.Is(err, storage.ErrQueueNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("couldn't get queue with error: %w", err)
+	}
+
+	msg := GetDashboardLinkMessage(lang, chatID, queue.Description, b.dashboard.Link(messageID))
+	if _, err = b.TgBot.Send(msg); err != nil {
+		return fmt.Errorf("couldn't send dashboard link in telegram with error: %w", err)
+	}
+
+	return nil
+}
+
+func (b TelegramBot) SendHistory(ctx context.Context, message *tgbotapi.Message) error {
+	queues, err := b.u.GetFinishedQueues(ctx, message.From.ID)
+	if err != nil {
+		return fmt.Errorf("couldn't get finished queues with error: %w", err)
+	}
+
+	if _, err = b.TgBot.Send(GetHistoryMessage(UserLanguage(message.From), message.Chat.ID, queues)); err != nil {
+		return fmt.Errorf("couldn't send history message in telegram with error: %w", err)
+	}
+
+	return nil
+}
+
+func (b TelegramBot) SendEventsUsage(message *tgbotapi.Message) error {
+	if _, err := b.TgBot.Send(GetEventsUsageMessage(UserLanguage(message.From), message.Chat.ID)); err != nil {
+		return fmt.Errorf("couldn't send events usage in telegram with error: %w", err)
+	}
+
+	return nil
+}
+
+func (b TelegramBot) SendCreateUsage(message *tgbotapi.Message) error {
+	if _, err := b.TgBot.Send(GetCreateUsageMessage(UserLanguage(message.From), message.Chat.ID)); err != nil {
+		return fmt.Errorf("couldn't send create usage in telegram with error: %w", err)
+	}
+
+	return nil
+}
+
+func (b TelegramBot) SendScheduleUsage(message *tgbotapi.Message) error {
+	if _, err := b.TgBot.Send(GetScheduleUsageMessage(UserLanguage(message.From), message.Chat.ID)); err != nil {
+		return fmt.Errorf("couldn't send schedule usage in telegram with error: %w", err)
+	}
+
+	return nil
+}
+
 func (b TelegramBot) SendForwardMessageButton(message *tgbotapi.Message) error {
-	msg := GetForwardMessage(message.Chat.ID, message.Text)
+	msg := GetForwardMessage(UserLanguage(message.From), message.Chat.ID, message.Text)
 	if _, err := b.TgBot.Send(msg); err != nil {
 		return fmt.Errorf("couldn't send forward to message in telegram with error: %w", err)
 	}
@@ -43,12 +221,142 @@ func (b TelegramBot) SendForwardMessageButton(message *tgbotapi.Message) error {
 	return nil
 }
 
-func (b TelegramBot) CreateQueue(ctx context.Context, messageID string, description string) error {
-	if err := b.u.CreateQueue(ctx, messageID, description); err != nil {
+// CreateQueue creates the queue in the language of its owner.
+// Description, Capacity, Schedule and TemplateID of the queue are used, zero ones are ignored.
+func (b TelegramBot) CreateQueue(ctx context.Context, messageID string, queue entity.Queue, owner *tgbotapi.User) error {
+	err := b.u.CreateQueue(ctx, messageID, queue.Description, owner.ID, string(UserLanguage(owner)), queue.Capacity)
+	if err != nil {
 		return fmt.Errorf("couldn't create queue with error: %w", err)
 	}
 
-	slog.Info("Queue created successfully", "messageID", messageID, "description", description)
+	if !queue.Schedule.IsZero() {
+		if err = b.u.ScheduleQueue(ctx, messageID, owner.ID, queue.Schedule); err != nil {
+			return fmt.Errorf("couldn't schedule queue with error: %w", err)
+		}
+	}
+
+	if queue.TemplateID != 0 {
+		if err = b.u.SetQueueTemplate(ctx, messageID, owner.ID, queue.TemplateID); err != nil {
+			return fmt.Errorf("couldn't link queue to template with error: %w", err)
+		}
+	}
+
+	slog.Info(
+		"Queue created successfully",
+		"messageID", messageID,
+		"description", queue.Description,
+		"capacity", queue.Capacity,
+		"startsAt", queue.Schedule.StartsAt,
+		"templateId", queue.TemplateID,
+		"ownerId", owner.ID,
+	)
+
+	return nil
+}
+
+// TemplateQueryPrefix starts the inline query which posts a queue from the template, e.g. "/templates 3".
+// It is the /templates command with the ID, so the forward button of the command message posts the queue too.
+const TemplateQueryPrefix = "/templates "
+
+// TemplateQuery is the inline query which posts a queue from the template.
+func TemplateQuery(templateID int64) string {
+	return fmt.Sprintf("%s%d", TemplateQueryPrefix, templateID)
+}
+
+func (b TelegramBot) GetTemplate(ctx context.Context, templateID int64, user *tgbotapi.User) (entity.Template, error) {
+	template, err := b.u.GetTemplate(ctx, templateID, user.ID)
+	if err != nil {
+		return entity.Template{}, fmt.Errorf("couldn't get template with error: %w", err)
+	}
+
+	return template, nil
+}
+
+// SendTemplates sends templates of the user with buttons which post queues from them.
+func (b TelegramBot) SendTemplates(ctx context.Context, message *tgbotapi.Message) error {
+	templates, err := b.u.GetTemplates(ctx, message.From.ID)
+	if err != nil {
+		return fmt.Errorf("couldn't get templates with error: %w", err)
+	}
+
+	if _, err = b.TgBot.Send(GetTemplatesMessage(UserLanguage(message.From), message.Chat.ID, templates)); err != nil {
+		return fmt.Errorf("couldn't send templates message in telegram with error: %w", err)
+	}
+
+	return nil
+}
+
+func (b TelegramBot) SendTemplatesUsage(message *tgbotapi.Message) error {
+	if _, err := b.TgBot.Send(GetTemplatesUsageMessage(UserLanguage(message.From), message.Chat.ID)); err != nil {
+		return fmt.Errorf("couldn't send templates usage in telegram with error: %w", err)
+	}
+
+	return nil
+}
+
+// SendTemplatesPrivateOnly tells the user who sent /templates to a group to send it in a private chat.
+func (b TelegramBot) SendTemplatesPrivateOnly(message *tgbotapi.Message) error {
+	if _, err := b.TgBot.Send(GetTemplatesPrivateOnlyMessage(UserLanguage(message.From), message.Chat.ID)); err != nil {
+		return fmt.Errorf("couldn't send templates private only message in telegram with error: %w", err)
+	}
+
+	return nil
+}
+
+// CreateTemplate saves the template of the user who sent the message, then sends their templates.
+func (b TelegramBot) CreateTemplate(ctx context.Context, message *tgbotapi.Message, template entity.Template) error {
+	template.OwnerID = message.From.ID
+
+	templateID, err := b.u.CreateTemplate(ctx, template)
+	if err != nil {
+		return fmt.Errorf("couldn't create template with error: %w", err)
+	}
+
+	slog.Info("Template created successfully", "templateId", templateID, "ownerId", template.OwnerID)
+
+	return b.SendTemplates(ctx, message)
+}
+
+// DeleteTemplate deletes the template of the user who sent the message, then sends their templates.
+func (b TelegramBot) DeleteTemplate(ctx context.Context, message *tgbotapi.Message, templateID int64) error {
+	err := b.u.DeleteTemplate(ctx, templateID, message.From.ID)
+	if errors.Is(err, storage.ErrTemplateNotFound) {
+		return b.sendTemplateNotFound(message, templateID)
+	}
+
+	if err != nil {
+		return fmt.Errorf("couldn't delete template with error: %w", err)
+	}
+
+	slog.Info("Template deleted successfully", "templateId", templateID, "ownerId", message.From.ID)
+
+	return b.SendTemplates(ctx, message)
+}
+
+// SendTemplateForwardButton sends the button which posts a queue from the template of the user to a chat.
+func (b TelegramBot) SendTemplateForwardButton(ctx context.Context, message *tgbotapi.Message, templateID int64) error {
+	_, err := b.u.GetTemplate(ctx, templateID, message.From.ID)
+	if errors.Is(err, storage.ErrTemplateNotFound) {
+		return b.sendTemplateNotFound(message, templateID)
+	}
+
+	if err != nil {
+		return fmt.Errorf("couldn't get template with error: %w", err)
+	}
+
+	msg := GetForwardMessage(UserLanguage(message.From), message.Chat.ID, TemplateQuery(templateID))
+	if _, err = b.TgBot.Send(msg); err != nil {
+		return fmt.Errorf("couldn't send forward to message in telegram with error: %w", err)
+	}
+
+	return nil
+}
+
+func (b TelegramBot) sendTemplateNotFound(message *tgbotapi.Message, templateID int64) error {
+	msg := GetTemplateNotFoundMessage(UserLanguage(message.From), message.Chat.ID, templateID)
+	if _, err := b.TgBot.Send(msg); err != nil {
+		return fmt.Errorf("couldn't send template not found in telegram with error: %w", err)
+	}
 
 	return nil
 }
@@ -73,17 +381,14 @@ func (b TelegramBot) LogInOurOut(ctx context.Context, callbackQuery *tgbotapi.Ca
 
 	slog.Debug("Got queue", "elapsed", time.Since(startTime).String())
 
-	updatedMessage := GetUpdatedQueueMessage(callbackQuery.InlineMessageID, queue.Description, queue.Users)
+	updatedMessage := GetUpdatedQueueMessage(QueueLanguage(queue), callbackQuery.InlineMessageID, queue)
 
 	slog.Debug("Got updated queue message", "elapsed", time.Since(startTime).String())
 
-	_, err = b.TgBot.Request(updatedMessage)
-	if err != nil {
-		return fmt.Errorf("couldn't update message with error: %w", err)
-	}
+	b.edits.Schedule(callbackQuery.InlineMessageID, updatedMessage)
 
 	slog.Debug(
-		"Logged in/out and sent updated message",
+		"Logged in/out and scheduled updated message",
 		"messageId", callbackQuery.InlineMessageID,
 		"userId", callbackQuery.From.ID,
 		"elapsed", time.Since(startTime).String(),
@@ -93,7 +398,7 @@ func (b TelegramBot) LogInOurOut(ctx context.Context, callbackQuery *tgbotapi.Ca
 }
 
 func (b TelegramBot) Start(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery, isShuffled bool) error {
-	err := b.u.StartQueue(ctx, callbackQuery.InlineMessageID, isShuffled)
+	err := b.u.StartQueue(ctx, callbackQuery.InlineMessageID, callbackQuery.From.ID, isShuffled)
 	if err != nil {
 		return fmt.Errorf("couldn't start queue with error: %w", err)
 	}
@@ -104,7 +409,7 @@ func (b TelegramBot) Start(ctx context.Context, callbackQuery *tgbotapi.Callback
 }
 
 func (b TelegramBot) Next(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery) error {
-	err := b.u.SetNextPersonToQueue(ctx, callbackQuery.InlineMessageID)
+	err := b.u.SetNextPersonToQueue(ctx, callbackQuery.InlineMessageID, callbackQuery.From.ID)
 	if err != nil {
 		return fmt.Errorf("couldn't increment current person in queue %s with error: %w", callbackQuery.InlineMessageID, err)
 	}
@@ -114,56 +419,176 @@ func (b TelegramBot) Next(ctx context.Context, callbackQuery *tgbotapi.CallbackQ
 	return b.sendQueueStatusMessage(ctx, callbackQuery)
 }
 
+func (b TelegramBot) FinishSlot(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery, slot int) error {
+	err := b.u.FinishSlot(ctx, callbackQuery.InlineMessageID, callbackQuery.From.ID, slot)
+	if err != nil {
+		return fmt.Errorf("couldn't finish slot %d in queue %s with error: %w", slot, callbackQuery.InlineMessageID, err)
+	}
+
+	slog.Info("Finished slot", "messageId", callbackQuery.InlineMessageID, "slot", slot)
+
+	return b.sendQueueStatusMessage(ctx, callbackQuery)
+}
+
+func (b TelegramBot) Skip(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery, positions int) error {
+	err := b.u.SkipCurrentPerson(ctx, callbackQuery.InlineMessageID, callbackQuery.From.ID, positions)
+	if err != nil {
+		return fmt.Errorf("couldn't skip current person in queue %s with error: %w", callbackQuery.InlineMessageID, err)
+	}
+
+	slog.Info("Skipped current person", "messageId", callbackQuery.InlineMessageID, "positions", positions)
+
+	return b.sendQueueStatusMessage(ctx, callbackQuery)
+}
+
+func (b TelegramBot) Previous(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery) error {
+	err := b.u.SetPreviousPersonToQueue(ctx, callbackQuery.InlineMessageID, callbackQuery.From.ID)
+	if err != nil {
+		return fmt.Errorf("couldn't decrement current person in queue %s with error: %w", callbackQuery.InlineMessageID, err)
+	}
+
+	slog.Info("Set previous person", "messageId", callbackQuery.InlineMessageID)
+
+	return b.sendQueueStatusMessage(ctx, callbackQuery)
+}
+
 func (b TelegramBot) GoToMenu(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery) error {
+	if err := b.u.StopQueue(ctx, callbackQuery.InlineMessageID, callbackQuery.From.ID); err != nil {
+		return fmt.Errorf("couldn't stop queue with error: %w", err)
+	}
+
+	slog.Info("Went to menu", "messageId", callbackQuery.InlineMessageID)
+
+	return b.sendMenuMessage(ctx, callbackQuery)
+}
+
+// ShowMenu brings back the menu without changing the queue, e.g. from the admins menu.
+func (b TelegramBot) ShowMenu(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery) error {
+	if err := b.u.CheckAdmin(ctx, callbackQuery.InlineMessageID, callbackQuery.From.ID); err != nil {
+		return fmt.Errorf("couldn't show menu: %w", err)
+	}
+
+	return b.sendMenuMessage(ctx, callbackQuery)
+}
+
+func (b TelegramBot) Undo(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery) error {
+	operation, err := b.u.Undo(ctx, callbackQuery.InlineMessageID, callbackQuery.From.ID)
+	if err != nil {
+		return fmt.Errorf("couldn't undo with error: %w", err)
+	}
+
+	slog.Info("Undone operation", "messageId", callbackQuery.InlineMessageID, "operation", operation)
+
 	queue, err := b.u.GetQueue(ctx, callbackQuery.InlineMessageID)
 	if err != nil {
 		return fmt.Errorf("couldn't get queue with error: %w", err)
 	}
 
-	updatedMessage := GetQueueMessage(callbackQuery.InlineMessageID, queue.Users, queue.Description)
-	_, err = b.TgBot.Request(updatedMessage)
+	if queue.IsStarted {
+		return b.sendQueueStatusMessage(ctx, callbackQuery)
+	}
+
+	return b.sendMenuMessage(ctx, callbackQuery)
+}
+
+func (b TelegramBot) sendMenuMessage(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery) error {
+	queue, err := b.u.GetQueue(ctx, callbackQuery.InlineMessageID)
 	if err != nil {
-		return fmt.Errorf("couldn't go to menu with error: %w", err)
+		return fmt.Errorf("couldn't get queue with error: %w", err)
 	}
 
-	slog.Info("Went to menu", "messageId", callbackQuery.InlineMessageID)
+	b.edits.Schedule(
+		callbackQuery.InlineMessageID,
+		GetQueueMessage(QueueLanguage(queue), callbackQuery.InlineMessageID, queue),
+	)
 
 	return nil
 }
 
 func (b TelegramBot) FinishQueue(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery) error {
-	updatedMessage := GetFinishedMessage(callbackQuery.InlineMessageID)
-	_, err := b.TgBot.Request(updatedMessage)
+	if err := b.u.CheckAdmin(ctx, callbackQuery.InlineMessageID, callbackQuery.From.ID); err != nil {
+		return fmt.Errorf("couldn't finish queue: %w", err)
+	}
+
+	// The queue can't be read after it is finished.
+	queue, err := b.u.GetQueue(ctx, callbackQuery.InlineMessageID)
 	if err != nil {
-		return fmt.Errorf("couldn't send finish queue with error: %w", err)
+		return fmt.Errorf("couldn't get queue with error: %w", err)
 	}
 
-	if err = b.u.FinishQueue(ctx, callbackQuery.InlineMessageID); err != nil {
+	if err = b.u.FinishQueue(ctx, callbackQuery.InlineMessageID, callbackQuery.From.ID); err != nil {
 		return fmt.Errorf("couldn't finish queue with error: %w", err)
 	}
 
+	b.edits.Schedule(callbackQuery.InlineMessageID, GetFinishedMessage(QueueLanguage(queue), callbackQuery.InlineMessageID))
+
 	slog.Info("Finished queue", "messageId", callbackQuery.InlineMessageID)
 
 	return nil
 }
 
-func (b TelegramBot) sendQueueStatusMessage(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery) error {
+func (b TelegramBot) ManageAdmins(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery) error {
+	if err := b.u.CheckOwner(ctx, callbackQuery.InlineMessageID, callbackQuery.From.ID); err != nil {
+		return fmt.Errorf("couldn't manage admins: %w", err)
+	}
+
+	return b.sendAdminsMessage(ctx, callbackQuery)
+}
+
+func (b TelegramBot) ToggleAdmin(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery, userID int64) error {
+	if err := b.u.ToggleAdmin(ctx, callbackQuery.InlineMessageID, callbackQuery.From.ID, userID); err != nil {
+		return fmt.Errorf("couldn't toggle admin with error: %w", err)
+	}
+
+	slog.Info("Toggled admin", "messageId", callbackQuery.InlineMessageID, "userId", userID)
+
+	return b.sendAdminsMessage(ctx, callbackQuery)
+}
+
+func (b TelegramBot) sendAdminsMessage(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery) error {
 	queue, err := b.u.GetQueue(ctx, callbackQuery.InlineMessageID)
 	if err != nil {
 		return fmt.Errorf("couldn't get queue with error: %w", err)
 	}
 
-	var updatedMessage tgbotapi.EditMessageTextConfig
-	if queue.CurrentPersonIdx == len(queue.Users) {
-		updatedMessage = GetEndQueueMessage(callbackQuery.InlineMessageID)
-	} else {
-		updatedMessage = GetQueueAfterStartMessage(callbackQuery.InlineMessageID, queue.Description, queue.Users, queue.CurrentPersonIdx)
+	b.edits.Schedule(callbackQuery.InlineMessageID, GetAdminsMessage(QueueLanguage(queue), callbackQuery.InlineMessageID, queue))
+
+	return nil
+}
+
+func (b TelegramBot) sendQueueStatusMessage(ctx context.Context, callbackQuery *tgbotapi.CallbackQuery) error {
+	queue, err := b.u.GetQueue(ctx, callbackQuery.InlineMessageID)
+	if err != nil {
+		return fmt.Errorf("couldn't get queue with error: %w", err)
 	}
 
-	_, err = b.TgBot.Request(updatedMessage)
+	b.edits.Schedule(callbackQuery.InlineMessageID, getQueueStatusMessage(queue))
+
+	return nil
+}
+
+// RenderQueue edits the inline message of the queue to show its current state, e.g. after it was changed outside Telegram.
+func (b TelegramBot) RenderQueue(ctx context.Context, messageID string) error {
+	queue, err := b.u.GetQueue(ctx, messageID)
 	if err != nil {
-		return fmt.Errorf("couldn't update message after starting queue with error: %w", err)
+		return fmt.Errorf("couldn't get queue with error: %w", err)
 	}
 
+	if !queue.IsStarted {
+		b.edits.Schedule(messageID, GetUpdatedQueueMessage(QueueLanguage(queue), messageID, queue))
+
+		return nil
+	}
+
+	b.edits.Schedule(messageID, getQueueStatusMessage(queue))
+
 	return nil
 }
+
+func getQueueStatusMessage(queue entity.Queue) tgbotapi.EditMessageTextConfig {
+	if queue.IsOver() {
+		return GetEndQueueMessage(QueueLanguage(queue), queue.MessageID, queue.HasSlots())
+	}
+
+	return GetQueueAfterStartMessage(QueueLanguage(queue), queue.MessageID, queue)
+}
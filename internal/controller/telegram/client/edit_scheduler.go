@@ -0,0 +1,165 @@
+package client
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// DefaultEditDebounce is how long EditScheduler waits for newer state of a message before editing it.
+const DefaultEditDebounce = 500 * time.Millisecond
+
+// maxEditRetries is how many times an edit is retried after Telegram asked to retry later.
+const maxEditRetries = 5
+
+// notModifiedError is the description Telegram answers with when the edit doesn't change the message.
+const notModifiedError = "message is not modified"
+
+// EditScheduler edits inline messages. Edits of the same message are debounced, so only the latest state is sent.
+// It waits as long as Telegram asks when edits are rate limited.
+type EditScheduler struct {
+	bot      *tgbotapi.BotAPI
+	debounce time.Duration
+
+	mu sync.Mutex
+	// pending edits by inline message ID. A message is present while its worker is running.
+	pending map[string]*pendingEdit
+	wg      sync.WaitGroup
+	closed  chan struct{}
+	once    sync.Once
+}
+
+type pendingEdit struct {
+	edit tgbotapi.Chattable
+	// version grows with every Schedule, so the worker knows whether the edit was replaced while it was sent.
+	version int
+}
+
+func NewEditScheduler(bot *tgbotapi.BotAPI, debounce time.Duration) *EditScheduler {
+	return &EditScheduler{
+		bot:      bot,
+		debounce: debounce,
+		pending:  make(map[string]*pendingEdit),
+		closed:   make(chan struct{}),
+	}
+}
+
+// Schedule replaces the pending edit of the message with edit.
+func (s *EditScheduler) Schedule(messageID string, edit tgbotapi.Chattable) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p, ok := s.pending[messageID]; ok {
+		p.edit = edit
+		p.version++
+
+		return
+	}
+
+	s.pending[messageID] = &pendingEdit{edit: edit, version: 1}
+
+	s.wg.Add(1)
+
+	go s.run(messageID)
+}
+
+// Close sends pending edits without waiting for debounce and waits until they are sent.
+func (s *EditScheduler) Close() {
+	s.once.Do(func() {
+		close(s.closed)
+	})
+
+	s.wg.Wait()
+}
+
+func (s *EditScheduler) run(messageID string) {
+	defer s.wg.Done()
+
+	sentVersion := 0
+	retries := 0
+	delay := s.debounce
+
+	for {
+		isClosed := s.wait(delay)
+
+		s.mu.Lock()
+		p := s.pending[messageID]
+		edit, version := p.edit, p.version
+		s.mu.Unlock()
+
+		if coalesced := version - sentVersion - 1; coalesced > 0 {
+			slog.Debug("Dropped outdated edits", "messageId", messageID, "count", coalesced)
+		}
+
+		retryAfter, err := s.send(edit)
+
+		switch {
+		case retryAfter > 0 && retries < maxEditRetries && !isClosed:
+			slog.Info("Edit is rate limited", "messageId", messageID, "retryAfter", retryAfter.String())
+
+			retries++
+			delay = retryAfter
+			sentVersion = version - 1
+
+			continue
+		case err != nil:
+			slog.Warn("Dropped edit", "messageId", messageID, "reason", err)
+		}
+
+		s.mu.Lock()
+		if p.version == version {
+			delete(s.pending, messageID)
+			s.mu.Unlock()
+
+			return
+		}
+		s.mu.Unlock()
+
+		// The message was changed while the edit was being sent.
+		sentVersion = version
+		retries = 0
+		delay = s.debounce
+	}
+}
+
+// wait sleeps for delay or until the scheduler is closed. It reports whether the scheduler is closed.
+func (s *EditScheduler) wait(delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		select {
+		case <-s.closed:
+			return true
+		default:
+			return false
+		}
+	case <-s.closed:
+		return true
+	}
+}
+
+// send edits the message. It returns how long to wait if Telegram asked to retry later.
+func (s *EditScheduler) send(edit tgbotapi.Chattable) (time.Duration, error) {
+	_, err := s.bot.Request(edit)
+
+	var tgErr *tgbotapi.Error
+	if !errors.As(err, &tgErr) {
+		return 0, err
+	}
+
+	switch {
+	case tgErr.Code == http.StatusTooManyRequests && tgErr.RetryAfter > 0:
+		return time.Duration(tgErr.RetryAfter) * time.Second, err
+	case tgErr.Code == http.StatusBadRequest && strings.Contains(tgErr.Message, notModifiedError):
+		return 0, nil
+	default:
+		return 0, err
+	}
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	okResponse          = `{"ok": true, "result": true}`
+	rateLimitedResponse = `{"ok": false, "error_code": 429, "description": "Too Many Requests: retry after 1", "parameters": {"retry_after": 1}}`
+	notModifiedResponse = `{"ok": false, "error_code": 400, "description": "Bad Request: message is not modified"}`
+	badRequestResponse  = `{"ok": false, "error_code": 400, "description": "Bad Request: message to edit not found"}`
+)
+
+// fakeEditAPI answers edits with responses in order and remembers edited texts.
+type fakeEditAPI struct {
+	mu        sync.Mutex
+	responses []string
+	texts     []string
+}
+
+func (f *fakeEditAPI) Do(r *http.Request) (*http.Response, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	body := `{"ok": true, "result": {"id": 1, "is_bot": true, "first_name": "QueueBot", "username": "queue_bot"}}`
+
+	if path.Base(r.URL.Path) == "editMessageText" {
+		f.mu.Lock()
+		f.texts = append(f.texts, r.PostForm.Get("text"))
+
+		body = okResponse
+		if len(f.responses) > 0 {
+			body, f.responses = f.responses[0], f.responses[1:]
+		}
+		f.mu.Unlock()
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}, nil
+}
+
+func (f *fakeEditAPI) editedTexts() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.texts...)
+}
+
+func newTestEditScheduler(t *testing.T, debounce time.Duration, responses ...string) (*EditScheduler, *fakeEditAPI) {
+	t.Helper()
+
+	fake := &fakeEditAPI{responses: responses}
+
+	bot, err := tgbotapi.NewBotAPIWithClient("token", tgbotapi.APIEndpoint, fake)
+	require.NoError(t, err)
+
+	return NewEditScheduler(bot, debounce), fake
+}
+
+func TestEditScheduler_Schedule(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []string
+		edits     map[string][]string
+		want      []string
+	}{
+		{
+			name:  "Only latest edit is sent",
+			edits: map[string][]string{"message": {"1", "2", "3"}},
+			want:  []string{"3"},
+		},
+		{
+			name:  "Messages are edited separately",
+			edits: map[string][]string{"first": {"1", "2"}, "second": {"3"}},
+			want:  []string{"2", "3"},
+		},
+		{
+			name:      "Failed edit isn't retried",
+			responses: []string{badRequestResponse},
+			edits:     map[string][]string{"message": {"1"}},
+			want:      []string{"1"},
+		},
+		{
+			name:      "Not modified message isn't retried",
+			responses: []string{notModifiedResponse},
+			edits:     map[string][]string{"message": {"1"}},
+			want:      []string{"1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Debounce never ends, so edits are sent by Close.
+			scheduler, fake := newTestEditScheduler(t, time.Hour, tt.responses...)
+
+			for messageID, texts := range tt.edits {
+				for _, text := range texts {
+					scheduler.Schedule(messageID, tgbotapi.NewEditMessageText(0, 0, text))
+				}
+			}
+
+			scheduler.Close()
+
+			assert.ElementsMatch(t, tt.want, fake.editedTexts())
+		})
+	}
+}
+
+func TestEditScheduler_RetryAfter(t *testing.T) {
+	scheduler, fake := newTestEditScheduler(t, time.Millisecond, rateLimitedResponse)
+
+	scheduler.Schedule("message", tgbotapi.NewEditMessageText(0, 0, "1"))
+
+	assert.Eventually(t, func() bool {
+		return len(fake.editedTexts()) == 1
+	}, time.Second, time.Millisecond)
+
+	// The rate limited edit is replaced while the scheduler waits.
+	scheduler.Schedule("message", tgbotapi.NewEditMessageText(0, 0, "2"))
+
+	assert.Eventually(t, func() bool {
+		return len(fake.editedTexts()) == 2
+	}, 3*time.Second, 10*time.Millisecond)
+
+	scheduler.Close()
+
+	assert.Equal(t, []string{"1", "2"}, fake.editedTexts())
+}
@@ -1,21 +1,18 @@
 package client
 
 import (
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-)
+	"strconv"
+	"strings"
+	"time"
 
-const LogInOurOutButton = "Добавиться/выйти из очереди"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
-const (
-	StartQueueButton        = "Старт в порядке очереди"
-	StartQueueShuffleButton = "Старт в случайном порядке"
+	"QueueBot/internal/entity"
+	"QueueBot/internal/i18n"
 )
 
-const (
-	NextButton        = "Следующий"
-	GoToMenuButton    = "Перейти в меню"
-	FinishQueueButton = "Закончить"
-)
+// AdminMark marks admins in the admins menu.
+const AdminMark = "✅"
 
 const (
 	LogInOurOutData       = "log_in_our_out"
@@ -24,67 +21,264 @@ const (
 	NextData              = "next_user"
 	GoToMenuData          = "go_to_menu"
 	FinishQueueData       = "finish_queue"
+	ManageAdminsData      = "manage_admins"
+	ToggleAdminData       = "toggle_admin"
+	SkipData              = "skip_user"
+	PreviousData          = "previous_user"
+	UndoData              = "undo"
+	ShowMenuData          = "show_menu"
+	EventsData            = "events"
+	NotifyCountData       = "notify_count"
+	LanguageData          = "language"
+	TurnDurationData      = "turn_duration"
+	SlotCountData         = "slot_count"
+	CheckInTimeoutData    = "check_in_timeout"
+	// SlotDoneData is followed by number of the slot.
+	SlotDoneData = "slot_done"
+	// CheckInData is followed by message ID of the queue when it's pressed in a private message.
+	CheckInData = "check_in"
 )
 
-func GetBeforeStartKeyboard() tgbotapi.InlineKeyboardMarkup {
+// SkipToEndArg is passed with SkipData instead of the number of positions to move the person to the end.
+const SkipToEndArg = "end"
+
+// callbackDataSeparator splits action and its argument in callback data, e.g. "toggle_admin:42".
+const callbackDataSeparator = ":"
+
+// ParseCallbackData splits callback data into action and optional argument.
+func ParseCallbackData(data string) (action string, arg string) {
+	action, arg, _ = strings.Cut(data, callbackDataSeparator)
+
+	return action, arg
+}
+
+func callbackData(action string, arg string) string {
+	return action + callbackDataSeparator + arg
+}
+
+func GetBeforeStartKeyboard(
+	lang i18n.Language,
+	notifyCount int,
+	turnDuration time.Duration,
+	slotCount int,
+	checkInTimeout time.Duration,
+) tgbotapi.InlineKeyboardMarkup {
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			logInOurOutQueueButton(),
+			logInOurOutQueueButton(lang),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			startQueueButton(lang),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			startQueueShuffleButton(lang),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			manageAdminsButton(lang),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			notifyCountButton(lang, notifyCount),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			turnDurationButton(lang, turnDuration),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			slotCountButton(lang, slotCount),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			startQueueButton(),
+			checkInTimeoutButton(lang, checkInTimeout),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			startQueueShuffleButton(),
+			languageButton(lang),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			undoButton(lang),
+			eventsButton(lang),
 		),
 	)
 
 	return keyboard
 }
 
-func GetAfterStartKeyboard() tgbotapi.InlineKeyboardMarkup {
+func GetAfterStartKeyboard(lang i18n.Language) tgbotapi.InlineKeyboardMarkup {
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			nextButton(),
+			previousButton(lang),
+			nextButton(lang),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			skipButton(lang),
+			skipToEndButton(lang),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			undoButton(lang),
+			eventsButton(lang),
 		),
 	)
 
 	return keyboard
 }
 
-func GetEndedQueueKeyboard() tgbotapi.InlineKeyboardMarkup {
+// GetSlotsKeyboard has a button for every slot of the started queue. Pressing it calls the next person to the slot.
+func GetSlotsKeyboard(lang i18n.Language, slotCount int) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, slotCount+1)
+
+	for slot := 1; slot <= slotCount; slot++ {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(slotDoneButton(lang, slot)))
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		undoButton(lang),
+		eventsButton(lang),
+	))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// GetCheckInKeyboard confirms the presence in the queue from a private message.
+func GetCheckInKeyboard(lang i18n.Language, messageID string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(lang.Text(i18n.CheckInButton), callbackData(CheckInData, messageID)),
+	))
+}
+
+// GetEndedQueueKeyboard goes back to the last person. Queues with slots have no single last person, so the last slot
+// is brought back by undo.
+func GetEndedQueueKeyboard(lang i18n.Language, hasSlots bool) tgbotapi.InlineKeyboardMarkup {
+	back := previousButton(lang)
+	if hasSlots {
+		back = undoButton(lang)
+	}
+
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			goToMenuButton(),
+			back,
+			goToMenuButton(lang),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			endQueueButton(),
+			endQueueButton(lang),
 		),
 	)
 
 	return keyboard
 }
 
-func logInOurOutQueueButton() tgbotapi.InlineKeyboardButton {
-	return tgbotapi.NewInlineKeyboardButtonData(LogInOurOutButton, LogInOurOutData)
+// GetAdminsKeyboard has a button for every participant except the owner. Pressing it grants or revokes admin rights.
+func GetAdminsKeyboard(lang i18n.Language, queue entity.Queue) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(queue.Users)+1)
+
+	for _, user := range queue.Users {
+		if user.ID == queue.OwnerID {
+			continue
+		}
+
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(toggleAdminButton(user, queue.IsCoAdmin(user.ID))))
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(backButton(lang)))
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func logInOurOutQueueButton(lang i18n.Language) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(lang.Text(i18n.LogInOutButton), LogInOurOutData)
+}
+
+func startQueueButton(lang i18n.Language) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(lang.Text(i18n.StartQueueButton), StartQueueData)
+}
+
+func startQueueShuffleButton(lang i18n.Language) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(lang.Text(i18n.StartQueueShuffleButton), StartQueueShuffleData)
+}
+
+func nextButton(lang i18n.Language) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(lang.Text(i18n.NextButton), NextData)
+}
+
+func skipButton(lang i18n.Language) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(lang.Text(i18n.SkipButton), callbackData(SkipData, "1"))
+}
+
+func skipToEndButton(lang i18n.Language) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(lang.Text(i18n.SkipToEndButton), callbackData(SkipData, SkipToEndArg))
 }
 
-func startQueueButton() tgbotapi.InlineKeyboardButton {
-	return tgbotapi.NewInlineKeyboardButtonData(StartQueueButton, StartQueueData)
+func goToMenuButton(lang i18n.Language) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(lang.Text(i18n.GoToMenuButton), GoToMenuData)
 }
 
-func startQueueShuffleButton() tgbotapi.InlineKeyboardButton {
-	return tgbotapi.NewInlineKeyboardButtonData(StartQueueShuffleButton, StartQueueShuffleData)
+func endQueueButton(lang i18n.Language) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(lang.Text(i18n.FinishQueueButton), FinishQueueData)
+}
+
+func manageAdminsButton(lang i18n.Language) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(lang.Text(i18n.ManageAdminsButton), ManageAdminsData)
+}
+
+func toggleAdminButton(user entity.User, isAdmin bool) tgbotapi.InlineKeyboardButton {
+	text := user.Name
+	if isAdmin {
+		text = AdminMark + " " + text
+	}
+
+	return tgbotapi.NewInlineKeyboardButtonData(text, callbackData(ToggleAdminData, strconv.FormatInt(user.ID, 10)))
 }
 
-func nextButton() tgbotapi.InlineKeyboardButton {
-	return tgbotapi.NewInlineKeyboardButtonData(NextButton, NextData)
+func backButton(lang i18n.Language) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(lang.Text(i18n.GoToMenuButton), ShowMenuData)
+}
+
+func previousButton(lang i18n.Language) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(lang.Text(i18n.PreviousButton), PreviousData)
+}
+
+func undoButton(lang i18n.Language) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(lang.Text(i18n.UndoButton), UndoData)
+}
+
+func eventsButton(lang i18n.Language) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(lang.Text(i18n.EventsButton), EventsData)
+}
+
+func notifyCountButton(lang i18n.Language, notifyCount int) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(lang.Text(i18n.NotifyCountButton, notifyCount), NotifyCountData)
+}
+
+func turnDurationButton(lang i18n.Language, turnDuration time.Duration) tgbotapi.InlineKeyboardButton {
+	text := lang.Text(i18n.NoTurnDurationButton)
+	if turnDuration > 0 {
+		text = lang.Text(i18n.TurnDurationButton, int(turnDuration/time.Minute))
+	}
+
+	return tgbotapi.NewInlineKeyboardButtonData(text, TurnDurationData)
+}
+
+func slotCountButton(lang i18n.Language, slotCount int) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(lang.Text(i18n.SlotCountButton, slotCount), SlotCountData)
+}
+
+func slotDoneButton(lang i18n.Language, slot int) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(
+		lang.Text(i18n.SlotDoneButton, slot),
+		callbackData(SlotDoneData, strconv.Itoa(slot)),
+	)
+}
+
+func checkInTimeoutButton(lang i18n.Language, checkInTimeout time.Duration) tgbotapi.InlineKeyboardButton {
+	text := lang.Text(i18n.NoCheckInButton)
+	if checkInTimeout > 0 {
+		text = lang.Text(i18n.CheckInTimeoutButton, int(checkInTimeout/time.Minute))
+	}
+
+	return tgbotapi.NewInlineKeyboardButtonData(text, CheckInTimeoutData)
 }
 
-func goToMenuButton() tgbotapi.InlineKeyboardButton {
-	return tgbotapi.NewInlineKeyboardButtonData(GoToMenuButton, GoToMenuData)
+// checkInButton is pressed in the chat of the queue by people who haven't started the bot.
+func checkInButton(lang i18n.Language) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(lang.Text(i18n.CheckInButton), CheckInData)
 }
 
-func endQueueButton() tgbotapi.InlineKeyboardButton {
-	return tgbotapi.NewInlineKeyboardButtonData(FinishQueueButton, FinishQueueData)
+func languageButton(lang i18n.Language) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(lang.Text(i18n.LanguageButton), LanguageData)
 }
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCallbackData(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantAction string
+		wantArg    string
+	}{
+		{
+			name:       "Without argument",
+			data:       NextData,
+			wantAction: NextData,
+		},
+		{
+			name:       "With argument",
+			data:       callbackData(ToggleAdminData, "42"),
+			wantAction: ToggleAdminData,
+			wantArg:    "42",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, arg := ParseCallbackData(tt.data)
+			assert.Equal(t, tt.wantAction, action)
+			assert.Equal(t, tt.wantArg, arg)
+		})
+	}
+}
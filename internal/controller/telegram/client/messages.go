@@ -2,121 +2,493 @@ package client
 
 import (
 	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"QueueBot/internal/entity"
+	"QueueBot/internal/i18n"
 )
 
-const (
-	ForwardQueueToMessage = "Отлично! Теперь с помощью кнопки ниже вы можете переслать свою 'очередь'"
-	QueueDescription      = "В очереди состоят:"
-	EndedQueue            = "Участники закончились, значит и очередь тоже. Что делаем дальше?"
-	FinishedQueue         = "'Очередь' окончена 🎉"
-	ForwardQueueButton    = "Переслать 'очередь'"
-)
+// eventTimeLayout is how time of the event is shown in the events message.
+const eventTimeLayout = "02.01 15:04:05"
 
-func getMessageContentBeforeStart(title string, users []entity.User) string {
-	return fmt.Sprintf(
-		"*%s*\n%s\n%s",
-		title,
-		QueueDescription,
-		cutStringByLines(entity.ListToString(users), 26),
-	)
+// scheduleTimeLayout is how times of the schedule are shown in the queue message.
+const scheduleTimeLayout = "02.01 15:04"
+
+// finishedTimeLayout is how time when the queue was finished is shown in the history message.
+const finishedTimeLayout = "02.01.2006 15:04"
+
+var operationKeys = map[entity.Operation]i18n.Key{
+	entity.OperationCreate:         i18n.OperationCreate,
+	entity.OperationJoin:           i18n.OperationJoin,
+	entity.OperationLeave:          i18n.OperationLeave,
+	entity.OperationStart:          i18n.OperationStart,
+	entity.OperationShuffle:        i18n.OperationShuffle,
+	entity.OperationNext:           i18n.OperationNext,
+	entity.OperationPrevious:       i18n.OperationPrevious,
+	entity.OperationSkip:           i18n.OperationSkip,
+	entity.OperationStop:           i18n.OperationStop,
+	entity.OperationUndo:           i18n.OperationUndo,
+	entity.OperationFinish:         i18n.OperationFinish,
+	entity.OperationRemove:         i18n.OperationRemove,
+	entity.OperationWaitlist:       i18n.OperationWaitlist,
+	entity.OperationPromote:        i18n.OperationPromote,
+	entity.OperationSlotDone:       i18n.OperationSlotDone,
+	entity.OperationCheckIn:        i18n.OperationCheckIn,
+	entity.OperationCheckInMoved:   i18n.OperationCheckInMoved,
+	entity.OperationCheckInRemoved: i18n.OperationCheckInRemoved,
+	entity.OperationAddAdmin:       i18n.OperationAddAdmin,
+	entity.OperationRemoveAdmin:    i18n.OperationRemoveAdmin,
 }
 
-func getMessageContentAfterStart(title string, users []entity.User, currentPersonIndex int) string {
-	return fmt.Sprintf(
-		"*%s*\n%s\n%s",
-		title,
-		QueueDescription,
-		cutStringByLinesWithCurrent(entity.ListToStringWithCurrent(users, currentPersonIndex), 13, currentPersonIndex),
-	)
+// waitlistLines is how many waitlisted people the queue message shows.
+const waitlistLines = 10
+
+// getMessageContentBeforeStart renders the queue in HTML. Title and names are escaped, since they are typed by users.
+func getMessageContentBeforeStart(lang i18n.Language, queue entity.Queue) string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("<b>%s</b>\n", html.EscapeString(queue.Description)))
+
+	if queue.Capacity > 0 {
+		sb.WriteString(lang.Text(i18n.Places, len(queue.Users), queue.Capacity))
+		sb.WriteByte('\n')
+	}
+
+	writeSchedule(&sb, lang, queue.Schedule)
+	writeTurnDuration(&sb, lang, queue.TurnDuration)
+	writeCheckInTimeout(&sb, lang, queue.CheckInTimeout)
+	sb.WriteString(lang.Text(i18n.QueueDescription))
+	sb.WriteByte('\n')
+	sb.WriteString(html.EscapeString(cutStringByLines(entity.ListToString(queue.Users), 26)))
+	writeWaitlist(&sb, lang, queue.Waitlist)
+
+	return sb.String()
+}
+
+// getMessageContentAfterStart renders the started queue in HTML like getMessageContentBeforeStart.
+func getMessageContentAfterStart(lang i18n.Language, queue entity.Queue) string {
+	if queue.HasSlots() {
+		return getSlotsMessageContent(lang, queue)
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("<b>%s</b>\n", html.EscapeString(queue.Description)))
+	writeTurnDuration(&sb, lang, queue.TurnDuration)
+	writeCheckInTimeout(&sb, lang, queue.CheckInTimeout)
+	writeAwaitingCheckIn(&sb, lang, queue)
+	sb.WriteString(lang.Text(i18n.QueueDescription))
+	sb.WriteByte('\n')
+	sb.WriteString(html.EscapeString(cutStringByLinesWithCurrent(
+		entity.ListToStringWithCurrent(queue.Users, queue.CurrentPersonIdx), 13, queue.CurrentPersonIdx,
+	)))
+	writeWaitlist(&sb, lang, queue.Waitlist)
+
+	return sb.String()
+}
+
+// getSlotsMessageContent renders who is at every slot and who waits to be called.
+func getSlotsMessageContent(lang i18n.Language, queue entity.Queue) string {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("<b>%s</b>\n", html.EscapeString(queue.Description)))
+
+	for idx, user := range queue.Slots {
+		if user.ID == 0 {
+			sb.WriteString(lang.Text(i18n.FreeSlotLine, idx+1))
+		} else {
+			sb.WriteString(lang.Text(i18n.SlotLine, idx+1, html.EscapeString(user.Name)))
+		}
+
+		sb.WriteByte('\n')
+	}
+
+	if queue.CurrentPersonIdx < len(queue.Users) {
+		sb.WriteByte('\n')
+		writeAwaitingCheckIn(&sb, lang, queue)
+		sb.WriteString(lang.Text(i18n.WaitingTitle))
+		sb.WriteByte('\n')
+		sb.WriteString(html.EscapeString(cutStringByLines(entity.ListToString(queue.Users[queue.CurrentPersonIdx:]), 13)))
+	}
+
+	writeWaitlist(&sb, lang, queue.Waitlist)
+
+	return sb.String()
 }
 
-func GetQueueMessageContent(description string) tgbotapi.InputTextMessageContent {
+// writeSchedule appends lines with time when joining opens and when the queue starts, if they are set.
+func writeSchedule(sb *strings.Builder, lang i18n.Language, schedule entity.Schedule) {
+	if !schedule.OpensAt.IsZero() {
+		sb.WriteString(lang.Text(i18n.JoinOpensAt, FormatScheduleTime(schedule.OpensAt)))
+		sb.WriteByte('\n')
+	}
+
+	if schedule.StartsAt.IsZero() {
+		return
+	}
+
+	key := i18n.StartsAt
+	if schedule.IsShuffle {
+		key = i18n.ShuffleStartsAt
+	}
+
+	sb.WriteString(lang.Text(key, FormatScheduleTime(schedule.StartsAt)))
+	sb.WriteByte('\n')
+}
+
+// FormatScheduleTime shows the time of the schedule in the time zone of the bot.
+func FormatScheduleTime(t time.Time) string {
+	return t.Local().Format(scheduleTimeLayout)
+}
+
+// writeTurnDuration appends the line with time every person has, unless turns aren't timed.
+func writeTurnDuration(sb *strings.Builder, lang i18n.Language, duration time.Duration) {
+	if duration <= 0 {
+		return
+	}
+
+	sb.WriteString(lang.Text(i18n.TurnDuration, int(duration/time.Minute)))
+	sb.WriteByte('\n')
+}
+
+// writeCheckInTimeout appends the line with time people have to confirm their presence, unless it isn't asked.
+func writeCheckInTimeout(sb *strings.Builder, lang i18n.Language, timeout time.Duration) {
+	if timeout <= 0 {
+		return
+	}
+
+	sb.WriteString(lang.Text(i18n.CheckInTimeout, int(timeout/time.Minute)))
+	sb.WriteByte('\n')
+}
+
+// writeAwaitingCheckIn appends the line with people who were asked to confirm their presence and haven't done it yet.
+func writeAwaitingCheckIn(sb *strings.Builder, lang i18n.Language, queue entity.Queue) {
+	var names []string
+
+	for _, user := range queue.Users {
+		if checkIn := queue.CheckIns[user.ID]; !checkIn.AskedAt.IsZero() && !checkIn.IsConfirmed {
+			names = append(names, html.EscapeString(user.Name))
+		}
+	}
+
+	if len(names) == 0 {
+		return
+	}
+
+	sb.WriteString(lang.Text(i18n.AwaitingCheckIn, strings.Join(names, ", ")))
+	sb.WriteByte('\n')
+}
+
+// writeWaitlist appends waitlisted people, if there are any, after the queue.
+func writeWaitlist(sb *strings.Builder, lang i18n.Language, waitlist []entity.User) {
+	if len(waitlist) == 0 {
+		return
+	}
+
+	sb.WriteString("\n\n")
+	sb.WriteString(lang.Text(i18n.WaitlistTitle))
+	sb.WriteByte('\n')
+	sb.WriteString(html.EscapeString(cutStringByLines(entity.ListToString(waitlist), waitlistLines)))
+}
+
+// GetQueueMessageContent is the new queue before anyone joins it.
+func GetQueueMessageContent(lang i18n.Language, queue entity.Queue) tgbotapi.InputTextMessageContent {
 	answer := tgbotapi.InputTextMessageContent{
-		Text:      getMessageContentBeforeStart(description, nil),
-		ParseMode: tgbotapi.ModeMarkdown,
+		Text:      getMessageContentBeforeStart(lang, queue),
+		ParseMode: tgbotapi.ModeHTML,
 	}
 
 	return answer
 }
 
-func GetQueueMessage(messageID string, users []entity.User, description string) tgbotapi.EditMessageTextConfig {
-	keyboard := GetBeforeStartKeyboard()
+func GetQueueMessage(lang i18n.Language, messageID string, queue entity.Queue) tgbotapi.EditMessageTextConfig {
+	keyboard := GetBeforeStartKeyboard(lang, queue.NotifyCount, queue.TurnDuration, queue.SlotCount, queue.CheckInTimeout)
 	answer := tgbotapi.EditMessageTextConfig{
 		BaseEdit: tgbotapi.BaseEdit{
 			InlineMessageID: messageID,
 			ReplyMarkup:     &keyboard,
 		},
-		Text:      getMessageContentBeforeStart(description, users),
-		ParseMode: tgbotapi.ModeMarkdown,
+		Text:      getMessageContentBeforeStart(lang, queue),
+		ParseMode: tgbotapi.ModeHTML,
 	}
 
 	return answer
 }
 
-func GetUpdatedQueueMessage(messageID string, description string, users []entity.User) tgbotapi.EditMessageTextConfig {
-	keyboard := GetBeforeStartKeyboard()
+func GetUpdatedQueueMessage(lang i18n.Language, messageID string, queue entity.Queue) tgbotapi.EditMessageTextConfig {
+	keyboard := GetBeforeStartKeyboard(lang, queue.NotifyCount, queue.TurnDuration, queue.SlotCount, queue.CheckInTimeout)
 	answer := tgbotapi.EditMessageTextConfig{
 		BaseEdit: tgbotapi.BaseEdit{
 			InlineMessageID: messageID,
 			ReplyMarkup:     &keyboard,
 		},
-		Text:      getMessageContentBeforeStart(description, users),
-		ParseMode: tgbotapi.ModeMarkdown,
+		Text:      getMessageContentBeforeStart(lang, queue),
+		ParseMode: tgbotapi.ModeHTML,
 	}
 
 	return answer
 }
 
-func GetForwardMessage(chatID int64, description string) tgbotapi.MessageConfig {
-	answer := tgbotapi.NewMessage(chatID, ForwardQueueToMessage)
+func GetHelloMessage(lang i18n.Language, chatID int64) tgbotapi.MessageConfig {
+	return tgbotapi.NewMessage(chatID, lang.Text(i18n.Hello))
+}
+
+func GetEventsUsageMessage(lang i18n.Language, chatID int64) tgbotapi.MessageConfig {
+	return tgbotapi.NewMessage(chatID, lang.Text(i18n.EventsUsage))
+}
+
+func GetCreateUsageMessage(lang i18n.Language, chatID int64) tgbotapi.MessageConfig {
+	return tgbotapi.NewMessage(chatID, lang.Text(i18n.CreateUsage))
+}
+
+func GetScheduleUsageMessage(lang i18n.Language, chatID int64) tgbotapi.MessageConfig {
+	return tgbotapi.NewMessage(chatID, lang.Text(i18n.ScheduleUsage))
+}
+
+func GetForwardMessage(lang i18n.Language, chatID int64, description string) tgbotapi.MessageConfig {
+	answer := tgbotapi.NewMessage(chatID, lang.Text(i18n.ForwardQueueToMessage))
 	answer.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
-		tgbotapi.NewInlineKeyboardButtonSwitch(ForwardQueueButton, description),
+		tgbotapi.NewInlineKeyboardButtonSwitch(lang.Text(i18n.ForwardQueueButton), description),
 	))
-	answer.ParseMode = tgbotapi.ModeMarkdown
+	answer.ParseMode = tgbotapi.ModeHTML
 
 	return answer
 }
 
-func GetQueueAfterStartMessage(messageID string, description string, users []entity.User, currentPersonIndex int) tgbotapi.EditMessageTextConfig {
-	keyboard := GetAfterStartKeyboard()
+func GetQueueAfterStartMessage(lang i18n.Language, messageID string, queue entity.Queue) tgbotapi.EditMessageTextConfig {
+	keyboard := GetAfterStartKeyboard(lang)
+	if queue.HasSlots() {
+		keyboard = GetSlotsKeyboard(lang, queue.SlotCount)
+	}
+
+	if queue.CheckInTimeout > 0 {
+		keyboard.InlineKeyboard = append(
+			[][]tgbotapi.InlineKeyboardButton{tgbotapi.NewInlineKeyboardRow(checkInButton(lang))},
+			keyboard.InlineKeyboard...,
+		)
+	}
 
 	answer := tgbotapi.EditMessageTextConfig{
 		BaseEdit: tgbotapi.BaseEdit{
 			InlineMessageID: messageID,
 			ReplyMarkup:     &keyboard,
 		},
-		Text:      getMessageContentAfterStart(description, users, currentPersonIndex),
-		ParseMode: tgbotapi.ModeMarkdown,
+		Text:      getMessageContentAfterStart(lang, queue),
+		ParseMode: tgbotapi.ModeHTML,
 	}
 
 	return answer
 }
 
-func GetEndQueueMessage(messageID string) tgbotapi.EditMessageTextConfig {
-	keyboard := GetEndedQueueKeyboard()
+func GetEndQueueMessage(lang i18n.Language, messageID string, hasSlots bool) tgbotapi.EditMessageTextConfig {
+	keyboard := GetEndedQueueKeyboard(lang, hasSlots)
 
 	answer := tgbotapi.EditMessageTextConfig{
 		BaseEdit: tgbotapi.BaseEdit{
 			InlineMessageID: messageID,
 			ReplyMarkup:     &keyboard,
 		},
-		Text: EndedQueue,
+		Text: lang.Text(i18n.EndedQueue),
 	}
 
 	return answer
 }
 
-func GetFinishedMessage(messageID string) tgbotapi.EditMessageTextConfig {
+func GetFinishedMessage(lang i18n.Language, messageID string) tgbotapi.EditMessageTextConfig {
 	answer := tgbotapi.EditMessageTextConfig{
 		BaseEdit: tgbotapi.BaseEdit{
 			InlineMessageID: messageID,
 		},
-		Text: FinishedQueue,
+		Text: lang.Text(i18n.FinishedQueue),
 	}
 
 	return answer
 }
+
+func GetAdminsMessage(lang i18n.Language, messageID string, queue entity.Queue) tgbotapi.EditMessageTextConfig {
+	keyboard := GetAdminsKeyboard(lang, queue)
+
+	answer := tgbotapi.EditMessageTextConfig{
+		BaseEdit: tgbotapi.BaseEdit{
+			InlineMessageID: messageID,
+			ReplyMarkup:     &keyboard,
+		},
+		Text:      fmt.Sprintf("<b>%s</b>\n%s", html.EscapeString(queue.Description), lang.Text(i18n.ChooseAdmins)),
+		ParseMode: tgbotapi.ModeHTML,
+	}
+
+	return answer
+}
+
+// getEventLine links to the user who made the change. The ID is shown only when their name is unknown.
+func getEventLine(lang i18n.Language, event entity.Event) string {
+	description := string(event.Operation)
+	if key, ok := operationKeys[event.Operation]; ok {
+		description = lang.Text(key)
+	}
+
+	name := strconv.FormatInt(event.UserID, 10)
+	if event.UserName != "" {
+		name = html.EscapeString(event.UserName)
+	}
+
+	return fmt.Sprintf(
+		`%s <a href="tg://user?id=%d">%s</a> %s`,
+		event.CreatedAt.Format(eventTimeLayout),
+		event.UserID,
+		name,
+		description,
+	)
+}
+
+// GetEventsMessage lists events from the oldest to the newest one. Events are expected newest first.
+func GetEventsMessage(lang i18n.Language, chatID int64, events []entity.Event) tgbotapi.MessageConfig {
+	if len(events) == 0 {
+		return tgbotapi.NewMessage(chatID, lang.Text(i18n.NoEvents))
+	}
+
+	lines := make([]string, 0, len(events)+1)
+	lines = append(lines, lang.Text(i18n.EventsTitle))
+
+	for i := len(events) - 1; i >= 0; i-- {
+		lines = append(lines, getEventLine(lang, events[i]))
+	}
+
+	answer := tgbotapi.NewMessage(chatID, strings.Join(lines, "\n"))
+	answer.ParseMode = tgbotapi.ModeHTML
+
+	return answer
+}
+
+func getFinishedQueueLine(lang i18n.Language, queue entity.FinishedQueue) string {
+	mark := lang.Text(i18n.NotPassedMark)
+	if queue.Passed() {
+		mark = lang.Text(i18n.PassedMark)
+	}
+
+	return lang.Text(
+		i18n.HistoryLine,
+		html.EscapeString(queue.Description),
+		queue.FinishedAt.Format(finishedTimeLayout),
+		queue.Position+1,
+		queue.Participants,
+		mark,
+	)
+}
+
+func GetDashboardLinkMessage(lang i18n.Language, chatID int64, description string, link string) tgbotapi.MessageConfig {
+	answer := tgbotapi.NewMessage(chatID, lang.Text(i18n.DashboardLink, description, link))
+	answer.DisableWebPagePreview = true
+
+	return answer
+}
+
+func GetHistoryMessage(lang i18n.Language, chatID int64, queues []entity.FinishedQueue) tgbotapi.MessageConfig {
+	if len(queues) == 0 {
+		return tgbotapi.NewMessage(chatID, lang.Text(i18n.NoHistory))
+	}
+
+	lines := make([]string, 0, len(queues)+1)
+	lines = append(lines, lang.Text(i18n.HistoryTitle))
+
+	for _, queue := range queues {
+		lines = append(lines, getFinishedQueueLine(lang, queue))
+	}
+
+	answer := tgbotapi.NewMessage(chatID, strings.Join(lines, "\n"))
+	answer.ParseMode = tgbotapi.ModeHTML
+
+	return answer
+}
+
+// getTemplateLine describes the template and how many times it was posted. The message isn't HTML.
+func getTemplateLine(lang i18n.Language, template entity.Template) string {
+	parts := []string{lang.Text(i18n.TemplateLine, template.ID, template.Description)}
+
+	if template.Capacity > 0 {
+		parts = append(parts, lang.Text(i18n.TemplatePlaces, template.Capacity))
+	}
+
+	if template.IsScheduled() {
+		key := i18n.TemplateSchedule
+		if template.IsShuffle {
+			key = i18n.TemplateShuffleSchedule
+		}
+
+		parts = append(parts, lang.Text(key, template.OpensAt, template.StartsAt))
+	}
+
+	parts = append(parts, lang.Text(i18n.TemplateInstances, template.Instances))
+
+	return strings.Join(parts, ", ")
+}
+
+// GetTemplatesMessage lists templates of the user with buttons which post queues from them, then tells how to manage templates.
+func GetTemplatesMessage(lang i18n.Language, chatID int64, templates []entity.Template) tgbotapi.MessageConfig {
+	lines := []string{lang.Text(i18n.NoTemplates)}
+	if len(templates) > 0 {
+		lines = []string{lang.Text(i18n.TemplatesTitle)}
+	}
+
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(templates))
+
+	for _, template := range templates {
+		lines = append(lines, getTemplateLine(lang, template))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonSwitch(lang.Text(i18n.PostTemplateButton, template.Description), TemplateQuery(template.ID)),
+		))
+	}
+
+	lines = append(lines, "", lang.Text(i18n.TemplatesUsage))
+
+	answer := tgbotapi.NewMessage(chatID, strings.Join(lines, "\n"))
+	if len(rows) > 0 {
+		answer.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	}
+
+	return answer
+}
+
+func GetTemplatesUsageMessage(lang i18n.Language, chatID int64) tgbotapi.MessageConfig {
+	return tgbotapi.NewMessage(chatID, lang.Text(i18n.TemplatesUsage))
+}
+
+func GetTemplatesPrivateOnlyMessage(lang i18n.Language, chatID int64) tgbotapi.MessageConfig {
+	return tgbotapi.NewMessage(chatID, lang.Text(i18n.TemplatesPrivateOnly))
+}
+
+func GetTemplateNotFoundMessage(lang i18n.Language, chatID int64, templateID int64) tgbotapi.MessageConfig {
+	return tgbotapi.NewMessage(chatID, lang.Text(i18n.TemplateNotFound, templateID))
+}
+
+// GetTurnEndingMessage warns the current person that the queue advances in left time. Minutes are rounded up.
+func GetTurnEndingMessage(lang i18n.Language, userID int64, description string, left time.Duration) tgbotapi.MessageConfig {
+	minutes := int((left + time.Minute - 1) / time.Minute)
+
+	return tgbotapi.NewMessage(userID, lang.Text(i18n.TurnEnding, description, minutes))
+}
+
+// GetCheckInMessage asks the participant whose turn is coming to confirm their presence within timeout.
+func GetCheckInMessage(
+	lang i18n.Language,
+	userID int64,
+	messageID string,
+	description string,
+	timeout time.Duration,
+) tgbotapi.MessageConfig {
+	answer := tgbotapi.NewMessage(userID, lang.Text(i18n.CheckInRequest, description, int(timeout/time.Minute)))
+	answer.ReplyMarkup = GetCheckInKeyboard(lang, messageID)
+
+	return answer
+}
+
+// GetTurnMessage is a private message to the participant whose turn is coming.
+func GetTurnMessage(lang i18n.Language, userID int64, description string, peopleBefore int) tgbotapi.MessageConfig {
+	if peopleBefore == 0 {
+		return tgbotapi.NewMessage(userID, lang.Text(i18n.YourTurn, description))
+	}
+
+	return tgbotapi.NewMessage(userID, lang.Text(i18n.PeopleBeforeYou, description, peopleBefore))
+}
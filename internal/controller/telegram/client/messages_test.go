@@ -0,0 +1,290 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/i18n"
+)
+
+func TestGetMessageContentBeforeStart(t *testing.T) {
+	tests := []struct {
+		name  string
+		queue entity.Queue
+		want  string
+	}{
+		{
+			name: "Plain names",
+			queue: entity.Queue{
+				Description: "Лаба 3",
+				Users:       []entity.User{{ID: 1, Name: "Иванов Иван"}, {ID: 2, Name: "Петров Петр"}},
+			},
+			want: "<b>Лаба 3</b>\nВ очереди состоят:\nИванов Иван\nПетров Петр",
+		},
+		{
+			name: "Markdown in title and names",
+			queue: entity.Queue{
+				Description: "lab_3 *final*",
+				Users:       []entity.User{{ID: 1, Name: "snake_case"}, {ID: 2, Name: "*star* [link](x) `code`"}},
+			},
+			want: "<b>lab_3 *final*</b>\nВ очереди состоят:\nsnake_case\n*star* [link](x) `code`",
+		},
+		{
+			name: "HTML in title and names",
+			queue: entity.Queue{
+				Description: "<b>Лаба</b> & co",
+				Users:       []entity.User{{ID: 1, Name: `<a href="x">Иван</a>`}, {ID: 2, Name: "</b>"}},
+			},
+			want: "<b>&lt;b&gt;Лаба&lt;/b&gt; &amp; co</b>\nВ очереди состоят:\n" +
+				"&lt;a href=&#34;x&#34;&gt;Иван&lt;/a&gt;\n&lt;/b&gt;",
+		},
+		{
+			name:  "Without users",
+			queue: entity.Queue{Description: "Лаба 3"},
+			want:  "<b>Лаба 3</b>\nВ очереди состоят:\n",
+		},
+		{
+			name: "With capacity and waitlist",
+			queue: entity.Queue{
+				Description: "Лаба 3",
+				Users:       []entity.User{{ID: 1, Name: "Иванов Иван"}},
+				Capacity:    1,
+				Waitlist:    []entity.User{{ID: 2, Name: "<Петров>"}},
+			},
+			want: "<b>Лаба 3</b>\nЗанято мест: 1 из 1\nВ очереди состоят:\nИванов Иван\n\n" +
+				"Лист ожидания:\n&lt;Петров&gt;",
+		},
+		{
+			name: "Scheduled in random order",
+			queue: entity.Queue{
+				Description: "Лаба 3",
+				Schedule: entity.Schedule{
+					OpensAt:   time.Date(2024, time.March, 1, 8, 0, 0, 0, time.Local),
+					StartsAt:  time.Date(2024, time.March, 1, 9, 30, 0, 0, time.Local),
+					IsShuffle: true,
+				},
+			},
+			want: "<b>Лаба 3</b>\nЗапись откроется в 01.03 08:00\nСтарт в случайном порядке в 01.03 09:30\nВ очереди состоят:\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getMessageContentBeforeStart(i18n.Russian, tt.queue))
+		})
+	}
+}
+
+func TestGetMessageContentAfterStart(t *testing.T) {
+	tests := []struct {
+		name  string
+		lang  i18n.Language
+		queue entity.Queue
+		want  string
+	}{
+		{
+			name: "Current person is marked",
+			lang: i18n.Russian,
+			queue: entity.Queue{
+				Description:      "Лаба 3",
+				Users:            []entity.User{{ID: 1, Name: "Иванов Иван"}, {ID: 2, Name: "Петров Петр"}},
+				CurrentPersonIdx: 1,
+			},
+			want: "<b>Лаба 3</b>\nВ очереди состоят:\nИванов Иван\n-&gt; Петров Петр &lt;-",
+		},
+		{
+			name: "Markdown in names",
+			lang: i18n.Russian,
+			queue: entity.Queue{
+				Description: "lab_3",
+				Users:       []entity.User{{ID: 1, Name: "_under_"}, {ID: 2, Name: "**bold**"}},
+			},
+			want: "<b>lab_3</b>\nВ очереди состоят:\n-&gt; _under_ &lt;-\n**bold**",
+		},
+		{
+			name: "HTML in names",
+			lang: i18n.Russian,
+			queue: entity.Queue{
+				Description:      "a < b",
+				Users:            []entity.User{{ID: 1, Name: "<i>Иван</i>"}, {ID: 2, Name: "&amp;"}},
+				CurrentPersonIdx: 1,
+			},
+			want: "<b>a &lt; b</b>\nВ очереди состоят:\n&lt;i&gt;Иван&lt;/i&gt;\n-&gt; &amp;amp; &lt;-",
+		},
+		{
+			name: "In English with waitlist",
+			lang: i18n.English,
+			queue: entity.Queue{
+				Description: "Lab 3",
+				Users:       []entity.User{{ID: 1, Name: "John"}},
+				Capacity:    1,
+				Waitlist:    []entity.User{{ID: 2, Name: "Jane"}, {ID: 3, Name: "Bob"}},
+			},
+			want: "<b>Lab 3</b>\nIn the queue:\n-&gt; John &lt;-\n\nWaitlist:\nJane\nBob",
+		},
+		{
+			name: "With turn duration",
+			lang: i18n.Russian,
+			queue: entity.Queue{
+				Description:  "Лаба 3",
+				Users:        []entity.User{{ID: 1, Name: "Иванов Иван"}},
+				TurnDuration: 7 * time.Minute,
+			},
+			want: "<b>Лаба 3</b>\nВремя на человека: 7 мин\nВ очереди состоят:\n-&gt; Иванов Иван &lt;-",
+		},
+		{
+			name: "With check-in",
+			lang: i18n.Russian,
+			queue: entity.Queue{
+				Description: "Лаба 3",
+				Users: []entity.User{
+					{ID: 1, Name: "Иванов Иван"}, {ID: 2, Name: "<b>Петр</b>"}, {ID: 3, Name: "Сидоров Сидор"},
+				},
+				CheckInTimeout: 5 * time.Minute,
+				CheckIns: map[int64]entity.CheckIn{
+					1: {AskedAt: time.Unix(0, 0), IsConfirmed: true},
+					2: {AskedAt: time.Unix(0, 0)},
+					3: {Missed: 1},
+				},
+			},
+			want: "<b>Лаба 3</b>\nПодтверждение присутствия: 5 мин\n✋ Ждём подтверждения: &lt;b&gt;Петр&lt;/b&gt;\n" +
+				"В очереди состоят:\n-&gt; Иванов Иван &lt;-\n&lt;b&gt;Петр&lt;/b&gt;\nСидоров Сидор",
+		},
+		{
+			name: "With slots",
+			lang: i18n.Russian,
+			queue: entity.Queue{
+				Description: "Лаба 3",
+				Users: []entity.User{
+					{ID: 1, Name: "Иванов Иван"}, {ID: 2, Name: "<b>Петр</b>"}, {ID: 3, Name: "Сидоров Сидор"},
+				},
+				CurrentPersonIdx: 2,
+				SlotCount:        3,
+				Slots:            []entity.User{{ID: 2, Name: "<b>Петр</b>"}, {}, {ID: 1, Name: "Иванов Иван"}},
+			},
+			want: "<b>Лаба 3</b>\nОкно 1: &lt;b&gt;Петр&lt;/b&gt;\nОкно 2: свободно\nОкно 3: Иванов Иван\n\nОжидают:\nСидоров Сидор",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getMessageContentAfterStart(tt.lang, tt.queue))
+		})
+	}
+}
+
+func TestGetEventsMessage(t *testing.T) {
+	createdAt := time.Date(2024, time.March, 5, 14, 3, 12, 0, time.UTC)
+	events := []entity.Event{
+		{Operation: entity.OperationJoin, UserID: 2, UserName: "Анна <3", CreatedAt: createdAt.Add(time.Minute)},
+		{Operation: entity.OperationCreate, UserID: 1, CreatedAt: createdAt},
+	}
+
+	tests := []struct {
+		name   string
+		lang   i18n.Language
+		events []entity.Event
+		want   string
+	}{
+		{
+			name: "Without events",
+			lang: i18n.Russian,
+			want: "В журнале очереди пока нет событий",
+		},
+		{
+			name:   "Oldest event first",
+			lang:   i18n.Russian,
+			events: events,
+			want: "Последние события очереди:\n" +
+				`05.03 14:03:12 <a href="tg://user?id=1">1</a> создал(а) очередь` + "\n" +
+				`05.03 14:04:12 <a href="tg://user?id=2">Анна &lt;3</a> встал(а) в очередь`,
+		},
+		{
+			name:   "In English",
+			lang:   i18n.English,
+			events: events,
+			want: "Latest events of the queue:\n" +
+				`05.03 14:03:12 <a href="tg://user?id=1">1</a> created the queue` + "\n" +
+				`05.03 14:04:12 <a href="tg://user?id=2">Анна &lt;3</a> joined the queue`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetEventsMessage(tt.lang, 1, tt.events).Text)
+		})
+	}
+}
+
+func TestGetHistoryMessage(t *testing.T) {
+	finishedAt := time.Date(2024, time.March, 5, 14, 3, 12, 0, time.UTC)
+	queues := []entity.FinishedQueue{
+		{Description: "Лаба 3", Position: 1, Participants: 15, CurrentPersonIdx: 15, FinishedAt: finishedAt},
+		{Description: "<Лаба 2>", Position: 4, Participants: 5, CurrentPersonIdx: 3, FinishedAt: finishedAt},
+	}
+
+	tests := []struct {
+		name   string
+		lang   i18n.Language
+		queues []entity.FinishedQueue
+		want   string
+	}{
+		{
+			name: "Without queues",
+			lang: i18n.Russian,
+			want: "Вы еще не были в законченных очередях",
+		},
+		{
+			name:   "Passed and not passed",
+			lang:   i18n.Russian,
+			queues: queues,
+			want: "Ваши прошедшие очереди:\n" +
+				"<b>Лаба 3</b> — 05.03.2024 14:03, место 2 из 15, прошел(ла)\n" +
+				"<b>&lt;Лаба 2&gt;</b> — 05.03.2024 14:03, место 5 из 5, не успел(а)",
+		},
+		{
+			name:   "In English",
+			lang:   i18n.English,
+			queues: queues,
+			want: "Your past queues:\n" +
+				"<b>Лаба 3</b> — 05.03.2024 14:03, place 2 of 15, passed\n" +
+				"<b>&lt;Лаба 2&gt;</b> — 05.03.2024 14:03, place 5 of 5, didn't make it",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetHistoryMessage(tt.lang, 1, tt.queues).Text)
+		})
+	}
+}
+
+func TestGetTemplateLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		template entity.Template
+		want     string
+	}{
+		{
+			name:     "Unscheduled",
+			template: entity.Template{ID: 1, Description: "Лаба 3", Instances: 2},
+			want:     "№1 «Лаба 3», опубликован раз: 2",
+		},
+		{
+			name: "Scheduled in random order",
+			template: entity.Template{
+				ID:          3,
+				Description: "Лаба по ОС",
+				Capacity:    15,
+				OpensAt:     "08:00",
+				StartsAt:    "09:00",
+				IsShuffle:   true,
+			},
+			want: "№3 «Лаба по ОС», мест: 15, запись с 08:00, старт в случайном порядке в 09:00, опубликован раз: 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getTemplateLine(i18n.Russian, tt.template))
+		})
+	}
+}
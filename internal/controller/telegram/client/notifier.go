@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"QueueBot/internal/i18n"
+	"QueueBot/internal/usecase"
+)
+
+// Notifier sends private messages about turns in queues. It implements usecase.Notifier.
+type Notifier struct {
+	tgBot *tgbotapi.BotAPI
+}
+
+func NewNotifier(tgBot *tgbotapi.BotAPI) *Notifier {
+	return &Notifier{tgBot: tgBot}
+}
+
+func (n Notifier) NotifyTurn(_ context.Context, userID int64, description string, language string, peopleBefore int) error {
+	return n.send(userID, GetTurnMessage(i18n.Language(language), userID, description, peopleBefore))
+}
+
+func (n Notifier) NotifyTurnEnding(
+	_ context.Context,
+	userID int64,
+	description string,
+	language string,
+	left time.Duration,
+) error {
+	return n.send(userID, GetTurnEndingMessage(i18n.Language(language), userID, description, left))
+}
+
+func (n Notifier) AskCheckIn(
+	_ context.Context,
+	userID int64,
+	messageID string,
+	description string,
+	language string,
+	timeout time.Duration,
+) error {
+	return n.send(userID, GetCheckInMessage(i18n.Language(language), userID, messageID, description, timeout))
+}
+
+func (n Notifier) send(userID int64, message tgbotapi.MessageConfig) error {
+	_, err := n.tgBot.Send(message)
+
+	// Telegram answers with 403 when user has blocked the bot.
+	var tgErr *tgbotapi.Error
+	if errors.As(err, &tgErr) && tgErr.Code == http.StatusForbidden {
+		return fmt.Errorf("couldn't notify user %d: %w", userID, usecase.ErrUserUnreachable)
+	}
+
+	if err != nil {
+		return fmt.Errorf("couldn't send notification in telegram with error: %w", err)
+	}
+
+	return nil
+}
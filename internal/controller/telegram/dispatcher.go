@@ -0,0 +1,100 @@
+package telegram
+
+import (
+	"errors"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+)
+
+// ErrDispatcherClosed is returned by Dispatch once shutdown has started.
+var ErrDispatcherClosed = errors.New("dispatcher is closed")
+
+// Dispatcher runs tasks with the same key one after another, in the order they were dispatched.
+// Tasks with different keys run in parallel.
+type Dispatcher struct {
+	mu sync.Mutex
+	// pending tasks by key. A key is present while a worker for it is running.
+	pending  map[string][]func()
+	isClosed bool
+	wg       sync.WaitGroup
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{pending: make(map[string][]func())}
+}
+
+// Dispatch schedules task. Tasks with empty key aren't serialized with anything.
+// After Close the task isn't run and ErrDispatcherClosed is returned.
+func (d *Dispatcher) Dispatch(key string, task func()) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Tasks are counted under the lock, so none is added after Close while Wait is running.
+	if d.isClosed {
+		return ErrDispatcherClosed
+	}
+
+	d.wg.Add(1)
+
+	if key == "" {
+		go d.runTask(task)
+
+		return nil
+	}
+
+	tasks, isRunning := d.pending[key]
+	d.pending[key] = append(tasks, task)
+
+	if !isRunning {
+		go d.run(key)
+	}
+
+	return nil
+}
+
+// Close stops accepting tasks. Already dispatched tasks still run.
+func (d *Dispatcher) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.isClosed = true
+}
+
+// Wait blocks until all dispatched tasks are done. It's called after Close, so no task is dispatched meanwhile.
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) run(key string) {
+	for {
+		d.mu.Lock()
+		tasks := d.pending[key]
+		if len(tasks) == 0 {
+			delete(d.pending, key)
+			d.mu.Unlock()
+
+			return
+		}
+
+		task := tasks[0]
+		d.pending[key] = tasks[1:]
+		d.mu.Unlock()
+
+		d.runTask(task)
+	}
+}
+
+// runTask runs task and marks it done. A panic in the task is logged, so later tasks with its key still run
+// and Wait doesn't block forever.
+func (d *Dispatcher) runTask(task func()) {
+	defer d.wg.Done()
+
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Task panicked", "reason", r, "stack", string(debug.Stack()))
+		}
+	}()
+
+	task()
+}
@@ -0,0 +1,119 @@
+package telegram
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatcher_SameKeyInOrder(t *testing.T) {
+	d := NewDispatcher()
+
+	var mu sync.Mutex
+	var got []int
+
+	for i := 0; i < 100; i++ {
+		i := i
+		require.NoError(t, d.Dispatch("key", func() {
+			mu.Lock()
+			defer mu.Unlock()
+
+			got = append(got, i)
+		}))
+	}
+
+	d.Wait()
+
+	assert.Len(t, got, 100)
+	for i, value := range got {
+		assert.Equal(t, i, value)
+	}
+}
+
+func TestDispatcher_TaskPanics(t *testing.T) {
+	d := NewDispatcher()
+
+	done := make(chan struct{})
+
+	require.NoError(t, d.Dispatch("key", func() { panic("test") }))
+	require.NoError(t, d.Dispatch("key", func() { close(done) }))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("task after the panicked one isn't run")
+	}
+
+	d.Close()
+	d.Wait()
+}
+
+func TestDispatcher_DifferentKeysInParallel(t *testing.T) {
+	d := NewDispatcher()
+
+	secondStarted := make(chan struct{})
+	firstDone := make(chan bool, 1)
+
+	require.NoError(t, d.Dispatch("first", func() {
+		select {
+		case <-secondStarted:
+			firstDone <- true
+		case <-time.After(time.Second):
+			firstDone <- false
+		}
+	}))
+	require.NoError(t, d.Dispatch("second", func() {
+		close(secondStarted)
+	}))
+
+	d.Wait()
+
+	assert.True(t, <-firstDone, "task of another key didn't run while the first one was blocked")
+}
+
+func TestDispatcher_WithoutKey(t *testing.T) {
+	d := NewDispatcher()
+
+	started := make(chan struct{})
+	done := make(chan bool, 1)
+
+	require.NoError(t, d.Dispatch("", func() {
+		select {
+		case <-started:
+			done <- true
+		case <-time.After(time.Second):
+			done <- false
+		}
+	}))
+	require.NoError(t, d.Dispatch("", func() {
+		close(started)
+	}))
+
+	d.Wait()
+
+	assert.True(t, <-done, "tasks without key were serialized")
+}
+
+func TestDispatcher_Close(t *testing.T) {
+	d := NewDispatcher()
+
+	release := make(chan struct{})
+	done := false
+
+	require.NoError(t, d.Dispatch("key", func() {
+		<-release
+		done = true
+	}))
+
+	d.Close()
+	assert.ErrorIs(t, d.Dispatch("key", func() {}), ErrDispatcherClosed)
+	assert.ErrorIs(t, d.Dispatch("", func() {}), ErrDispatcherClosed)
+
+	close(release)
+	d.Wait()
+
+	assert.True(t, done, "task dispatched before Close didn't run")
+}
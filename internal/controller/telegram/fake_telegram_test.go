@@ -0,0 +1,172 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/require"
+
+	"QueueBot/internal/controller/telegram/client"
+	"QueueBot/internal/usecase"
+	"QueueBot/internal/usecase/storage"
+)
+
+// fakeTelegram answers Bot API requests and remembers texts of edited inline messages and sent messages.
+type fakeTelegram struct {
+	mu    sync.Mutex
+	edits map[string][]string
+	sent  []string
+	// updates are returned by getUpdates until they are confirmed with the offset.
+	updates []int
+	// offsets are sent with getUpdates, the latest one confirms all updates before it.
+	offsets []int
+}
+
+func (f *fakeTelegram) Do(r *http.Request) (*http.Response, error) {
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+
+	// Random delay lets handlers of different updates interleave the way they do with real network.
+	time.Sleep(time.Duration(rand.Intn(2000)) * time.Microsecond)
+
+	result := "true"
+
+	switch path.Base(r.URL.Path) {
+	case "getMe":
+		result = `{"id": 1, "is_bot": true, "first_name": "QueueBot", "username": "queue_bot"}`
+	case "getUpdates":
+		result = f.getUpdates(r.PostForm.Get("offset"))
+	case "sendMessage":
+		f.mu.Lock()
+		f.sent = append(f.sent, r.PostForm.Get("text"))
+		f.mu.Unlock()
+
+		result = `{"message_id": 1, "date": 0, "chat": {"id": 1, "type": "private"}}`
+	case "editMessageText":
+		f.mu.Lock()
+		f.edits[r.PostForm.Get("inline_message_id")] = append(f.edits[r.PostForm.Get("inline_message_id")], r.PostForm.Get("text"))
+		f.mu.Unlock()
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(`{"ok": true, "result": ` + result + `}`)),
+	}, nil
+}
+
+func (f *fakeTelegram) getUpdates(offsetParam string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Offset isn't sent while it's zero.
+	offset, _ := strconv.Atoi(offsetParam)
+	f.offsets = append(f.offsets, offset)
+
+	updates := make([]string, 0, len(f.updates))
+
+	for _, id := range f.updates {
+		if id >= offset {
+			updates = append(updates, fmt.Sprintf(`{"update_id": %d}`, id))
+		}
+	}
+
+	return "[" + strings.Join(updates, ",") + "]"
+}
+
+func (f *fakeTelegram) lastOffset() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.offsets) == 0 {
+		return 0
+	}
+
+	return f.offsets[len(f.offsets)-1]
+}
+
+func (f *fakeTelegram) lastEdit(messageID string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	edits := f.edits[messageID]
+	if len(edits) == 0 {
+		return ""
+	}
+
+	return edits[len(edits)-1]
+}
+
+// testEditDebounce is short enough for tests and long enough for edits of concurrent callbacks to coalesce.
+const testEditDebounce = 5 * time.Millisecond
+
+// newTestServer returns a server talking to the fake Telegram. Pending edits are sent when the test ends.
+func newTestServer(t *testing.T, s storage.Storage) (*BotServer, *fakeTelegram, *client.EditScheduler) {
+	t.Helper()
+
+	fake := &fakeTelegram{edits: make(map[string][]string)}
+
+	botAPI, err := tgbotapi.NewBotAPIWithClient("token", tgbotapi.APIEndpoint, fake)
+	require.NoError(t, err)
+
+	edits := client.NewEditScheduler(botAPI, testEditDebounce)
+	t.Cleanup(edits.Close)
+
+	return NewBotServer(client.NewTelegramBot(botAPI, usecase.NewBotUseCase(s, nil), edits, nil), time.Second), fake, edits
+}
+
+// channelSource passes updates from the channel to BotServer.Listen.
+type channelSource chan tgbotapi.Update
+
+func (c channelSource) Start(context.Context, chan<- error) (tgbotapi.UpdatesChannel, error) {
+	return (chan tgbotapi.Update)(c), nil
+}
+
+// listen runs server until all updates sent by send are handled and returns errors reported by handlers.
+func listen(server *BotServer, send func(updates chan<- tgbotapi.Update)) []error {
+	updates := make(channelSource)
+	errChan := make(chan error)
+	done := make(chan struct{})
+
+	var errs []error
+
+	go func() {
+		for err := range errChan {
+			errs = append(errs, err)
+		}
+		close(done)
+	}()
+
+	go func() {
+		send(updates)
+		close(updates)
+	}()
+
+	server.Listen(context.Background(), updates, errChan)
+	close(errChan)
+	<-done
+
+	return errs
+}
+
+func callbackUpdate(messageID string, userID int64, data string) tgbotapi.Update {
+	return tgbotapi.Update{
+		CallbackQuery: &tgbotapi.CallbackQuery{
+			ID:              "callback",
+			From:            &tgbotapi.User{ID: userID, FirstName: "User"},
+			InlineMessageID: messageID,
+			Data:            data,
+		},
+	}
+}
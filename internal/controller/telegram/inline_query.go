@@ -3,28 +3,136 @@ package telegram
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"QueueBot/internal/controller/telegram/client"
+	"QueueBot/internal/entity"
+	"QueueBot/internal/i18n"
+	"QueueBot/internal/usecase/storage"
 )
 
-const CreateQueue = "Создать очередь"
+// createQueryPrefix starts queries of queues with limited capacity, e.g. "/create 15 Лаба 3".
+// It is the /create command, so the forward button of the command message creates such a queue.
+const createQueryPrefix = "/" + CreateCommand + " "
 
-func (s BotServer) HandleInlineQuery(inlineQuery *tgbotapi.InlineQuery) error {
-	article := tgbotapi.NewInlineQueryResultArticle(inlineQuery.ID, CreateQueue, fmt.Sprintf("С описанием: %s", inlineQuery.Query))
-	article.InputMessageContent = client.GetQueueMessageContent(inlineQuery.Query)
+// scheduleQueryPrefix starts queries of scheduled queues, e.g. "/schedule 08:00 09:00 shuffle Лаба 3".
+const scheduleQueryPrefix = "/" + ScheduleCommand + " "
 
-	keyboard := client.GetBeforeStartKeyboard()
+// shuffleArg makes the scheduled queue start in random order.
+const shuffleArg = "shuffle"
+
+// queueQuery is the queue the inline query creates.
+type queueQuery struct {
+	description string
+	capacity    int
+	schedule    entity.Schedule
+	// templateID is the template the queue is posted from, zero for other queries.
+	templateID int64
+}
+
+// queue is the new queue before anyone joins it.
+func (q queueQuery) queue() entity.Queue {
+	return entity.Queue{Description: q.description, Capacity: q.capacity, Schedule: q.schedule, TemplateID: q.templateID}
+}
+
+// parseQueueQuery returns the queue the inline query creates. Times of the schedule are the nearest ones after now.
+// Queries without a valid "/create" or "/schedule" prefix are descriptions of unlimited queues.
+func parseQueueQuery(query string, now time.Time) queueQuery {
+	if args, ok := strings.CutPrefix(query, createQueryPrefix); ok {
+		if description, capacity, ok := parseCreateArgs(args); ok {
+			return queueQuery{description: description, capacity: capacity}
+		}
+	}
+
+	if args, ok := strings.CutPrefix(query, scheduleQueryPrefix); ok {
+		if description, schedule, ok := parseScheduleArgs(args, now); ok {
+			return queueQuery{description: description, schedule: schedule}
+		}
+	}
+
+	return queueQuery{description: query}
+}
+
+// parseCreateArgs splits "<capacity> <description>" arguments of the /create command.
+func parseCreateArgs(args string) (description string, capacity int, ok bool) {
+	rawCapacity, description, _ := strings.Cut(strings.TrimSpace(args), " ")
+	description = strings.TrimSpace(description)
+
+	capacity, err := strconv.Atoi(rawCapacity)
+	if err != nil || capacity <= 0 || description == "" {
+		return "", 0, false
+	}
+
+	return description, capacity, true
+}
+
+// parseScheduleArgs splits "<opens> <starts> [shuffle] <description>" arguments of the /schedule command.
+// Joining opens at the nearest time after now and the queue starts at the nearest time after that.
+func parseScheduleArgs(args string, now time.Time) (description string, schedule entity.Schedule, ok bool) {
+	rawOpensAt, rest, _ := strings.Cut(strings.TrimSpace(args), " ")
+	rawStartsAt, description, _ := strings.Cut(strings.TrimSpace(rest), " ")
+	description = strings.TrimSpace(description)
+
+	isShuffle := false
+	if first, rest, _ := strings.Cut(description, " "); first == shuffleArg {
+		isShuffle = true
+		description = strings.TrimSpace(rest)
+	}
+
+	template := entity.Template{OpensAt: rawOpensAt, StartsAt: rawStartsAt, IsShuffle: isShuffle}
+
+	schedule, err := template.Schedule(now)
+	if err != nil || description == "" {
+		return "", entity.Schedule{}, false
+	}
+
+	return description, schedule, true
+}
+
+func (s BotServer) HandleInlineQuery(ctx context.Context, inlineQuery *tgbotapi.InlineQuery) error {
+	// The queue is created in the language of its owner, see TelegramBot.CreateQueue.
+	lang := client.UserLanguage(inlineQuery.From)
+
+	query, err := s.resolveQueueQuery(ctx, inlineQuery.Query, inlineQuery.From, time.Now())
+	if err != nil {
+		return err
+	}
+
+	articleDescription := lang.Text(i18n.CreateQueueDescription, query.description)
+
+	switch {
+	case query.capacity > 0:
+		articleDescription = lang.Text(i18n.CreateLimitedQueueDescription, query.description, query.capacity)
+	case !query.schedule.IsZero():
+		articleDescription = lang.Text(
+			i18n.CreateScheduledQueueDescription, query.description, client.FormatScheduleTime(query.schedule.StartsAt),
+		)
+	}
+
+	article := tgbotapi.NewInlineQueryResultArticle(inlineQuery.ID, lang.Text(i18n.CreateQueue), articleDescription)
+	article.InputMessageContent = client.GetQueueMessageContent(lang, query.queue())
+
+	keyboard := client.GetBeforeStartKeyboard(lang, storage.DefaultNotifyCount, 0, storage.DefaultSlotCount, 0)
 	article.ReplyMarkup = &keyboard
 
+	// The result is in the language of the user, so it's cached only for them.
 	inlineConf := tgbotapi.InlineConfig{
 		InlineQueryID: inlineQuery.ID,
 		CacheTime:     9999,
+		IsPersonal:    true,
 		Results:       []interface{}{article},
 	}
 
-	_, err := s.bot.TgBot.Request(inlineConf)
+	// Times of scheduled queues move on and templates may change, so such results aren't cached.
+	if !query.schedule.IsZero() || query.templateID != 0 {
+		inlineConf.CacheTime = 0
+	}
+
+	_, err = s.bot.TgBot.Request(inlineConf)
 	if err != nil {
 		return fmt.Errorf("couldn't handle inline query with error: %w", err)
 	}
@@ -32,13 +140,18 @@ func (s BotServer) HandleInlineQuery(inlineQuery *tgbotapi.InlineQuery) error {
 	return nil
 }
 
-func (s BotServer) HandleChosenInlineResult(chosenInlineResult *tgbotapi.ChosenInlineResult) error {
+func (s BotServer) HandleChosenInlineResult(ctx context.Context, chosenInlineResult *tgbotapi.ChosenInlineResult) error {
+	query, err := s.resolveQueueQuery(ctx, chosenInlineResult.Query, chosenInlineResult.From, time.Now())
+	if err != nil {
+		return err
+	}
+
 	// Обрубаем слишком длинные описания
-	if len(chosenInlineResult.Query) > 100 {
-		chosenInlineResult.Query = chosenInlineResult.Query[:100]
+	if len(query.description) > 100 {
+		query.description = query.description[:100]
 	}
 
-	if err := s.bot.CreateQueue(context.Background(), chosenInlineResult.InlineMessageID, chosenInlineResult.Query); err != nil {
+	if err = s.bot.CreateQueue(ctx, chosenInlineResult.InlineMessageID, query.queue(), chosenInlineResult.From); err != nil {
 		return fmt.Errorf("couldn't create queue: %w", err)
 	}
 
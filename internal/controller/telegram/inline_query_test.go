@@ -0,0 +1,71 @@
+package telegram
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"QueueBot/internal/entity"
+)
+
+func TestParseQueueQuery(t *testing.T) {
+	now := time.Date(2024, time.March, 1, 8, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		query string
+		want  queueQuery
+	}{
+		{name: "Unlimited queue", query: "Лаба 3", want: queueQuery{description: "Лаба 3"}},
+		{name: "Limited queue", query: "/create 15 Лаба 3", want: queueQuery{description: "Лаба 3", capacity: 15}},
+		{name: "Extra spaces", query: "/create  15   Лаба 3 ", want: queueQuery{description: "Лаба 3", capacity: 15}},
+		{name: "Zero capacity", query: "/create 0 Лаба 3", want: queueQuery{description: "/create 0 Лаба 3"}},
+		{name: "Negative capacity", query: "/create -1 Лаба 3", want: queueQuery{description: "/create -1 Лаба 3"}},
+		{name: "Capacity isn't a number", query: "/create abc", want: queueQuery{description: "/create abc"}},
+		{name: "Without description", query: "/create 15", want: queueQuery{description: "/create 15"}},
+		{
+			name:  "Scheduled queue",
+			query: "/schedule 09:00 10:00 Лаба 3",
+			want: queueQuery{description: "Лаба 3", schedule: entity.Schedule{
+				OpensAt:  time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC),
+				StartsAt: time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC),
+			}},
+		},
+		{
+			name:  "Scheduled queue in random order",
+			query: "/schedule 09:00 10:00 shuffle Лаба 3",
+			want: queueQuery{description: "Лаба 3", schedule: entity.Schedule{
+				OpensAt:   time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC),
+				StartsAt:  time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC),
+				IsShuffle: true,
+			}},
+		},
+		{
+			name:  "Passed times are tomorrow",
+			query: "/schedule 08:00 08:15 Лаба 3",
+			want: queueQuery{description: "Лаба 3", schedule: entity.Schedule{
+				OpensAt:  time.Date(2024, time.March, 2, 8, 0, 0, 0, time.UTC),
+				StartsAt: time.Date(2024, time.March, 2, 8, 15, 0, 0, time.UTC),
+			}},
+		},
+		{
+			name:  "Start after midnight",
+			query: "/schedule 23:00 01:00 Лаба 3",
+			want: queueQuery{description: "Лаба 3", schedule: entity.Schedule{
+				OpensAt:  time.Date(2024, time.March, 1, 23, 0, 0, 0, time.UTC),
+				StartsAt: time.Date(2024, time.March, 2, 1, 0, 0, 0, time.UTC),
+			}},
+		},
+		{name: "Invalid time", query: "/schedule 25:00 10:00 Лаба 3", want: queueQuery{description: "/schedule 25:00 10:00 Лаба 3"}},
+		{name: "Without start", query: "/schedule 09:00 Лаба 3", want: queueQuery{description: "/schedule 09:00 Лаба 3"}},
+		{name: "Without schedule description", query: "/schedule 09:00 10:00 shuffle", want: queueQuery{
+			description: "/schedule 09:00 10:00 shuffle",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseQueueQuery(tt.query, now))
+		})
+	}
+}
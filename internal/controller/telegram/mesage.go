@@ -1,20 +1,65 @@
 package telegram
 
 import (
+	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"QueueBot/internal/controller/telegram/client"
 )
 
-const StartCommand = "start"
+const (
+	StartCommand   = "start"
+	EventsCommand  = "events"
+	HistoryCommand = "history"
+	// CreateCommand is "/create <capacity> <description>". The queue is created by the forward button like without it.
+	CreateCommand = "create"
+	// ScheduleCommand is "/schedule <opens> <starts> [shuffle] <description>" like CreateCommand.
+	ScheduleCommand = "schedule"
+	// TemplatesCommand manages templates of the user, see handleTemplatesCommand.
+	TemplatesCommand = "templates"
+)
 
-func (s BotServer) HandleMessage(message *tgbotapi.Message) error {
+func (s BotServer) HandleMessage(ctx context.Context, message *tgbotapi.Message) error {
 	// Проверяем, если сообщение - команда.
 	// Если да, отправляем соотвутствующее сообщение
-	if message.Command() == StartCommand {
+	switch message.Command() {
+	case StartCommand:
+		if err := s.bot.Subscribe(ctx, message); err != nil {
+			return fmt.Errorf("subscribe error occurred: %w", err)
+		}
+
+		// Ссылка из кнопки "Журнал" открывает бота с /start events_<id очереди>
+		if messageID, ok := strings.CutPrefix(message.CommandArguments(), client.EventsStartPrefix); ok {
+			return s.handleEvents(ctx, message, messageID, 0)
+		}
+
 		if err := s.bot.SendHelloMessage(message); err != nil {
 			return fmt.Errorf("sendHelloMessage error occurred: %w", err)
 		}
+	case EventsCommand:
+		return s.handleEventsCommand(ctx, message)
+	case HistoryCommand:
+		if err := s.bot.SendHistory(ctx, message); err != nil {
+			return fmt.Errorf("sendHistory error occurred: %w", err)
+		}
+
+		return nil
+	case CreateCommand:
+		// Текст команды становится запросом кнопки пересылки, см. parseQueueQuery
+		if _, _, ok := parseCreateArgs(message.CommandArguments()); !ok {
+			return s.bot.SendCreateUsage(message)
+		}
+	case TemplatesCommand:
+		return s.handleTemplatesCommand(ctx, message)
+	case ScheduleCommand:
+		if _, _, ok := parseScheduleArgs(message.CommandArguments(), time.Now()); !ok {
+			return s.bot.SendScheduleUsage(message)
+		}
 	}
 
 	if err := s.bot.SendForwardMessageButton(message); err != nil {
@@ -23,3 +68,29 @@ func (s BotServer) HandleMessage(message *tgbotapi.Message) error {
 
 	return nil
 }
+
+// handleEventsCommand handles "/events <id очереди> [количество]".
+func (s BotServer) handleEventsCommand(ctx context.Context, message *tgbotapi.Message) error {
+	args := strings.Fields(message.CommandArguments())
+	if len(args) == 0 || len(args) > 2 {
+		return s.bot.SendEventsUsage(message)
+	}
+
+	limit := 0
+	if len(args) == 2 {
+		var err error
+		if limit, err = strconv.Atoi(args[1]); err != nil {
+			return s.bot.SendEventsUsage(message)
+		}
+	}
+
+	return s.handleEvents(ctx, message, args[0], limit)
+}
+
+func (s BotServer) handleEvents(ctx context.Context, message *tgbotapi.Message, messageID string, limit int) error {
+	if err := s.bot.SendEvents(ctx, message, messageID, limit); err != nil {
+		return fmt.Errorf("sendEvents error occurred: %w", err)
+	}
+
+	return nil
+}
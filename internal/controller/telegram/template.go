@@ -0,0 +1,135 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"QueueBot/internal/controller/telegram/client"
+	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase/storage"
+)
+
+const (
+	addTemplateAction    = "add"
+	deleteTemplateAction = "delete"
+)
+
+// handleTemplatesCommand handles "/templates", "/templates add <шаблон>", "/templates delete <номер>"
+// and "/templates <номер>", which sends the forward button posting a queue from the template.
+// Templates of the user aren't shown to a group, so the commands work only in a private chat.
+func (s BotServer) handleTemplatesCommand(ctx context.Context, message *tgbotapi.Message) error {
+	if !message.Chat.IsPrivate() {
+		return s.bot.SendTemplatesPrivateOnly(message)
+	}
+
+	action, args, _ := strings.Cut(strings.TrimSpace(message.CommandArguments()), " ")
+
+	switch action {
+	case "":
+		return s.bot.SendTemplates(ctx, message)
+	case addTemplateAction:
+		template, ok := parseTemplateArgs(args)
+		if !ok {
+			return s.bot.SendTemplatesUsage(message)
+		}
+
+		return s.bot.CreateTemplate(ctx, message, template)
+	case deleteTemplateAction:
+		templateID, ok := parseTemplateID(args)
+		if !ok {
+			return s.bot.SendTemplatesUsage(message)
+		}
+
+		return s.bot.DeleteTemplate(ctx, message, templateID)
+	}
+
+	templateID, ok := parseTemplateID(action)
+	if !ok || args != "" {
+		return s.bot.SendTemplatesUsage(message)
+	}
+
+	return s.bot.SendTemplateForwardButton(ctx, message, templateID)
+}
+
+// parseTemplateArgs parses "[capacity] [<opens> <starts> [shuffle]] <description>" arguments of "/templates add".
+func parseTemplateArgs(args string) (template entity.Template, ok bool) {
+	fields := strings.Fields(args)
+
+	if len(fields) > 0 {
+		if capacity, err := strconv.Atoi(fields[0]); err == nil {
+			if capacity <= 0 {
+				return entity.Template{}, false
+			}
+
+			template.Capacity = capacity
+			fields = fields[1:]
+		}
+	}
+
+	if len(fields) > 1 && isClockTime(fields[0]) && isClockTime(fields[1]) {
+		template.OpensAt, template.StartsAt = fields[0], fields[1]
+		fields = fields[2:]
+
+		if len(fields) > 0 && fields[0] == shuffleArg {
+			template.IsShuffle = true
+			fields = fields[1:]
+		}
+	}
+
+	template.Description = strings.Join(fields, " ")
+
+	return template, template.Description != ""
+}
+
+func isClockTime(value string) bool {
+	_, err := time.Parse(entity.ClockLayout, value)
+
+	return err == nil
+}
+
+func parseTemplateID(value string) (int64, bool) {
+	templateID, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+
+	return templateID, err == nil && templateID > 0
+}
+
+// resolveQueueQuery is parseQueueQuery which also posts queues from templates of the user, e.g. "/templates 3".
+// Unknown templates and templates of other users are descriptions of unlimited queues like other invalid queries.
+func (s BotServer) resolveQueueQuery(ctx context.Context, query string, user *tgbotapi.User, now time.Time) (queueQuery, error) {
+	rawID, ok := strings.CutPrefix(query, client.TemplateQueryPrefix)
+	if !ok {
+		return parseQueueQuery(query, now), nil
+	}
+
+	templateID, ok := parseTemplateID(rawID)
+	if !ok {
+		return parseQueueQuery(query, now), nil
+	}
+
+	template, err := s.bot.GetTemplate(ctx, templateID, user)
+	if errors.Is(err, storage.ErrTemplateNotFound) {
+		return parseQueueQuery(query, now), nil
+	}
+
+	if err != nil {
+		return queueQuery{}, fmt.Errorf("couldn't get template %d: %w", templateID, err)
+	}
+
+	schedule, err := template.Schedule(now)
+	if err != nil {
+		return queueQuery{}, fmt.Errorf("couldn't schedule queue of template %d: %w", templateID, err)
+	}
+
+	return queueQuery{
+		description: template.Description,
+		capacity:    template.Capacity,
+		schedule:    schedule,
+		templateID:  template.ID,
+	}, nil
+}
@@ -0,0 +1,91 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/i18n"
+	"QueueBot/internal/usecase/storage/memory"
+)
+
+func TestParseTemplateArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   string
+		want   entity.Template
+		wantOk bool
+	}{
+		{name: "Description", args: "Лаба по ОС", want: entity.Template{Description: "Лаба по ОС"}, wantOk: true},
+		{name: "Capacity", args: "15 Лаба по ОС", want: entity.Template{Description: "Лаба по ОС", Capacity: 15}, wantOk: true},
+		{
+			name:   "Schedule",
+			args:   "08:00 09:00 Лаба по ОС",
+			want:   entity.Template{Description: "Лаба по ОС", OpensAt: "08:00", StartsAt: "09:00"},
+			wantOk: true,
+		},
+		{
+			name: "Everything",
+			args: " 15  08:00 09:00 shuffle Лаба по ОС ",
+			want: entity.Template{
+				Description: "Лаба по ОС",
+				Capacity:    15,
+				OpensAt:     "08:00",
+				StartsAt:    "09:00",
+				IsShuffle:   true,
+			},
+			wantOk: true,
+		},
+		{
+			name:   "Shuffle without schedule is description",
+			args:   "shuffle Лаба",
+			want:   entity.Template{Description: "shuffle Лаба"},
+			wantOk: true,
+		},
+		{
+			name:   "Single time is description",
+			args:   "08:00 Лаба",
+			want:   entity.Template{Description: "08:00 Лаба"},
+			wantOk: true,
+		},
+		{name: "Zero capacity", args: "0 Лаба по ОС"},
+		{name: "Without description", args: "15 08:00 09:00 shuffle"},
+		{name: "Empty", args: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseTemplateArgs(tt.args)
+			assert.Equal(t, tt.wantOk, ok)
+
+			if tt.wantOk {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBotServer_TemplatesInGroup(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStorage()
+	server, fake, _ := newTestServer(t, s)
+
+	const text = "/templates add Лаба"
+
+	message := &tgbotapi.Message{
+		Text:     text,
+		Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Length: len("/templates")}},
+		Chat:     &tgbotapi.Chat{ID: -1, Type: "group"},
+		From:     &tgbotapi.User{ID: 1, LanguageCode: "en"},
+	}
+
+	require.NoError(t, server.HandleMessage(ctx, message))
+
+	templates, err := s.GetTemplates(ctx, 1)
+	require.NoError(t, err)
+	assert.Empty(t, templates, "template is created from a group")
+	assert.Equal(t, []string{i18n.English.Text(i18n.TemplatesPrivateOnly)}, fake.sent)
+}
@@ -0,0 +1,168 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// UpdateSource delivers updates from Telegram to BotServer.Listen.
+type UpdateSource interface {
+	// Start begins receiving updates. Errors which happen after Start returns are sent to errChan.
+	// The channel is closed once ctx is done and receiving is stopped.
+	Start(ctx context.Context, errChan chan<- error) (tgbotapi.UpdatesChannel, error)
+}
+
+// pollingRetryDelay is how long Polling waits after getUpdates failed.
+const pollingRetryDelay = 3 * time.Second
+
+// ErrPollingStale is returned by Polling.CheckFresh when Telegram hasn't answered getUpdates for too long.
+var ErrPollingStale = errors.New("no recent response to getUpdates")
+
+// Polling receives updates with getUpdates long polling.
+type Polling struct {
+	bot    *tgbotapi.BotAPI
+	config tgbotapi.UpdateConfig
+	// lastResponse is the Unix time in nanoseconds when getUpdates succeeded last time, or when polling was started.
+	lastResponse atomic.Int64
+}
+
+func NewPolling(bot *tgbotapi.BotAPI, config tgbotapi.UpdateConfig) *Polling {
+	return &Polling{bot: bot, config: config}
+}
+
+func (p *Polling) Start(ctx context.Context, _ chan<- error) (tgbotapi.UpdatesChannel, error) {
+	// Telegram doesn't allow getUpdates while a webhook is set, e.g. after running in webhook mode.
+	if _, err := p.bot.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		return nil, fmt.Errorf("couldn't delete webhook before polling: %w", err)
+	}
+
+	// The first long poll may take the whole timeout, so polling counts as fresh since the start.
+	p.lastResponse.Store(time.Now().UnixNano())
+
+	h := &handoff{updates: make(chan tgbotapi.Update)}
+
+	go p.poll(ctx, h)
+
+	// A long poll can't be interrupted, so the channel is closed here without waiting for it.
+	go func() {
+		<-ctx.Done()
+
+		// The channel is closed after the confirmation, so Listen doesn't return before it.
+		p.confirm(h.stop())
+		close(h.updates)
+		slog.Info("Stopped polling updates")
+	}()
+
+	return h.updates, nil
+}
+
+// poll requests updates until ctx is done. An update is confirmed by the next getUpdates only after Listen has taken it,
+// so updates which weren't taken before the stop are received again after restart. Updates taken after the last
+// getUpdates are confirmed by confirm on stop.
+func (p *Polling) poll(ctx context.Context, h *handoff) {
+	config := p.config
+
+	for ctx.Err() == nil {
+		updates, err := p.bot.GetUpdates(config)
+		if err != nil {
+			slog.Warn("Couldn't get updates, retrying", "reason", err, "delay", pollingRetryDelay.String())
+
+			select {
+			case <-time.After(pollingRetryDelay):
+			case <-ctx.Done():
+			}
+
+			continue
+		}
+
+		p.lastResponse.Store(time.Now().UnixNano())
+
+		for _, update := range updates {
+			if update.UpdateID < config.Offset {
+				continue
+			}
+
+			if !h.send(ctx, update) {
+				return
+			}
+
+			config.Offset = update.UpdateID + 1
+		}
+	}
+}
+
+// confirm tells Telegram that updates before offset are handled, otherwise they are received again after restart.
+// Zero offset means no update was taken.
+func (p *Polling) confirm(offset int) {
+	if offset == 0 {
+		return
+	}
+
+	config := p.config
+	config.Offset = offset
+	config.Timeout = 0
+
+	if _, err := p.bot.GetUpdates(config); err != nil {
+		slog.Warn("Couldn't confirm handled updates", "reason", err, "offset", offset)
+	}
+}
+
+// handoff passes updates from poll to Listen one by one. Nothing is sent to the channel once it's stopped.
+type handoff struct {
+	mu       sync.Mutex
+	updates  chan tgbotapi.Update
+	isClosed bool
+	// offset follows the last update taken by Listen.
+	offset int
+}
+
+// send blocks until Listen takes update and reports whether it was taken.
+func (h *handoff) send(ctx context.Context, update tgbotapi.Update) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.isClosed {
+		return false
+	}
+
+	select {
+	case h.updates <- update:
+		h.offset = update.UpdateID + 1
+
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// stop is called after ctx is done, so it waits for send only until send sees it too.
+// It returns the offset following the last taken update.
+func (h *handoff) stop() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.isClosed = true
+
+	return h.offset
+}
+
+// CheckFresh returns ErrPollingStale if getUpdates hasn't succeeded for longer than maxAge.
+func (p *Polling) CheckFresh(maxAge time.Duration) error {
+	lastResponse := p.lastResponse.Load()
+	if lastResponse == 0 {
+		return fmt.Errorf("polling isn't started: %w", ErrPollingStale)
+	}
+
+	if age := time.Since(time.Unix(0, lastResponse)); age > maxAge {
+		return fmt.Errorf("last response was %s ago: %w", age.Round(time.Second), ErrPollingStale)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolling_CheckFresh(t *testing.T) {
+	fake := &fakeTelegram{edits: make(map[string][]string)}
+
+	botAPI, err := tgbotapi.NewBotAPIWithClient("token", tgbotapi.APIEndpoint, fake)
+	require.NoError(t, err)
+
+	polling := NewPolling(botAPI, tgbotapi.NewUpdate(0))
+	assert.ErrorIs(t, polling.CheckFresh(time.Minute), ErrPollingStale, "not started polling is ready")
+
+	polling.lastResponse.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+	assert.ErrorIs(t, polling.CheckFresh(time.Minute), ErrPollingStale, "stale polling is ready")
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	updates, err := polling.Start(ctx, nil)
+	require.NoError(t, err)
+	assert.NoError(t, polling.CheckFresh(time.Minute))
+
+	// Responses to getUpdates keep polling fresh.
+	polling.lastResponse.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+	assert.Eventually(t, func() bool {
+		return polling.CheckFresh(time.Minute) == nil
+	}, time.Second, time.Millisecond, "response to getUpdates isn't recorded")
+
+	cancel()
+
+	_, ok := <-updates
+	assert.False(t, ok, "updates channel isn't closed after cancel")
+}
+
+func TestPolling_StopConfirmsTakenUpdates(t *testing.T) {
+	fake := &fakeTelegram{edits: make(map[string][]string), updates: []int{1, 2, 3}}
+
+	botAPI, err := tgbotapi.NewBotAPIWithClient("token", tgbotapi.APIEndpoint, fake)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	updates, err := NewPolling(botAPI, tgbotapi.NewUpdate(0)).Start(ctx, nil)
+	require.NoError(t, err)
+
+	update := <-updates
+	assert.Equal(t, 1, update.UpdateID)
+
+	// The rest of the batch is received while the first update is handled.
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	taken := update.UpdateID
+	for update := range updates {
+		taken = update.UpdateID
+	}
+
+	// Taken updates are confirmed on stop, so they aren't handled twice after restart.
+	// The rest aren't, so Telegram sends them again.
+	assert.Equal(t, taken+1, fake.lastOffset())
+}
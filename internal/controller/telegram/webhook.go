@@ -0,0 +1,181 @@
+package telegram
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// SecretTokenHeader carries the secret token Telegram was given when the webhook was set.
+const SecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+const (
+	webhookReadHeaderTimeout = 10 * time.Second
+	webhookShutdownTimeout   = 5 * time.Second
+)
+
+type WebhookConfig struct {
+	// URL is the public address Telegram sends updates to. Its path is served by the listener.
+	URL string
+	// ListenAddr is the address of the HTTP listener, e.g. ":8443".
+	ListenAddr  string
+	SecretToken string
+	// CertFile and KeyFile make the listener serve TLS itself.
+	// Without them it serves plain HTTP and is expected to be behind a reverse proxy terminating TLS.
+	CertFile string
+	KeyFile  string
+	// UploadCert sends CertFile to Telegram, which is needed for self-signed certificates.
+	UploadCert bool
+}
+
+// Webhook receives updates which Telegram sends to the HTTP listener.
+type Webhook struct {
+	bot     *tgbotapi.BotAPI
+	config  WebhookConfig
+	updates chan tgbotapi.Update
+
+	// mu guards sending to updates against closing it. Requests coming after that are answered with 503.
+	mu      sync.RWMutex
+	stopped bool
+}
+
+func NewWebhook(bot *tgbotapi.BotAPI, config WebhookConfig) *Webhook {
+	return &Webhook{
+		bot:     bot,
+		config:  config,
+		updates: make(chan tgbotapi.Update, bot.Buffer),
+	}
+}
+
+func (w *Webhook) Start(ctx context.Context, errChan chan<- error) (tgbotapi.UpdatesChannel, error) {
+	webhookURL, err := url.Parse(w.config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't parse webhook url: %w", err)
+	}
+
+	if err = w.setWebhook(); err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(webhookURL.Path, w)
+
+	server := &http.Server{
+		Addr:              w.config.ListenAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: webhookReadHeaderTimeout,
+	}
+
+	go func() {
+		slog.Info("Started webhook listener", "addr", w.config.ListenAddr, "path", webhookURL.Path)
+
+		var serveErr error
+		if w.config.CertFile != "" {
+			serveErr = server.ListenAndServeTLS(w.config.CertFile, w.config.KeyFile)
+		} else {
+			serveErr = server.ListenAndServe()
+		}
+
+		if !errors.Is(serveErr, http.ErrServerClosed) {
+			errChan <- fmt.Errorf("webhook listener stopped: %w", serveErr)
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		w.stop(server)
+	}()
+
+	return w.updates, nil
+}
+
+// stop shuts the listener down and closes the updates channel.
+func (w *Webhook) stop(server *http.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		slog.Warn("Couldn't shut webhook listener down gracefully", "reason", err)
+	}
+
+	w.mu.Lock()
+	w.stopped = true
+	close(w.updates)
+	w.mu.Unlock()
+
+	slog.Info("Stopped webhook listener")
+}
+
+// setWebhook registers the webhook in Telegram. tgbotapi.WebhookConfig can't pass the secret token, so params are built here.
+func (w *Webhook) setWebhook() error {
+	params := tgbotapi.Params{
+		"url":          w.config.URL,
+		"secret_token": w.config.SecretToken,
+	}
+
+	var err error
+	if w.config.UploadCert {
+		_, err = w.bot.UploadFiles("setWebhook", params, []tgbotapi.RequestFile{
+			{Name: "certificate", Data: tgbotapi.FilePath(w.config.CertFile)},
+		})
+	} else {
+		_, err = w.bot.MakeRequest("setWebhook", params)
+	}
+
+	if err != nil {
+		return fmt.Errorf("couldn't set webhook: %w", err)
+	}
+
+	return nil
+}
+
+// ServeHTTP accepts an update from Telegram and passes it to the updates channel.
+func (w *Webhook) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	token := r.Header.Get(SecretTokenHeader)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(w.config.SecretToken)) != 1 {
+		slog.Warn("Rejected webhook request with wrong secret token", "remoteAddr", r.RemoteAddr)
+		rw.WriteHeader(http.StatusUnauthorized)
+
+		return
+	}
+
+	var update tgbotapi.Update
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		slog.Warn("Couldn't decode webhook update", "reason", err)
+		rw.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.stopped {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+
+		return
+	}
+
+	select {
+	case w.updates <- update:
+		rw.WriteHeader(http.StatusOK)
+	case <-r.Context().Done():
+		// Telegram retries updates which weren't answered with 2xx.
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,133 @@
+package telegram
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecretToken = "secret_token-42"
+
+func newTestWebhook() *Webhook {
+	return NewWebhook(&tgbotapi.BotAPI{Buffer: 1}, WebhookConfig{SecretToken: testSecretToken})
+}
+
+func newWebhookRequest(t *testing.T, method string, body string, secretToken string) *http.Request {
+	t.Helper()
+
+	r := httptest.NewRequest(method, "/webhook", strings.NewReader(body))
+	if secretToken != "" {
+		r.Header.Set(SecretTokenHeader, secretToken)
+	}
+
+	return r
+}
+
+func TestWebhook_ServeHTTP_RecordedUpdates(t *testing.T) {
+	tests := []struct {
+		file  string
+		check func(t *testing.T, update tgbotapi.Update)
+	}{
+		{
+			file: "message.json",
+			check: func(t *testing.T, update tgbotapi.Update) {
+				require.NotNil(t, update.Message)
+				assert.Equal(t, StartCommand, update.Message.Command())
+				assert.Equal(t, int64(271828182), update.Message.From.ID)
+				assert.True(t, update.Message.Chat.IsPrivate())
+			},
+		},
+		{
+			file: "callback_query.json",
+			check: func(t *testing.T, update tgbotapi.Update) {
+				require.NotNil(t, update.CallbackQuery)
+				assert.Equal(t, "AgAAAOyOBQBOG7QQ1vLn2XSmkKk", update.CallbackQuery.InlineMessageID)
+				assert.Equal(t, "skip_user:end", update.CallbackQuery.Data)
+			},
+		},
+		{
+			file: "inline_query.json",
+			check: func(t *testing.T, update tgbotapi.Update) {
+				require.NotNil(t, update.InlineQuery)
+				assert.Equal(t, "Лаба 3", update.InlineQuery.Query)
+			},
+		},
+		{
+			file: "chosen_inline_result.json",
+			check: func(t *testing.T, update tgbotapi.Update) {
+				require.NotNil(t, update.ChosenInlineResult)
+				assert.Equal(t, "AgAAAOyOBQBOG7QQ1vLn2XSmkKk", update.ChosenInlineResult.InlineMessageID)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			body, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			require.NoError(t, err)
+
+			webhook := newTestWebhook()
+			rw := httptest.NewRecorder()
+
+			webhook.ServeHTTP(rw, newWebhookRequest(t, http.MethodPost, string(body), testSecretToken))
+
+			assert.Equal(t, http.StatusOK, rw.Code)
+			require.Len(t, webhook.updates, 1)
+			tt.check(t, <-webhook.updates)
+		})
+	}
+}
+
+func TestWebhook_ServeHTTP_Rejected(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		body        string
+		secretToken string
+		wantCode    int
+	}{
+		{
+			name:     "Without secret token",
+			method:   http.MethodPost,
+			body:     `{"update_id": 1}`,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:        "Wrong secret token",
+			method:      http.MethodPost,
+			body:        `{"update_id": 1}`,
+			secretToken: "wrong",
+			wantCode:    http.StatusUnauthorized,
+		},
+		{
+			name:        "Wrong method",
+			method:      http.MethodGet,
+			secretToken: testSecretToken,
+			wantCode:    http.StatusMethodNotAllowed,
+		},
+		{
+			name:        "Malformed update",
+			method:      http.MethodPost,
+			body:        `{"update_id":`,
+			secretToken: testSecretToken,
+			wantCode:    http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := newTestWebhook()
+			rw := httptest.NewRecorder()
+
+			webhook.ServeHTTP(rw, newWebhookRequest(t, tt.method, tt.body, tt.secretToken))
+
+			assert.Equal(t, tt.wantCode, rw.Code)
+			assert.Empty(t, webhook.updates)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// CheckIn is how a participant near their turn confirms being present.
+type CheckIn struct {
+	// AskedAt is when the participant was asked to confirm. It is zero until then.
+	AskedAt     time.Time
+	IsConfirmed bool
+	// Missed is how many times the participant didn't confirm in time.
+	Missed int
+}
+
+// IsZero reports whether the participant was never asked and never missed a check-in.
+func (c CheckIn) IsZero() bool {
+	return c.AskedAt.IsZero() && !c.IsConfirmed && c.Missed == 0
+}
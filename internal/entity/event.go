@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// Event is a record of the queue audit log: who did what and when.
+type Event struct {
+	Operation Operation
+	UserID    int64
+	// UserName is the name the user has in the queue, also after leaving it. It's empty if the user never joined.
+	UserName  string
+	CreatedAt time.Time
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// FinishedQueue is an archived queue as seen by one of its participants.
+type FinishedQueue struct {
+	MessageID   string
+	Description string
+	// Position of the participant in the final order, starting from 0.
+	Position         int
+	Participants     int
+	CurrentPersonIdx int
+	FinishedAt       time.Time
+}
+
+// Passed reports whether the participant's turn was over before the queue was finished.
+func (q FinishedQueue) Passed() bool {
+	return q.Position < q.CurrentPersonIdx
+}
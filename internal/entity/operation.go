@@ -0,0 +1,34 @@
+package entity
+
+// Operation is a change made to a queue by a user.
+type Operation string
+
+const (
+	OperationCreate   Operation = "create"
+	OperationJoin     Operation = "join"
+	OperationLeave    Operation = "leave"
+	OperationStart    Operation = "start"
+	OperationShuffle  Operation = "shuffle"
+	OperationNext     Operation = "next"
+	OperationPrevious Operation = "previous"
+	OperationSkip     Operation = "skip"
+	OperationStop     Operation = "stop"
+	OperationUndo     Operation = "undo"
+	OperationFinish   Operation = "finish"
+	OperationRemove   Operation = "remove"
+	// OperationWaitlist is joining the full queue.
+	OperationWaitlist Operation = "waitlist"
+	// OperationPromote is moving the user from the waitlist to the queue after somebody left.
+	OperationPromote Operation = "promote"
+	// OperationSlotDone is freeing a service slot, the next person is called to it.
+	OperationSlotDone Operation = "slot_done"
+	// OperationCheckIn is confirming being present when asked.
+	OperationCheckIn Operation = "check_in"
+	// OperationCheckInMoved and OperationCheckInRemoved are done to the user who didn't confirm being present in time.
+	// The first time the user is moved to the end of the queue, the next time they are removed from it.
+	OperationCheckInMoved   Operation = "check_in_moved"
+	OperationCheckInRemoved Operation = "check_in_removed"
+
+	OperationAddAdmin    Operation = "add_admin"
+	OperationRemoveAdmin Operation = "remove_admin"
+)
@@ -1,8 +1,106 @@
 package entity
 
+import "time"
+
 type Queue struct {
 	MessageID        string
 	Description      string
 	Users            []User
 	CurrentPersonIdx int
+	// OwnerID is the user who created the queue. It is zero for queues created before owners were stored.
+	OwnerID  int64
+	AdminIDs []int64
+	// IsStarted is true after the queue was started and until it is returned to the menu.
+	IsStarted bool
+	// NotifyCount is how many people after the current one get a private message when the queue advances.
+	NotifyCount int
+	// Language of the inline message, e.g. "ru".
+	Language string
+	// Capacity is the most people in the queue, the rest wait in Waitlist. Zero means unlimited.
+	Capacity int
+	// Waitlist are people who joined the full queue, in the order they get into it.
+	Waitlist []User
+	// TurnDuration is how long the current person has before the queue advances by itself. Zero turns it off.
+	TurnDuration time.Duration
+	Turn         Turn
+	Schedule     Schedule
+	// TemplateID is the template the queue was posted from. It is zero for queues created by hand.
+	TemplateID int64
+	// SlotCount is how many people pass at once. With more than one slot CurrentPersonIdx is the first waiting person.
+	SlotCount int
+	// Slots are people who pass now, by slot number starting from 1. Free slots hold zero User.
+	// They are only filled when SlotCount is more than one.
+	Slots []User
+	// CheckInTimeout is how long people near their turn have to confirm being present. Zero turns check-in off.
+	CheckInTimeout time.Duration
+	// CheckIns of participants who were asked to confirm or missed it, by user ID.
+	CheckIns map[int64]CheckIn
+}
+
+// HasSlots reports whether several people pass the queue at once.
+func (q Queue) HasSlots() bool {
+	return q.SlotCount > 1
+}
+
+// IsOver reports whether everybody in the started queue has passed.
+func (q Queue) IsOver() bool {
+	if q.CurrentPersonIdx < len(q.Users) {
+		return false
+	}
+
+	for _, user := range q.Slots {
+		if user.ID != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsFull reports whether people who join now go to the waitlist.
+func (q Queue) IsFull() bool {
+	return q.Capacity > 0 && len(q.Users) >= q.Capacity
+}
+
+// IsOwner reports whether user can manage admins of the queue. Queues without an owner can be managed by anyone.
+func (q Queue) IsOwner(userID int64) bool {
+	return q.OwnerID == 0 || q.OwnerID == userID
+}
+
+// IsAdmin reports whether user can start, advance and finish the queue.
+func (q Queue) IsAdmin(userID int64) bool {
+	return q.IsOwner(userID) || q.IsCoAdmin(userID)
+}
+
+// IsCoAdmin reports whether owner has granted admin rights to user.
+func (q Queue) IsCoAdmin(userID int64) bool {
+	for _, adminID := range q.AdminIDs {
+		if adminID == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsWaitlisted reports whether user waits for a place in the queue.
+func (q Queue) IsWaitlisted(userID int64) bool {
+	for _, user := range q.Waitlist {
+		if user.ID == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasUser reports whether user is in the queue. Waitlisted people aren't in it yet.
+func (q Queue) HasUser(userID int64) bool {
+	for _, user := range q.Users {
+		if user.ID == userID {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// Schedule is when people can join the queue and when it starts by itself. Zero times aren't scheduled.
+type Schedule struct {
+	OpensAt  time.Time
+	StartsAt time.Time
+	// IsShuffle starts the queue in random order.
+	IsShuffle bool
+}
+
+// IsZero reports whether the queue isn't scheduled at all.
+func (s Schedule) IsZero() bool {
+	return s.OpensAt.IsZero() && s.StartsAt.IsZero()
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// ClockLayout is how OpensAt and StartsAt of templates are written.
+const ClockLayout = "15:04"
+
+// Template is a queue which is posted again and again, e.g. the same lab every week.
+type Template struct {
+	ID          int64
+	OwnerID     int64
+	Description string
+	// Capacity of posted queues. Zero means unlimited.
+	Capacity int
+	// OpensAt and StartsAt are clock times in the time zone of the bot. Both are empty when posted queues aren't scheduled.
+	OpensAt  string
+	StartsAt string
+	// IsShuffle starts scheduled queues in random order.
+	IsShuffle bool
+	// Instances is how many queues were posted from the template.
+	Instances int
+}
+
+// IsScheduled reports whether queues posted from the template start by themselves.
+func (t Template) IsScheduled() bool {
+	return t.OpensAt != "" || t.StartsAt != ""
+}
+
+// Schedule returns the schedule of the queue posted at now.
+// Joining opens at the nearest OpensAt after now and the queue starts at the nearest StartsAt after that.
+func (t Template) Schedule(now time.Time) (Schedule, error) {
+	if !t.IsScheduled() {
+		return Schedule{}, nil
+	}
+
+	opensAt, err := NextClockTime(t.OpensAt, now)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	startsAt, err := NextClockTime(t.StartsAt, opensAt)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	return Schedule{OpensAt: opensAt, StartsAt: startsAt, IsShuffle: t.IsShuffle}, nil
+}
+
+// NextClockTime returns the nearest time after the given one which shows clock, e.g. "09:00", in its location.
+func NextClockTime(clock string, after time.Time) (time.Time, error) {
+	parsed, err := time.Parse(ClockLayout, clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("couldn't parse clock time %q: %w", clock, err)
+	}
+
+	year, month, day := after.Date()
+
+	next := time.Date(year, month, day, parsed.Hour(), parsed.Minute(), 0, 0, after.Location())
+	if !next.After(after) {
+		next = time.Date(year, month, day+1, parsed.Hour(), parsed.Minute(), 0, 0, after.Location())
+	}
+
+	return next, nil
+}
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+// Turn is the timing of the current person in a queue with a turn duration.
+type Turn struct {
+	// UserID is the person the turn is timed for. It lags behind the current person until the scheduler notices the change.
+	UserID    int64
+	StartedAt time.Time
+	// IsWarned is true after the person was told that the turn is about to end.
+	IsWarned bool
+}
@@ -0,0 +1,54 @@
+// Package health serves liveness and readiness probes for orchestrators.
+package health
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// checkTimeout bounds all readiness checks of one request.
+const checkTimeout = 3 * time.Second
+
+// Check tells whether a dependency of the bot works.
+type Check struct {
+	Name string
+	// Check returns why the dependency isn't ready, or nil.
+	Check func(ctx context.Context) error
+}
+
+// Live answers 200 while the process serves HTTP.
+func Live(rw http.ResponseWriter, _ *http.Request) {
+	rw.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(rw, "ok\n")
+}
+
+// Ready answers 200 when all checks pass. Otherwise it answers 503 with the failed checks.
+func Ready(checks ...Check) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		defer cancel()
+
+		var failed []string
+
+		for _, check := range checks {
+			if err := check.Check(ctx); err != nil {
+				slog.Warn("Readiness check failed", "check", check.Name, "reason", err)
+				failed = append(failed, check.Name+": "+err.Error())
+			}
+		}
+
+		if len(failed) > 0 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = io.WriteString(rw, strings.Join(failed, "\n")+"\n")
+
+			return
+		}
+
+		rw.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(rw, "ok\n")
+	}
+}
@@ -0,0 +1,51 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReady(t *testing.T) {
+	ok := Check{Name: "storage", Check: func(context.Context) error { return nil }}
+	stale := Check{Name: "polling", Check: func(context.Context) error { return errors.New("no response") }}
+
+	tests := []struct {
+		name     string
+		checks   []Check
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "Without checks",
+			wantCode: http.StatusOK,
+			wantBody: "ok\n",
+		},
+		{
+			name:     "All checks pass",
+			checks:   []Check{ok},
+			wantCode: http.StatusOK,
+			wantBody: "ok\n",
+		},
+		{
+			name:     "Failed check is reported",
+			checks:   []Check{ok, stale},
+			wantCode: http.StatusServiceUnavailable,
+			wantBody: "polling: no response\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+
+			Ready(tt.checks...)(rw, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, tt.wantCode, rw.Code)
+			assert.Equal(t, tt.wantBody, rw.Body.String())
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package i18n
+
+var english = map[Key]string{
+	Hello: `Hi! I'm a bot for making queues.
+Send me the description of your queue and I'll create it`,
+	ForwardQueueToMessage: "Great! Now send your queue to a chat with the button below",
+	ForwardQueueButton:    "Send the queue",
+	EventsTitle:           "Latest events of the queue:",
+	NoEvents:              "There are no events of the queue yet",
+	EventsUsage:           "Usage: /events <queue id> [number of events]",
+	CreateUsage:           "Usage: /create <number of places> <description of the queue>, e.g. /create 15 Lab 3",
+	ScheduleUsage:         "Usage: /schedule <joining opens> <start> [shuffle] <description of the queue>, e.g. /schedule 08:00 09:00 shuffle Lab 3",
+	HistoryTitle:          "Your past queues:",
+	NoHistory:             "You haven't been in finished queues yet",
+	HistoryLine:           "<b>%s</b> — %s, place %d of %d, %s",
+	PassedMark:            "passed",
+	NotPassedMark:         "didn't make it",
+	YourTurn:              "It's your turn in «%s»!",
+	PeopleBeforeYou:       "People before you in «%s»: %d",
+	TurnEnding:            "Your time in «%s» is running out, minutes left: %d",
+	CheckInRequest:        "Your turn in «%s» is coming. Confirm that you are here within %d min, otherwise you'll be moved to the end of the queue, and removed from it if you miss it again",
+	DashboardLink:         "Read-only live view of «%s», e.g. for a projector:\n%s",
+	TemplatesTitle:        "Your queue templates:",
+	NoTemplates:           "You have no queue templates yet",
+	TemplatesUsage: `Create a template: /templates add [places] [joining opens start [shuffle]] <description>, e.g. /templates add 15 08:00 09:00 shuffle OS Lab
+Delete a template: /templates delete <number>
+Post a queue from a template: the button under the list or /templates <number>`,
+	TemplatesPrivateOnly:    "Templates are private, send /templates in a private chat with the bot",
+	TemplateNotFound:        "Template #%d not found",
+	TemplateLine:            "#%d «%s»",
+	TemplatePlaces:          "places: %d",
+	TemplateSchedule:        "joining from %s, starts at %s",
+	TemplateShuffleSchedule: "joining from %s, starts in random order at %s",
+	TemplateInstances:       "posted times: %d",
+
+	QueueDescription:                "In the queue:",
+	EndedQueue:                      "Everybody has had their turn, so the queue is over. What's next?",
+	FinishedQueue:                   "The queue is finished 🎉",
+	ChooseAdmins:                    "Choose participants who can manage the queue:",
+	CreateQueue:                     "Create a queue",
+	CreateQueueDescription:          "Described as: %s",
+	CreateLimitedQueueDescription:   "Described as: %s, places: %d",
+	CreateScheduledQueueDescription: "Described as: %s, starts at %s",
+	Places:                          "Places taken: %d of %d",
+	WaitlistTitle:                   "Waitlist:",
+	TurnDuration:                    "Time per person: %d min",
+	JoinOpensAt:                     "Joining opens at %s",
+	StartsAt:                        "Starts at %s",
+	ShuffleStartsAt:                 "Starts in random order at %s",
+	SlotLine:                        "Slot %d: %s",
+	FreeSlotLine:                    "Slot %d: free",
+	WaitingTitle:                    "Waiting:",
+	CheckInTimeout:                  "Check-in: %d min",
+	AwaitingCheckIn:                 "✋ Waiting for check-in: %s",
+
+	LogInOutButton:          "Join/leave the queue",
+	StartQueueButton:        "Start in order of joining",
+	StartQueueShuffleButton: "Start in random order",
+	NextButton:              "Next",
+	PreviousButton:          "Back",
+	GoToMenuButton:          "Go to menu",
+	FinishQueueButton:       "Finish",
+	UndoButton:              "Undo",
+	EventsButton:            "Log",
+	SkipButton:              "Skip",
+	SkipToEndButton:         "Skip to the end",
+	ManageAdminsButton:      "Admins",
+	NotifyCountButton:       "🔔 Notify next: %d",
+	LanguageButton:          "🌐 Language: English",
+	TurnDurationButton:      "⏱ Time per person: %d min",
+	NoTurnDurationButton:    "⏱ Time per person: unlimited",
+	PostTemplateButton:      "Post «%s»",
+	SlotCountButton:         "👥 Served at once: %d",
+	SlotDoneButton:          "Done — slot %d",
+	CheckInTimeoutButton:    "✋ Check-in: %d min",
+	NoCheckInButton:         "✋ Check-in: off",
+	CheckInButton:           "✋ I'm here",
+
+	ActionCompleted:      "Done!",
+	ActionError:          "Something went wrong",
+	NotAdminError:        "Only the creator of the queue and admins they chose can do this",
+	NotOwnerError:        "Only the creator of the queue can choose admins",
+	JoinNotOpenError:     "Joining the queue isn't open yet, see the time in the queue message",
+	JoinClosedError:      "Joining the queue has closed, it is about to start",
+	NothingToUndo:        "Nothing to undo",
+	CheckInNotAskedError: "You don't need to check in yet, the bot will ask you when your turn comes close",
+	HasSlotsError:        "Several people are served at once in this queue, free their slots with the buttons",
+	NoCurrentPersonError: "Nobody's turn now: the queue isn't started or everybody has passed",
+	AlreadyStartedError:  "The queue is already started",
+
+	OperationCreate:         "created the queue",
+	OperationJoin:           "joined the queue",
+	OperationLeave:          "left the queue",
+	OperationStart:          "started the queue",
+	OperationShuffle:        "started the queue in random order",
+	OperationNext:           "moved to the next person",
+	OperationPrevious:       "moved back to the previous person",
+	OperationSkip:           "skipped the current person",
+	OperationStop:           "returned the queue to the menu",
+	OperationUndo:           "undid the last action",
+	OperationFinish:         "finished the queue",
+	OperationRemove:         "removed a participant from the queue",
+	OperationWaitlist:       "joined the waitlist",
+	OperationPromote:        "moved from the waitlist to the queue",
+	OperationSlotDone:       "freed a slot",
+	OperationCheckIn:        "checked in",
+	OperationCheckInMoved:   "was moved to the end: didn't check in in time",
+	OperationCheckInRemoved: "was removed from the queue: didn't check in again",
+	OperationAddAdmin:       "made someone an admin",
+	OperationRemoveAdmin:    "removed an admin",
+
+	DashboardNotFound:     "The queue isn't found or is already finished",
+	DashboardConnecting:   "Connecting…",
+	DashboardReconnecting: "Connection lost, reconnecting…",
+	DashboardNotStarted:   "The queue hasn't started yet",
+	DashboardJoined:       "Joined:",
+	DashboardCurrent:      "Now:",
+	DashboardNext:         "Next:",
+	DashboardAllPassed:    "Everybody has had their turn",
+	DashboardFinished:     "The queue is finished",
+}
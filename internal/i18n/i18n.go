@@ -0,0 +1,51 @@
+// Package i18n translates texts the bot shows to users.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Language is a language texts are translated to, e.g. "ru".
+type Language string
+
+const (
+	Russian Language = "ru"
+	English Language = "en"
+)
+
+// Default is the language of users whose language isn't supported.
+const Default = Russian
+
+// Languages are supported languages in the order admins switch between them.
+var Languages = []Language{Russian, English}
+
+var catalogs = map[Language]map[Key]string{
+	Russian: russian,
+	English: english,
+}
+
+// Parse returns the supported language of IETF language tag, e.g. "en-US" from Telegram. Other languages are Default.
+func Parse(tag string) Language {
+	base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+	if _, ok := catalogs[Language(base)]; ok {
+		return Language(base)
+	}
+
+	return Default
+}
+
+// Text returns the text of key in the language, formatted with args if there are any.
+// Texts missing in the language are taken from Default.
+func (l Language) Text(key Key, args ...any) string {
+	text, ok := catalogs[l][key]
+	if !ok {
+		text = catalogs[Default][key]
+	}
+
+	if len(args) == 0 {
+		return text
+	}
+
+	return fmt.Sprintf(text, args...)
+}
@@ -0,0 +1,100 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// declaredKeys returns values of all Key constants in keys.go, so a key can't be forgotten in a catalog.
+func declaredKeys(t *testing.T) []Key {
+	t.Helper()
+
+	file, err := parser.ParseFile(token.NewFileSet(), "keys.go", nil, 0)
+	require.NoError(t, err)
+
+	var keys []Key
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.CONST {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			require.True(t, ok)
+
+			for _, value := range valueSpec.Values {
+				literal, ok := value.(*ast.BasicLit)
+				require.True(t, ok, "keys must be string literals")
+
+				keys = append(keys, Key(literal.Value[1:len(literal.Value)-1]))
+			}
+		}
+	}
+
+	require.NotEmpty(t, keys)
+
+	return keys
+}
+
+var formatVerb = regexp.MustCompile(`%[a-z]`)
+
+func TestCatalogs(t *testing.T) {
+	keys := declaredKeys(t)
+
+	for _, language := range Languages {
+		t.Run(string(language), func(t *testing.T) {
+			catalog, ok := catalogs[language]
+			require.True(t, ok, "no catalog")
+
+			for _, key := range keys {
+				text, ok := catalog[key]
+				if !assert.True(t, ok, "missing key %q", key) {
+					continue
+				}
+
+				assert.NotEmpty(t, text, "empty text of %q", key)
+				assert.Equal(
+					t,
+					formatVerb.FindAllString(catalog[key], -1),
+					formatVerb.FindAllString(catalogs[Default][key], -1),
+					"format verbs of %q differ from %s", key, Default,
+				)
+			}
+
+			assert.Len(t, catalog, len(keys), "catalog has undeclared keys")
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want Language
+	}{
+		{tag: "ru", want: Russian},
+		{tag: "en", want: English},
+		{tag: "en-US", want: English},
+		{tag: "EN-gb", want: English},
+		{tag: "de", want: Default},
+		{tag: "", want: Default},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			assert.Equal(t, tt.want, Parse(tt.tag))
+		})
+	}
+}
+
+func TestLanguage_Text(t *testing.T) {
+	assert.Equal(t, "Next", English.Text(NextButton))
+	assert.Equal(t, "🔔 Notify next: 3", English.Text(NotifyCountButton, 3))
+	assert.Equal(t, russian[NextButton], Language("de").Text(NextButton))
+}
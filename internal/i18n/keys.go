@@ -0,0 +1,167 @@
+package i18n
+
+// Key identifies a text in catalogs. Every key must be translated to every language.
+type Key string
+
+// Private messages.
+const (
+	Hello                 Key = "hello"
+	ForwardQueueToMessage Key = "forward_queue_to_message"
+	ForwardQueueButton    Key = "forward_queue_button"
+	EventsTitle           Key = "events_title"
+	NoEvents              Key = "no_events"
+	EventsUsage           Key = "events_usage"
+	CreateUsage           Key = "create_usage"
+	ScheduleUsage         Key = "schedule_usage"
+	HistoryTitle          Key = "history_title"
+	NoHistory             Key = "no_history"
+	// HistoryLine is formatted with description, time of the finish, position, number of participants and a mark.
+	HistoryLine   Key = "history_line"
+	PassedMark    Key = "passed_mark"
+	NotPassedMark Key = "not_passed_mark"
+	// YourTurn is formatted with description of the queue.
+	YourTurn Key = "your_turn"
+	// PeopleBeforeYou is formatted with description of the queue and number of people before the user.
+	PeopleBeforeYou Key = "people_before_you"
+	// TurnEnding is formatted with description of the queue and minutes left before the queue advances.
+	TurnEnding Key = "turn_ending"
+	// CheckInRequest is formatted with description of the queue and minutes to confirm the presence.
+	CheckInRequest Key = "check_in_request"
+	// DashboardLink is formatted with description of the queue and the link.
+	DashboardLink  Key = "dashboard_link"
+	TemplatesTitle Key = "templates_title"
+	NoTemplates    Key = "no_templates"
+	TemplatesUsage Key = "templates_usage"
+	// TemplatesPrivateOnly answers /templates sent to a group.
+	TemplatesPrivateOnly Key = "templates_private_only"
+	// TemplateNotFound is formatted with ID of the template.
+	TemplateNotFound Key = "template_not_found"
+	// TemplateLine is formatted with ID and description of the template.
+	TemplateLine Key = "template_line"
+	// TemplatePlaces is formatted with capacity of the template.
+	TemplatePlaces Key = "template_places"
+	// TemplateSchedule and TemplateShuffleSchedule are formatted with clock times when joining opens and the queue starts.
+	TemplateSchedule        Key = "template_schedule"
+	TemplateShuffleSchedule Key = "template_shuffle_schedule"
+	// TemplateInstances is formatted with how many queues were posted from the template.
+	TemplateInstances Key = "template_instances"
+)
+
+// Inline message of the queue.
+const (
+	QueueDescription Key = "queue_description"
+	EndedQueue       Key = "ended_queue"
+	FinishedQueue    Key = "finished_queue"
+	ChooseAdmins     Key = "choose_admins"
+	// CreateQueue is the title of the inline query result.
+	CreateQueue Key = "create_queue"
+	// CreateQueueDescription is formatted with description of the queue.
+	CreateQueueDescription Key = "create_queue_description"
+	// CreateLimitedQueueDescription is formatted with description and capacity of the queue.
+	CreateLimitedQueueDescription Key = "create_limited_queue_description"
+	// CreateScheduledQueueDescription is formatted with description of the queue and time of its start.
+	CreateScheduledQueueDescription Key = "create_scheduled_queue_description"
+	// Places is formatted with number of people in the queue and its capacity.
+	Places        Key = "places"
+	WaitlistTitle Key = "waitlist_title"
+	// TurnDuration is formatted with minutes every person has.
+	TurnDuration Key = "turn_duration"
+	// JoinOpensAt, StartsAt and ShuffleStartsAt are formatted with the time.
+	JoinOpensAt     Key = "join_opens_at"
+	StartsAt        Key = "starts_at"
+	ShuffleStartsAt Key = "shuffle_starts_at"
+	// SlotLine is formatted with number of the slot and name of the person at it.
+	SlotLine Key = "slot_line"
+	// FreeSlotLine is formatted with number of the slot.
+	FreeSlotLine Key = "free_slot_line"
+	WaitingTitle Key = "waiting_title"
+	// CheckInTimeout is formatted with minutes people have to confirm their presence.
+	CheckInTimeout Key = "check_in_timeout"
+	// AwaitingCheckIn is formatted with names of people who haven't confirmed their presence yet.
+	AwaitingCheckIn Key = "awaiting_check_in"
+)
+
+// Buttons.
+const (
+	LogInOutButton          Key = "log_in_out_button"
+	StartQueueButton        Key = "start_queue_button"
+	StartQueueShuffleButton Key = "start_queue_shuffle_button"
+	NextButton              Key = "next_button"
+	PreviousButton          Key = "previous_button"
+	GoToMenuButton          Key = "go_to_menu_button"
+	FinishQueueButton       Key = "finish_queue_button"
+	UndoButton              Key = "undo_button"
+	EventsButton            Key = "events_button"
+	SkipButton              Key = "skip_button"
+	SkipToEndButton         Key = "skip_to_end_button"
+	ManageAdminsButton      Key = "manage_admins_button"
+	// NotifyCountButton is formatted with how many people after the current one get private messages.
+	NotifyCountButton Key = "notify_count_button"
+	// LanguageButton shows the language of the queue.
+	LanguageButton Key = "language_button"
+	// TurnDurationButton is formatted with minutes every person has.
+	TurnDurationButton   Key = "turn_duration_button"
+	NoTurnDurationButton Key = "no_turn_duration_button"
+	// PostTemplateButton is formatted with description of the template.
+	PostTemplateButton Key = "post_template_button"
+	// SlotCountButton is formatted with how many people pass at once.
+	SlotCountButton Key = "slot_count_button"
+	// SlotDoneButton is formatted with number of the slot.
+	SlotDoneButton Key = "slot_done_button"
+	// CheckInTimeoutButton is formatted with minutes people have to confirm their presence.
+	CheckInTimeoutButton Key = "check_in_timeout_button"
+	NoCheckInButton      Key = "no_check_in_button"
+	CheckInButton        Key = "check_in_button"
+)
+
+// Answers to callback queries.
+const (
+	ActionCompleted      Key = "action_completed"
+	ActionError          Key = "action_error"
+	NotAdminError        Key = "not_admin_error"
+	NotOwnerError        Key = "not_owner_error"
+	JoinNotOpenError     Key = "join_not_open_error"
+	JoinClosedError      Key = "join_closed_error"
+	NothingToUndo        Key = "nothing_to_undo"
+	CheckInNotAskedError Key = "check_in_not_asked_error"
+	HasSlotsError        Key = "has_slots_error"
+	NoCurrentPersonError Key = "no_current_person_error"
+	AlreadyStartedError  Key = "already_started_error"
+)
+
+// Operations in the events message.
+const (
+	OperationCreate         Key = "operation_create"
+	OperationJoin           Key = "operation_join"
+	OperationLeave          Key = "operation_leave"
+	OperationStart          Key = "operation_start"
+	OperationShuffle        Key = "operation_shuffle"
+	OperationNext           Key = "operation_next"
+	OperationPrevious       Key = "operation_previous"
+	OperationSkip           Key = "operation_skip"
+	OperationStop           Key = "operation_stop"
+	OperationUndo           Key = "operation_undo"
+	OperationFinish         Key = "operation_finish"
+	OperationRemove         Key = "operation_remove"
+	OperationWaitlist       Key = "operation_waitlist"
+	OperationPromote        Key = "operation_promote"
+	OperationSlotDone       Key = "operation_slot_done"
+	OperationCheckIn        Key = "operation_check_in"
+	OperationCheckInMoved   Key = "operation_check_in_moved"
+	OperationCheckInRemoved Key = "operation_check_in_removed"
+	OperationAddAdmin       Key = "operation_add_admin"
+	OperationRemoveAdmin    Key = "operation_remove_admin"
+)
+
+// Dashboard page.
+const (
+	DashboardNotFound     Key = "dashboard_not_found"
+	DashboardConnecting   Key = "dashboard_connecting"
+	DashboardReconnecting Key = "dashboard_reconnecting"
+	DashboardNotStarted   Key = "dashboard_not_started"
+	DashboardJoined       Key = "dashboard_joined"
+	DashboardCurrent      Key = "dashboard_current"
+	DashboardNext         Key = "dashboard_next"
+	DashboardAllPassed    Key = "dashboard_all_passed"
+	DashboardFinished     Key = "dashboard_finished"
+)
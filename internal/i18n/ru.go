@@ -0,0 +1,121 @@
+package i18n
+
+var russian = map[Key]string{
+	Hello: `Привет! Я бот, предназначенный для создания очередей. 
+Введи описание своей очереди, а я тебе ее создам`,
+	ForwardQueueToMessage: "Отлично! Теперь с помощью кнопки ниже вы можете переслать свою 'очередь'",
+	ForwardQueueButton:    "Переслать 'очередь'",
+	EventsTitle:           "Последние события очереди:",
+	NoEvents:              "В журнале очереди пока нет событий",
+	EventsUsage:           "Использование: /events <id очереди> [количество событий]",
+	CreateUsage:           "Использование: /create <количество мест> <описание очереди>, например /create 15 Лаба 3",
+	ScheduleUsage:         "Использование: /schedule <начало записи> <старт> [shuffle] <описание очереди>, например /schedule 08:00 09:00 shuffle Лаба 3",
+	HistoryTitle:          "Ваши прошедшие очереди:",
+	NoHistory:             "Вы еще не были в законченных очередях",
+	HistoryLine:           "<b>%s</b> — %s, место %d из %d, %s",
+	PassedMark:            "прошел(ла)",
+	NotPassedMark:         "не успел(а)",
+	YourTurn:              "Подошла ваша очередь в «%s»!",
+	PeopleBeforeYou:       "В очереди «%s» перед вами: %d",
+	TurnEnding:            "Ваше время в очереди «%s» заканчивается, осталось минут: %d",
+	CheckInRequest:        "Скоро ваша очередь в «%s». Подтвердите, что вы на месте, в течение %d мин, иначе вас переместят в конец очереди, а при повторном пропуске уберут из нее",
+	DashboardLink:         "Трансляция очереди «%s» только для просмотра, например для проектора:\n%s",
+	TemplatesTitle:        "Ваши шаблоны очередей:",
+	NoTemplates:           "У вас пока нет шаблонов очередей",
+	TemplatesUsage: `Создать шаблон: /templates add [мест] [начало записи старт [shuffle]] <описание>, например /templates add 15 08:00 09:00 shuffle Лаба по ОС
+Удалить шаблон: /templates delete <номер>
+Опубликовать очередь по шаблону: кнопка под списком или /templates <номер>`,
+	TemplatesPrivateOnly:    "Шаблоны личные, отправьте /templates в личном чате с ботом",
+	TemplateNotFound:        "Шаблон №%d не найден",
+	TemplateLine:            "№%d «%s»",
+	TemplatePlaces:          "мест: %d",
+	TemplateSchedule:        "запись с %s, старт в %s",
+	TemplateShuffleSchedule: "запись с %s, старт в случайном порядке в %s",
+	TemplateInstances:       "опубликован раз: %d",
+
+	QueueDescription:                "В очереди состоят:",
+	EndedQueue:                      "Участники закончились, значит и очередь тоже. Что делаем дальше?",
+	FinishedQueue:                   "'Очередь' окончена 🎉",
+	ChooseAdmins:                    "Выберите участников, которые смогут управлять очередью:",
+	CreateQueue:                     "Создать очередь",
+	CreateQueueDescription:          "С описанием: %s",
+	CreateLimitedQueueDescription:   "С описанием: %s, мест: %d",
+	CreateScheduledQueueDescription: "С описанием: %s, старт в %s",
+	Places:                          "Занято мест: %d из %d",
+	WaitlistTitle:                   "Лист ожидания:",
+	TurnDuration:                    "Время на человека: %d мин",
+	JoinOpensAt:                     "Запись откроется в %s",
+	StartsAt:                        "Старт в %s",
+	ShuffleStartsAt:                 "Старт в случайном порядке в %s",
+	SlotLine:                        "Окно %d: %s",
+	FreeSlotLine:                    "Окно %d: свободно",
+	WaitingTitle:                    "Ожидают:",
+	CheckInTimeout:                  "Подтверждение присутствия: %d мин",
+	AwaitingCheckIn:                 "✋ Ждём подтверждения: %s",
+
+	LogInOutButton:          "Добавиться/выйти из очереди",
+	StartQueueButton:        "Старт в порядке очереди",
+	StartQueueShuffleButton: "Старт в случайном порядке",
+	NextButton:              "Следующий",
+	PreviousButton:          "Назад",
+	GoToMenuButton:          "Перейти в меню",
+	FinishQueueButton:       "Закончить",
+	UndoButton:              "Отменить",
+	EventsButton:            "Журнал",
+	SkipButton:              "Пропустить",
+	SkipToEndButton:         "Пропустить в конец",
+	ManageAdminsButton:      "Администраторы",
+	NotifyCountButton:       "🔔 Уведомлять следующих: %d",
+	LanguageButton:          "🌐 Язык: русский",
+	TurnDurationButton:      "⏱ Время на человека: %d мин",
+	NoTurnDurationButton:    "⏱ Время на человека: без ограничений",
+	PostTemplateButton:      "Опубликовать «%s»",
+	SlotCountButton:         "👥 Принимают одновременно: %d",
+	SlotDoneButton:          "Готово — окно %d",
+	CheckInTimeoutButton:    "✋ Подтверждение присутствия: %d мин",
+	NoCheckInButton:         "✋ Подтверждение присутствия: выкл",
+	CheckInButton:           "✋ Я здесь",
+
+	ActionCompleted:      "Действие выполнено!",
+	ActionError:          "Произошла ошибка",
+	NotAdminError:        "Только создатель очереди и назначенные им администраторы могут это сделать",
+	NotOwnerError:        "Только создатель очереди может назначать администраторов",
+	JoinNotOpenError:     "Запись в очередь еще не открыта, время открытия указано в сообщении очереди",
+	JoinClosedError:      "Запись в очередь закрыта, она вот-вот начнется",
+	NothingToUndo:        "Нечего отменять",
+	CheckInNotAskedError: "Подтверждать присутствие пока не нужно, бот попросит об этом, когда подойдет ваша очередь",
+	HasSlotsError:        "В этой очереди принимают по несколько человек, освобождайте окна кнопками",
+	NoCurrentPersonError: "Сейчас ничья очередь: очередь не запущена или все уже прошли",
+	AlreadyStartedError:  "Очередь уже запущена",
+
+	OperationCreate:         "создал(а) очередь",
+	OperationJoin:           "встал(а) в очередь",
+	OperationLeave:          "вышел(ла) из очереди",
+	OperationStart:          "запустил(а) очередь",
+	OperationShuffle:        "запустил(а) очередь в случайном порядке",
+	OperationNext:           "перешел(ла) к следующему",
+	OperationPrevious:       "вернулся(ась) к предыдущему",
+	OperationSkip:           "пропустил(а) текущего",
+	OperationStop:           "вернул(а) очередь в меню",
+	OperationUndo:           "отменил(а) последнее действие",
+	OperationFinish:         "закончил(а) очередь",
+	OperationRemove:         "убрал(а) участника из очереди",
+	OperationWaitlist:       "встал(а) в лист ожидания",
+	OperationPromote:        "перешел(ла) из листа ожидания в очередь",
+	OperationSlotDone:       "освободил(а) окно",
+	OperationCheckIn:        "подтвердил(а) присутствие",
+	OperationCheckInMoved:   "перемещен(а) в конец: не подтвердил(а) присутствие вовремя",
+	OperationCheckInRemoved: "убран(а) из очереди: снова не подтвердил(а) присутствие",
+	OperationAddAdmin:       "назначил(а) администратора",
+	OperationRemoveAdmin:    "снял(а) администратора",
+
+	DashboardNotFound:     "Очередь не найдена или уже закончилась",
+	DashboardConnecting:   "Подключение…",
+	DashboardReconnecting: "Нет связи, переподключение…",
+	DashboardNotStarted:   "Очередь ещё не началась",
+	DashboardJoined:       "Записались:",
+	DashboardCurrent:      "Сейчас:",
+	DashboardNext:         "Далее:",
+	DashboardAllPassed:    "Все прошли",
+	DashboardFinished:     "Очередь закончилась",
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const countActiveTimeout = 5 * time.Second
+
+// ActiveCounter counts queues which aren't archived and people in them.
+type ActiveCounter interface {
+	CountActive(ctx context.Context) (queues int, participants int, err error)
+}
+
+// ActiveCollector reports active queues and participants. They are counted in storage on every scrape.
+type ActiveCollector struct {
+	counter      ActiveCounter
+	queues       *prometheus.Desc
+	participants *prometheus.Desc
+}
+
+func NewActiveCollector(counter ActiveCounter) *ActiveCollector {
+	return &ActiveCollector{
+		counter: counter,
+		queues: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "active_queues"),
+			"Queues which aren't finished.",
+			nil, nil,
+		),
+		participants: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "active_participants"),
+			"People in queues which aren't finished.",
+			nil, nil,
+		),
+	}
+}
+
+func (c *ActiveCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.queues
+	ch <- c.participants
+}
+
+func (c *ActiveCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), countActiveTimeout)
+	defer cancel()
+
+	queues, participants, err := c.counter.CountActive(ctx)
+	if err != nil {
+		// Other metrics are still worth scraping, so the error isn't passed to Prometheus.
+		slog.Warn("Couldn't count active queues", "reason", err)
+
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.queues, prometheus.GaugeValue, float64(queues))
+	ch <- prometheus.MustNewConstMetric(c.participants, prometheus.GaugeValue, float64(participants))
+}
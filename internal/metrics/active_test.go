@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase/storage/memory"
+)
+
+func TestActiveCollector(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStorage()
+
+	require.NoError(t, s.CreateQueue(ctx, "first", "First", 1, "ru", 0))
+	require.NoError(t, s.CreateQueue(ctx, "second", "Second", 1, "ru", 0))
+	require.NoError(t, s.LogInOutToQueue(ctx, "first", entity.New(1, "", "User")))
+	require.NoError(t, s.LogInOutToQueue(ctx, "second", entity.New(2, "", "User")))
+	require.NoError(t, s.LogInOutToQueue(ctx, "second", entity.New(3, "", "User")))
+
+	want := `
+# HELP queuebot_active_participants People in queues which aren't finished.
+# TYPE queuebot_active_participants gauge
+queuebot_active_participants 3
+# HELP queuebot_active_queues Queues which aren't finished.
+# TYPE queuebot_active_queues gauge
+queuebot_active_queues 2
+`
+
+	assert.NoError(t, testutil.CollectAndCompare(NewActiveCollector(s), strings.NewReader(want)))
+}
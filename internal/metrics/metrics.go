@@ -0,0 +1,54 @@
+// Package metrics collects bot metrics and exposes them for Prometheus.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "queuebot"
+
+// Update types counted by Updates.
+const (
+	UpdateMessage            = "message"
+	UpdateCallbackQuery      = "callback_query"
+	UpdateInlineQuery        = "inline_query"
+	UpdateChosenInlineResult = "chosen_inline_result"
+	UpdateOther              = "other"
+)
+
+var (
+	Updates = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "updates_total",
+		Help:      "Updates received from Telegram by type.",
+	}, []string{"type"})
+
+	CallbackDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "callback_duration_seconds",
+		Help:      "Time spent handling callback queries by action of callback data.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"action"})
+
+	TelegramErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "telegram_api_errors_total",
+		Help:      "Failed Bot API requests by method and error code. Code is \"network\" when no response was received.",
+	}, []string{"method", "code"})
+
+	StorageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "storage_operation_duration_seconds",
+		Help:      "Time spent in storage operations.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"operation"})
+)
+
+// Handler serves metrics of the default registry in Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,285 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase/storage"
+)
+
+// Storage measures how long operations of the wrapped storage take.
+type Storage struct {
+	s storage.Storage
+}
+
+func NewStorage(s storage.Storage) *Storage {
+	return &Storage{s: s}
+}
+
+func observeStorage(operation string, startTime time.Time) {
+	StorageDuration.WithLabelValues(operation).Observe(time.Since(startTime).Seconds())
+}
+
+func (s Storage) CreateQueue(
+	ctx context.Context,
+	messageID string,
+	description string,
+	ownerID int64,
+	language string,
+	capacity int,
+) error {
+	defer observeStorage("create_queue", time.Now())
+
+	return s.s.CreateQueue(ctx, messageID, description, ownerID, language, capacity)
+}
+
+func (s Storage) LogInOutToQueue(ctx context.Context, messageID string, user entity.User) error {
+	defer observeStorage("log_in_out_to_queue", time.Now())
+
+	return s.s.LogInOutToQueue(ctx, messageID, user)
+}
+
+func (s Storage) JoinWaitlist(ctx context.Context, messageID string, user entity.User) error {
+	defer observeStorage("join_waitlist", time.Now())
+
+	return s.s.JoinWaitlist(ctx, messageID, user)
+}
+
+func (s Storage) PromoteFromWaitlist(ctx context.Context, messageID string, userID int64) error {
+	defer observeStorage("promote_from_waitlist", time.Now())
+
+	return s.s.PromoteFromWaitlist(ctx, messageID, userID)
+}
+
+func (s Storage) GetQueue(ctx context.Context, messageID string) (entity.Queue, error) {
+	defer observeStorage("get_queue", time.Now())
+
+	return s.s.GetQueue(ctx, messageID)
+}
+
+func (s Storage) StartQueue(ctx context.Context, messageID string, isShuffle bool) error {
+	defer observeStorage("start_queue", time.Now())
+
+	return s.s.StartQueue(ctx, messageID, isShuffle)
+}
+
+func (s Storage) IncrementCurrentPerson(ctx context.Context, messageID string) error {
+	defer observeStorage("increment_current_person", time.Now())
+
+	return s.s.IncrementCurrentPerson(ctx, messageID)
+}
+
+func (s Storage) DecrementCurrentPerson(ctx context.Context, messageID string) error {
+	defer observeStorage("decrement_current_person", time.Now())
+
+	return s.s.DecrementCurrentPerson(ctx, messageID)
+}
+
+func (s Storage) StopQueue(ctx context.Context, messageID string) error {
+	defer observeStorage("stop_queue", time.Now())
+
+	return s.s.StopQueue(ctx, messageID)
+}
+
+func (s Storage) ArchiveQueue(ctx context.Context, messageID string) error {
+	defer observeStorage("archive_queue", time.Now())
+
+	return s.s.ArchiveQueue(ctx, messageID)
+}
+
+func (s Storage) MoveParticipant(ctx context.Context, messageID string, userID int64, position int) error {
+	defer observeStorage("move_participant", time.Now())
+
+	return s.s.MoveParticipant(ctx, messageID, userID, position)
+}
+
+func (s Storage) GetFinishedQueues(ctx context.Context, userID int64, limit int) ([]entity.FinishedQueue, error) {
+	defer observeStorage("get_finished_queues", time.Now())
+
+	return s.s.GetFinishedQueues(ctx, userID, limit)
+}
+
+func (s Storage) SaveSnapshot(ctx context.Context, messageID string, operation entity.Operation) error {
+	defer observeStorage("save_snapshot", time.Now())
+
+	return s.s.SaveSnapshot(ctx, messageID, operation)
+}
+
+func (s Storage) RestoreSnapshot(ctx context.Context, messageID string) (entity.Operation, error) {
+	defer observeStorage("restore_snapshot", time.Now())
+
+	return s.s.RestoreSnapshot(ctx, messageID)
+}
+
+func (s Storage) AddEvent(ctx context.Context, messageID string, operation entity.Operation, userID int64) error {
+	defer observeStorage("add_event", time.Now())
+
+	return s.s.AddEvent(ctx, messageID, operation, userID)
+}
+
+func (s Storage) GetEvents(ctx context.Context, messageID string, limit int) ([]entity.Event, error) {
+	defer observeStorage("get_events", time.Now())
+
+	return s.s.GetEvents(ctx, messageID, limit)
+}
+
+func (s Storage) AddAdmin(ctx context.Context, messageID string, userID int64) error {
+	defer observeStorage("add_admin", time.Now())
+
+	return s.s.AddAdmin(ctx, messageID, userID)
+}
+
+func (s Storage) RemoveAdmin(ctx context.Context, messageID string, userID int64) error {
+	defer observeStorage("remove_admin", time.Now())
+
+	return s.s.RemoveAdmin(ctx, messageID, userID)
+}
+
+func (s Storage) GetAdminQueueIDs(ctx context.Context, userID int64) ([]string, error) {
+	defer observeStorage("get_admin_queue_ids", time.Now())
+
+	return s.s.GetAdminQueueIDs(ctx, userID)
+}
+
+func (s Storage) SetNotifyCount(ctx context.Context, messageID string, count int) error {
+	defer observeStorage("set_notify_count", time.Now())
+
+	return s.s.SetNotifyCount(ctx, messageID, count)
+}
+
+func (s Storage) SetLanguage(ctx context.Context, messageID string, language string) error {
+	defer observeStorage("set_language", time.Now())
+
+	return s.s.SetLanguage(ctx, messageID, language)
+}
+
+func (s Storage) SetTurnDuration(ctx context.Context, messageID string, duration time.Duration) error {
+	defer observeStorage("set_turn_duration", time.Now())
+
+	return s.s.SetTurnDuration(ctx, messageID, duration)
+}
+
+func (s Storage) SetTurn(ctx context.Context, messageID string, turn entity.Turn) error {
+	defer observeStorage("set_turn", time.Now())
+
+	return s.s.SetTurn(ctx, messageID, turn)
+}
+
+func (s Storage) GetTimedQueueIDs(ctx context.Context) ([]string, error) {
+	defer observeStorage("get_timed_queue_ids", time.Now())
+
+	return s.s.GetTimedQueueIDs(ctx)
+}
+
+func (s Storage) SetSchedule(ctx context.Context, messageID string, schedule entity.Schedule) error {
+	defer observeStorage("set_schedule", time.Now())
+
+	return s.s.SetSchedule(ctx, messageID, schedule)
+}
+
+func (s Storage) GetScheduledQueueIDs(ctx context.Context) ([]string, error) {
+	defer observeStorage("get_scheduled_queue_ids", time.Now())
+
+	return s.s.GetScheduledQueueIDs(ctx)
+}
+
+func (s Storage) CreateTemplate(ctx context.Context, template entity.Template) (int64, error) {
+	defer observeStorage("create_template", time.Now())
+
+	return s.s.CreateTemplate(ctx, template)
+}
+
+func (s Storage) GetTemplate(ctx context.Context, templateID int64) (entity.Template, error) {
+	defer observeStorage("get_template", time.Now())
+
+	return s.s.GetTemplate(ctx, templateID)
+}
+
+func (s Storage) GetTemplates(ctx context.Context, ownerID int64) ([]entity.Template, error) {
+	defer observeStorage("get_templates", time.Now())
+
+	return s.s.GetTemplates(ctx, ownerID)
+}
+
+func (s Storage) DeleteTemplate(ctx context.Context, templateID int64) error {
+	defer observeStorage("delete_template", time.Now())
+
+	return s.s.DeleteTemplate(ctx, templateID)
+}
+
+func (s Storage) SetQueueTemplate(ctx context.Context, messageID string, templateID int64) error {
+	defer observeStorage("set_queue_template", time.Now())
+
+	return s.s.SetQueueTemplate(ctx, messageID, templateID)
+}
+
+func (s Storage) SetSlotCount(ctx context.Context, messageID string, count int) error {
+	defer observeStorage("set_slot_count", time.Now())
+
+	return s.s.SetSlotCount(ctx, messageID, count)
+}
+
+func (s Storage) CallToSlot(ctx context.Context, messageID string, slot int, userID int64) error {
+	defer observeStorage("call_to_slot", time.Now())
+
+	return s.s.CallToSlot(ctx, messageID, slot, userID)
+}
+
+func (s Storage) FreeSlot(ctx context.Context, messageID string, slot int) error {
+	defer observeStorage("free_slot", time.Now())
+
+	return s.s.FreeSlot(ctx, messageID, slot)
+}
+
+func (s Storage) SetCheckInTimeout(ctx context.Context, messageID string, timeout time.Duration) error {
+	defer observeStorage("set_check_in_timeout", time.Now())
+
+	return s.s.SetCheckInTimeout(ctx, messageID, timeout)
+}
+
+func (s Storage) SetCheckIn(ctx context.Context, messageID string, userID int64, checkIn entity.CheckIn) error {
+	defer observeStorage("set_check_in", time.Now())
+
+	return s.s.SetCheckIn(ctx, messageID, userID, checkIn)
+}
+
+func (s Storage) GetCheckInQueueIDs(ctx context.Context) ([]string, error) {
+	defer observeStorage("get_check_in_queue_ids", time.Now())
+
+	return s.s.GetCheckInQueueIDs(ctx)
+}
+
+func (s Storage) AddSubscriber(ctx context.Context, userID int64) error {
+	defer observeStorage("add_subscriber", time.Now())
+
+	return s.s.AddSubscriber(ctx, userID)
+}
+
+func (s Storage) RemoveSubscriber(ctx context.Context, userID int64) error {
+	defer observeStorage("remove_subscriber", time.Now())
+
+	return s.s.RemoveSubscriber(ctx, userID)
+}
+
+func (s Storage) IsSubscriber(ctx context.Context, userID int64) (bool, error) {
+	defer observeStorage("is_subscriber", time.Now())
+
+	return s.s.IsSubscriber(ctx, userID)
+}
+
+func (s Storage) CountActive(ctx context.Context) (queues int, participants int, err error) {
+	defer observeStorage("count_active", time.Now())
+
+	return s.s.CountActive(ctx)
+}
+
+func (s Storage) Ping(ctx context.Context) error {
+	defer observeStorage("ping", time.Now())
+
+	return s.s.Ping(ctx)
+}
+
+func (s Storage) Close() error {
+	return s.s.Close()
+}
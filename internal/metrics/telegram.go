@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// networkErrorCode labels requests which didn't get a response from Telegram.
+const networkErrorCode = "network"
+
+// HTTPClient counts failed Bot API requests in TelegramErrors.
+// Telegram reports errors in the response body, so the body is read here and handed over unchanged.
+type HTTPClient struct {
+	next tgbotapi.HTTPClient
+}
+
+func NewHTTPClient(next tgbotapi.HTTPClient) *HTTPClient {
+	return &HTTPClient{next: next}
+}
+
+func (c HTTPClient) Do(r *http.Request) (*http.Response, error) {
+	// Path is /bot<token>/<method>, so only its last element may be used as a label.
+	method := path.Base(r.URL.Path)
+
+	resp, err := c.next.Do(r)
+	if err != nil {
+		TelegramErrors.WithLabelValues(method, networkErrorCode).Inc()
+
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if err != nil {
+		TelegramErrors.WithLabelValues(method, networkErrorCode).Inc()
+
+		return nil, fmt.Errorf("couldn't read %s response: %w", method, err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var apiResp struct {
+		Ok        bool `json:"ok"`
+		ErrorCode int  `json:"error_code"`
+	}
+	if err = json.Unmarshal(body, &apiResp); err == nil && !apiResp.Ok {
+		TelegramErrors.WithLabelValues(method, strconv.Itoa(apiResp.ErrorCode)).Inc()
+	}
+
+	return resp, nil
+}
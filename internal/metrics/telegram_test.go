@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHTTPClient struct {
+	body string
+	err  error
+}
+
+func (f fakeHTTPClient) Do(*http.Request) (*http.Response, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(f.body))}, nil
+}
+
+func TestHTTPClient_Do(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		client    fakeHTTPClient
+		wantCode  string
+		wantCount float64
+	}{
+		{
+			name:      "Successful request isn't counted",
+			method:    "sendMessage",
+			client:    fakeHTTPClient{body: `{"ok": true, "result": true}`},
+			wantCode:  "0",
+			wantCount: 0,
+		},
+		{
+			name:      "Rate limited request",
+			method:    "editMessageText",
+			client:    fakeHTTPClient{body: `{"ok": false, "error_code": 429, "description": "Too Many Requests"}`},
+			wantCode:  "429",
+			wantCount: 1,
+		},
+		{
+			name:      "Network error",
+			method:    "answerCallbackQuery",
+			client:    fakeHTTPClient{err: errors.New("connection reset")},
+			wantCode:  networkErrorCode,
+			wantCount: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodPost, "https://api.telegram.org/bottoken/"+tt.method, nil)
+			require.NoError(t, err)
+
+			resp, err := NewHTTPClient(tt.client).Do(r)
+			if tt.client.err != nil {
+				assert.ErrorIs(t, err, tt.client.err)
+			} else {
+				require.NoError(t, err)
+
+				// The body is still readable by tgbotapi.
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tt.client.body, string(body))
+			}
+
+			assert.Equal(t, tt.wantCount, testutil.ToFloat64(TelegramErrors.WithLabelValues(tt.method, tt.wantCode)))
+		})
+	}
+}
@@ -2,74 +2,614 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
+	"time"
 
 	"QueueBot/internal/entity"
+	"QueueBot/internal/i18n"
 	"QueueBot/internal/usecase/storage"
 )
 
+var (
+	ErrNotAdmin         = errors.New("user is not an admin of the queue")
+	ErrNotOwner         = errors.New("user is not the owner of the queue")
+	ErrNoCurrentPerson  = errors.New("queue has no current person")
+	ErrAlreadyStarted   = errors.New("queue is already started")
+	ErrInvalidPositions = errors.New("positions to skip must not be negative")
+	ErrInvalidCapacity  = errors.New("capacity must not be negative")
+	ErrNothingToUndo    = errors.New("nothing to undo")
+	ErrNotParticipant   = errors.New("user is not in the queue")
+	ErrJoinNotOpen      = errors.New("joining the queue hasn't opened yet")
+	ErrJoinClosed       = errors.New("joining the queue has closed before its start")
+	ErrInvalidSchedule  = errors.New("queue must open before it starts")
+	ErrInvalidTemplate  = errors.New("template must have a description, valid capacity and both or none clock times")
+	ErrInvalidSlot      = errors.New("queue has no such slot")
+	ErrCheckInNotAsked  = errors.New("user wasn't asked to confirm their presence")
+	// ErrHasSlots is returned for operations on the current person of a queue where several people pass at once.
+	ErrHasSlots = errors.New("queue has several slots")
+	// ErrUserUnreachable is returned by Notifier when user has blocked the bot.
+	ErrUserUnreachable = errors.New("user can't receive private messages")
+)
+
+// SkipToEnd makes SkipCurrentPerson move the current person to the end of the queue.
+const SkipToEnd = 0
+
+const (
+	// DefaultEventsLimit is how many events GetEvents returns when limit isn't positive.
+	DefaultEventsLimit = 10
+	// MaxEventsLimit is the most events GetEvents returns at once.
+	MaxEventsLimit = 50
+)
+
+// FinishedQueuesLimit is how many latest finished queues GetFinishedQueues returns.
+const FinishedQueuesLimit = 10
+
+// NotifyCounts are values of Queue.NotifyCount admins switch between, in order.
+var NotifyCounts = []int{0, 1, 2, 3, 5}
+
+// Notifier sends private messages to users.
+type Notifier interface {
+	// NotifyTurn tells user how many people are left before their turn in the queue. Zero means it's their turn.
+	// The message is in the language of the queue.
+	NotifyTurn(ctx context.Context, userID int64, description string, language string, peopleBefore int) error
+	// NotifyTurnEnding tells the current person how much time is left before the queue advances by itself.
+	NotifyTurnEnding(ctx context.Context, userID int64, description string, language string, left time.Duration) error
+	// AskCheckIn asks user to confirm within timeout that they are present, the answer is sent for the queue messageID.
+	AskCheckIn(ctx context.Context, userID int64, messageID string, description string, language string, timeout time.Duration) error
+}
+
+// Observer is told about changes of queues, e.g. to refresh views of them outside Telegram.
+type Observer interface {
+	// QueueChanged is called after every change of the queue. It must not block.
+	QueueChanged(messageID string)
+}
+
 type Bot interface {
-	CreateQueue(ctx context.Context, messageID string, description string) error
+	CreateQueue(ctx context.Context, messageID string, description string, ownerID int64, language string, capacity int) error
 	LogInOutToQueue(ctx context.Context, messageID string, user entity.User) error
-	StartQueue(ctx context.Context, messageID string, shuffle bool) error
-	FinishQueue(ctx context.Context, messageID string) error
-	SetNextPersonToQueue(ctx context.Context, messageID string) error
+	StartQueue(ctx context.Context, messageID string, userID int64, shuffle bool) error
+	FinishQueue(ctx context.Context, messageID string, userID int64) error
+	SetNextPersonToQueue(ctx context.Context, messageID string, userID int64) error
+	SetPreviousPersonToQueue(ctx context.Context, messageID string, userID int64) error
+	SkipCurrentPerson(ctx context.Context, messageID string, userID int64, positions int) error
+	StopQueue(ctx context.Context, messageID string, userID int64) error
+	Undo(ctx context.Context, messageID string, userID int64) (entity.Operation, error)
+	RemoveParticipant(ctx context.Context, messageID string, adminID int64, userID int64) error
 	GetQueue(ctx context.Context, messageID string) (entity.Queue, error)
+	GetAdminQueues(ctx context.Context, userID int64) ([]entity.Queue, error)
+	GetEvents(ctx context.Context, messageID string, limit int) ([]entity.Event, error)
+	GetFinishedQueues(ctx context.Context, userID int64) ([]entity.FinishedQueue, error)
+	Subscribe(ctx context.Context, userID int64) error
+	SwitchNotifyCount(ctx context.Context, messageID string, userID int64) error
+	SwitchLanguage(ctx context.Context, messageID string, userID int64) error
+	SwitchTurnDuration(ctx context.Context, messageID string, userID int64) error
+	SwitchSlotCount(ctx context.Context, messageID string, userID int64) error
+	FinishSlot(ctx context.Context, messageID string, userID int64, slot int) error
+	SwitchCheckInTimeout(ctx context.Context, messageID string, userID int64) error
+	ConfirmCheckIn(ctx context.Context, messageID string, userID int64) error
+	GetCheckInQueueIDs(ctx context.Context) ([]string, error)
+	IsCheckInDue(ctx context.Context, messageID string, now time.Time) (bool, error)
+	UpdateCheckIns(ctx context.Context, messageID string, now time.Time) error
+	GetTimedQueueIDs(ctx context.Context) ([]string, error)
+	CheckTurn(ctx context.Context, messageID string, now time.Time) (isDue bool, err error)
+	UpdateTurn(ctx context.Context, messageID string, now time.Time) error
+	ScheduleQueue(ctx context.Context, messageID string, userID int64, schedule entity.Schedule) error
+	GetDueQueueIDs(ctx context.Context, now time.Time) ([]string, error)
+	StartScheduledQueue(ctx context.Context, messageID string, now time.Time) error
+	CreateTemplate(ctx context.Context, template entity.Template) (int64, error)
+	GetTemplate(ctx context.Context, templateID int64, userID int64) (entity.Template, error)
+	GetTemplates(ctx context.Context, ownerID int64) ([]entity.Template, error)
+	DeleteTemplate(ctx context.Context, templateID int64, userID int64) error
+	SetQueueTemplate(ctx context.Context, messageID string, userID int64, templateID int64) error
+
+	CheckAdmin(ctx context.Context, messageID string, userID int64) error
+	CheckOwner(ctx context.Context, messageID string, userID int64) error
+	ToggleAdmin(ctx context.Context, messageID string, ownerID int64, userID int64) error
 }
 
 type BotUseCase struct {
 	Storage storage.Storage
+	// Notifier is optional, nobody is notified without it.
+	Notifier Notifier
+	// Observer is optional.
+	Observer Observer
+	// Now tells whether joining has opened. Tests replace it.
+	Now func() time.Time
 }
 
-func NewBotUseCase(storage storage.Storage) *BotUseCase {
-	return &BotUseCase{Storage: storage}
+func NewBotUseCase(storage storage.Storage, notifier Notifier) *BotUseCase {
+	return &BotUseCase{Storage: storage, Notifier: notifier, Now: time.Now}
 }
 
-func (b BotUseCase) CreateQueue(ctx context.Context, messageID string, description string) error {
-	err := b.Storage.CreateQueue(ctx, messageID, description)
+// CreateQueue creates the queue for at most capacity people, others join its waitlist. Zero capacity means unlimited.
+func (b BotUseCase) CreateQueue(
+	ctx context.Context,
+	messageID string,
+	description string,
+	ownerID int64,
+	language string,
+	capacity int,
+) error {
+	if capacity < 0 {
+		return fmt.Errorf("got %d: %w", capacity, ErrInvalidCapacity)
+	}
+
+	err := b.Storage.CreateQueue(ctx, messageID, description, ownerID, language, capacity)
 	if err != nil {
 		return fmt.Errorf("couldn't create queue in storage with error: %w", err)
 	}
 
-	return nil
+	return b.addEvent(ctx, messageID, entity.OperationCreate, ownerID)
 }
 
+// LogInOutToQueue takes user out of the queue or its waitlist, or adds user to it.
+// People who join the full queue go to its waitlist. The first of them takes the place of a person who leaves.
+// Nobody can join before the scheduled opening or after the scheduled start until the queue is started,
+// but people can always leave.
 func (b BotUseCase) LogInOutToQueue(ctx context.Context, messageID string, user entity.User) error {
-	err := b.Storage.LogInOutToQueue(ctx, messageID, user)
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	operation := entity.OperationJoin
+
+	switch {
+	case queue.HasUser(user.ID), queue.IsWaitlisted(user.ID):
+		operation = entity.OperationLeave
+	case b.Now().Before(queue.Schedule.OpensAt):
+		return fmt.Errorf("queue %s opens at %s: %w", messageID, queue.Schedule.OpensAt, ErrJoinNotOpen)
+	case !queue.IsStarted && !queue.Schedule.StartsAt.IsZero() && !b.Now().Before(queue.Schedule.StartsAt):
+		return fmt.Errorf("queue %s starts at %s: %w", messageID, queue.Schedule.StartsAt, ErrJoinClosed)
+	case queue.IsFull():
+		operation = entity.OperationWaitlist
+	}
+
+	if err = b.saveSnapshot(ctx, messageID, operation); err != nil {
+		return err
+	}
+
+	if operation == entity.OperationWaitlist {
+		err = b.Storage.JoinWaitlist(ctx, messageID, user)
+	} else {
+		err = b.Storage.LogInOutToQueue(ctx, messageID, user)
+	}
+
 	if err != nil {
 		return fmt.Errorf("couldn't add user to queue in storage with error: %w", err)
 	}
 
-	return nil
+	if err = b.addEvent(ctx, messageID, operation, user.ID); err != nil {
+		return err
+	}
+
+	if !queue.HasUser(user.ID) {
+		return nil
+	}
+
+	return b.promoteFromWaitlist(ctx, queue)
+}
+
+// promoteFromWaitlist gives the place of a person who left the queue to the first waitlisted one.
+// The queue is the state before the person left.
+func (b BotUseCase) promoteFromWaitlist(ctx context.Context, queue entity.Queue) error {
+	if len(queue.Waitlist) == 0 {
+		return nil
+	}
+
+	promoted := queue.Waitlist[0]
+	if err := b.Storage.PromoteFromWaitlist(ctx, queue.MessageID, promoted.ID); err != nil {
+		return fmt.Errorf("couldn't promote user from waitlist in storage with error: %w", err)
+	}
+
+	return b.addEvent(ctx, queue.MessageID, entity.OperationPromote, promoted.ID)
 }
 
-func (b BotUseCase) StartQueue(ctx context.Context, messageID string, shuffle bool) error {
-	err := b.Storage.StartQueue(ctx, messageID, shuffle)
+// StartQueue numbers participants in the order they joined or in random order.
+// The scheduled start is cancelled, so the queue doesn't start again after it is stopped.
+func (b BotUseCase) StartQueue(ctx context.Context, messageID string, userID int64, shuffle bool) error {
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	if !queue.IsAdmin(userID) {
+		return fmt.Errorf("user %d in queue %s: %w", userID, messageID, ErrNotAdmin)
+	}
+
+	// Starting again would renumber people and make the first one current, so the queue is stopped first.
+	if queue.IsStarted {
+		return fmt.Errorf("queue %s: %w", messageID, ErrAlreadyStarted)
+	}
+
+	operation := entity.OperationStart
+	if shuffle {
+		operation = entity.OperationShuffle
+	}
+
+	if err = b.saveSnapshot(ctx, messageID, operation); err != nil {
+		return err
+	}
+
+	err = b.Storage.StartQueue(ctx, messageID, shuffle)
 	if err != nil {
 		return fmt.Errorf("couldn't start queue in storage with error: %w", err)
 	}
 
-	return nil
+	if !queue.Schedule.StartsAt.IsZero() {
+		if err = b.Storage.SetSchedule(ctx, messageID, entity.Schedule{OpensAt: queue.Schedule.OpensAt}); err != nil {
+			return fmt.Errorf("couldn't cancel scheduled start in storage with error: %w", err)
+		}
+	}
+
+	// The first person may be the one timed before the queue was stopped, so the timing starts over.
+	if err = b.setTurn(ctx, messageID, entity.Turn{}); err != nil {
+		return err
+	}
+
+	if queue.HasSlots() {
+		if err = b.fillSlots(ctx, messageID); err != nil {
+			return err
+		}
+	}
+
+	// Confirmations are asked from scratch, so nobody is moved for the time the queue was stopped.
+	if err = b.resetCheckIns(ctx, queue); err != nil {
+		return err
+	}
+
+	return b.addEvent(ctx, messageID, operation, userID)
 }
 
-func (b BotUseCase) FinishQueue(ctx context.Context, messageID string) error {
-	err := b.Storage.DeleteQueue(ctx, messageID)
+func (b BotUseCase) FinishQueue(ctx context.Context, messageID string, userID int64) error {
+	if err := b.CheckAdmin(ctx, messageID, userID); err != nil {
+		return err
+	}
+
+	err := b.Storage.ArchiveQueue(ctx, messageID)
 	if err != nil {
 		return fmt.Errorf("couldn't finish queue in storage with error: %w", err)
 	}
 
-	return nil
+	return b.addEvent(ctx, messageID, entity.OperationFinish, userID)
 }
 
-func (b BotUseCase) SetNextPersonToQueue(ctx context.Context, messageID string) (err error) {
+// SetNextPersonToQueue makes the person after the current one current. It returns ErrNoCurrentPerson
+// if the queue isn't started or everybody has passed.
+func (b BotUseCase) SetNextPersonToQueue(ctx context.Context, messageID string, userID int64) error {
+	queue, err := b.checkCurrentPersonAdmin(ctx, messageID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !queue.IsStarted || queue.CurrentPersonIdx >= len(queue.Users) {
+		return fmt.Errorf("queue %s: %w", messageID, ErrNoCurrentPerson)
+	}
+
+	if err = b.saveSnapshot(ctx, messageID, entity.OperationNext); err != nil {
+		return err
+	}
+
 	err = b.Storage.IncrementCurrentPerson(ctx, messageID)
 	if err != nil {
 		return fmt.Errorf("couldn't set next person to queue in storage with error: %w", err)
 	}
 
+	if err = b.addEvent(ctx, messageID, entity.OperationNext, userID); err != nil {
+		return err
+	}
+
+	b.notifyTurn(ctx, messageID)
+
+	return nil
+}
+
+// notifyTurn messages the current person and NotifyCount people after them.
+// Notifications are best effort, so errors are only logged.
+func (b BotUseCase) notifyTurn(ctx context.Context, messageID string) {
+	if b.Notifier == nil {
+		return
+	}
+
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		slog.Warn("Couldn't get queue to notify participants", "messageId", messageID, "reason", err)
+
+		return
+	}
+
+	// With slots the current person is the first waiting one, and the last called person is the one before.
+	current := queue.CurrentPersonIdx
+	if queue.HasSlots() {
+		current--
+	}
+
+	for peopleBefore := 0; peopleBefore <= queue.NotifyCount; peopleBefore++ {
+		idx := current + peopleBefore
+		if idx >= len(queue.Users) {
+			break
+		}
+
+		userID := queue.Users[idx].ID
+		b.notifyUser(ctx, queue, userID, func() error {
+			return b.Notifier.NotifyTurn(ctx, userID, queue.Description, queue.Language, peopleBefore)
+		})
+	}
+}
+
+// notifyUser sends a private message with notify if user has started the bot. Users who blocked it are unsubscribed.
+func (b BotUseCase) notifyUser(ctx context.Context, queue entity.Queue, userID int64, notify func() error) {
+	isSubscriber, err := b.Storage.IsSubscriber(ctx, userID)
+	if err != nil {
+		slog.Warn("Couldn't check subscriber", "userId", userID, "reason", err)
+
+		return
+	}
+
+	if !isSubscriber {
+		return
+	}
+
+	err = notify()
+	switch {
+	case errors.Is(err, ErrUserUnreachable):
+		if err = b.Storage.RemoveSubscriber(ctx, userID); err != nil {
+			slog.Warn("Couldn't remove unreachable subscriber", "userId", userID, "reason", err)
+		}
+	case err != nil:
+		slog.Warn("Couldn't notify participant", "messageId", queue.MessageID, "userId", userID, "reason", err)
+	}
+}
+
+// Subscribe lets the bot send private messages to user. It's called when user starts the bot.
+func (b BotUseCase) Subscribe(ctx context.Context, userID int64) error {
+	if err := b.Storage.AddSubscriber(ctx, userID); err != nil {
+		return fmt.Errorf("couldn't add subscriber in storage with error: %w", err)
+	}
+
+	return nil
+}
+
+// SwitchNotifyCount sets NotifyCount of the queue to the value following the current one in NotifyCounts.
+func (b BotUseCase) SwitchNotifyCount(ctx context.Context, messageID string, userID int64) error {
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	if !queue.IsAdmin(userID) {
+		return fmt.Errorf("user %d in queue %s: %w", userID, messageID, ErrNotAdmin)
+	}
+
+	// Index is -1 for values not in NotifyCounts, so they are switched to the first one.
+	next := NotifyCounts[(slices.Index(NotifyCounts, queue.NotifyCount)+1)%len(NotifyCounts)]
+
+	if err = b.Storage.SetNotifyCount(ctx, messageID, next); err != nil {
+		return fmt.Errorf("couldn't set notify count in storage with error: %w", err)
+	}
+
+	b.queueChanged(messageID)
+
+	return nil
+}
+
+// SwitchLanguage sets Language of the queue to the language following the current one in i18n.Languages.
+func (b BotUseCase) SwitchLanguage(ctx context.Context, messageID string, userID int64) error {
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	if !queue.IsAdmin(userID) {
+		return fmt.Errorf("user %d in queue %s: %w", userID, messageID, ErrNotAdmin)
+	}
+
+	next := i18n.Languages[(slices.Index(i18n.Languages, i18n.Language(queue.Language))+1)%len(i18n.Languages)]
+
+	if err = b.Storage.SetLanguage(ctx, messageID, string(next)); err != nil {
+		return fmt.Errorf("couldn't set language in storage with error: %w", err)
+	}
+
+	b.queueChanged(messageID)
+
+	return nil
+}
+
+func (b BotUseCase) SetPreviousPersonToQueue(ctx context.Context, messageID string, userID int64) error {
+	if _, err := b.checkCurrentPersonAdmin(ctx, messageID, userID); err != nil {
+		return err
+	}
+
+	if err := b.saveSnapshot(ctx, messageID, entity.OperationPrevious); err != nil {
+		return err
+	}
+
+	err := b.Storage.DecrementCurrentPerson(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("couldn't set previous person to queue in storage with error: %w", err)
+	}
+
+	return b.addEvent(ctx, messageID, entity.OperationPrevious, userID)
+}
+
+// StopQueue returns started queue to the menu, where participants can join and leave again.
+func (b BotUseCase) StopQueue(ctx context.Context, messageID string, userID int64) error {
+	if err := b.CheckAdmin(ctx, messageID, userID); err != nil {
+		return err
+	}
+
+	if err := b.saveSnapshot(ctx, messageID, entity.OperationStop); err != nil {
+		return err
+	}
+
+	err := b.Storage.StopQueue(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("couldn't stop queue in storage with error: %w", err)
+	}
+
+	return b.addEvent(ctx, messageID, entity.OperationStop, userID)
+}
+
+// Undo reverts the latest change of the queue and returns what was reverted.
+func (b BotUseCase) Undo(ctx context.Context, messageID string, userID int64) (entity.Operation, error) {
+	if err := b.CheckAdmin(ctx, messageID, userID); err != nil {
+		return "", err
+	}
+
+	operation, err := b.Storage.RestoreSnapshot(ctx, messageID)
+	if errors.Is(err, storage.ErrNoSnapshots) {
+		return "", fmt.Errorf("queue %s: %w", messageID, ErrNothingToUndo)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("couldn't restore snapshot from storage with error: %w", err)
+	}
+
+	if err = b.addEvent(ctx, messageID, entity.OperationUndo, userID); err != nil {
+		return "", err
+	}
+
+	return operation, nil
+}
+
+// GetEvents returns the latest events of the queue, newest first.
+func (b BotUseCase) GetEvents(ctx context.Context, messageID string, limit int) ([]entity.Event, error) {
+	if limit <= 0 {
+		limit = DefaultEventsLimit
+	}
+
+	if limit > MaxEventsLimit {
+		limit = MaxEventsLimit
+	}
+
+	events, err := b.Storage.GetEvents(ctx, messageID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get events from storage with error: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetFinishedQueues returns the latest finished queues the user was in, newest first.
+func (b BotUseCase) GetFinishedQueues(ctx context.Context, userID int64) ([]entity.FinishedQueue, error) {
+	queues, err := b.Storage.GetFinishedQueues(ctx, userID, FinishedQueuesLimit)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get finished queues from storage with error: %w", err)
+	}
+
+	return queues, nil
+}
+
+func (b BotUseCase) addEvent(ctx context.Context, messageID string, operation entity.Operation, userID int64) error {
+	if err := b.Storage.AddEvent(ctx, messageID, operation, userID); err != nil {
+		return fmt.Errorf("couldn't add %s event in storage with error: %w", operation, err)
+	}
+
+	b.queueChanged(messageID)
+
 	return nil
 }
 
+// queueChanged tells the observer about the change. Changes of participants are told about with their events,
+// settings which aren't recorded as events are told about by their setters.
+func (b BotUseCase) queueChanged(messageID string) {
+	if b.Observer != nil {
+		b.Observer.QueueChanged(messageID)
+	}
+}
+
+func (b BotUseCase) saveSnapshot(ctx context.Context, messageID string, operation entity.Operation) error {
+	if err := b.Storage.SaveSnapshot(ctx, messageID, operation); err != nil {
+		return fmt.Errorf("couldn't save snapshot before %s in storage with error: %w", operation, err)
+	}
+
+	return nil
+}
+
+// SkipCurrentPerson moves the current person back by positions, so the next person becomes current.
+// It can be done by admins or by the current person.
+func (b BotUseCase) SkipCurrentPerson(ctx context.Context, messageID string, userID int64, positions int) error {
+	if positions < 0 {
+		return fmt.Errorf("got %d: %w", positions, ErrInvalidPositions)
+	}
+
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	if queue.HasSlots() {
+		return fmt.Errorf("queue %s: %w", messageID, ErrHasSlots)
+	}
+
+	if queue.CurrentPersonIdx >= len(queue.Users) {
+		return fmt.Errorf("queue %s: %w", messageID, ErrNoCurrentPerson)
+	}
+
+	current := queue.Users[queue.CurrentPersonIdx]
+	if current.ID != userID && !queue.IsAdmin(userID) {
+		return fmt.Errorf("user %d in queue %s: %w", userID, messageID, ErrNotAdmin)
+	}
+
+	position := queue.CurrentPersonIdx + positions
+	if positions == SkipToEnd {
+		position = len(queue.Users)
+	}
+
+	if err = b.saveSnapshot(ctx, messageID, entity.OperationSkip); err != nil {
+		return err
+	}
+
+	if err = b.Storage.MoveParticipant(ctx, messageID, current.ID, position); err != nil {
+		return fmt.Errorf("couldn't skip current person in storage with error: %w", err)
+	}
+
+	return b.addEvent(ctx, messageID, entity.OperationSkip, userID)
+}
+
+// RemoveParticipant takes user out of the queue on behalf of admin. The current person stays current.
+func (b BotUseCase) RemoveParticipant(ctx context.Context, messageID string, adminID int64, userID int64) error {
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	if !queue.IsAdmin(adminID) {
+		return fmt.Errorf("user %d in queue %s: %w", adminID, messageID, ErrNotAdmin)
+	}
+
+	idx := slices.IndexFunc(queue.Users, func(user entity.User) bool {
+		return user.ID == userID
+	})
+	if idx < 0 {
+		return fmt.Errorf("user %d in queue %s: %w", userID, messageID, ErrNotParticipant)
+	}
+
+	if err = b.saveSnapshot(ctx, messageID, entity.OperationRemove); err != nil {
+		return err
+	}
+
+	if err = b.Storage.LogInOutToQueue(ctx, messageID, queue.Users[idx]); err != nil {
+		return fmt.Errorf("couldn't remove participant in storage with error: %w", err)
+	}
+
+	// People after the removed one move up, so the index has to follow the current person.
+	if queue.IsStarted && idx < queue.CurrentPersonIdx {
+		if err = b.Storage.DecrementCurrentPerson(ctx, messageID); err != nil {
+			return fmt.Errorf("couldn't keep current person in storage with error: %w", err)
+		}
+	}
+
+	if err = b.addEvent(ctx, messageID, entity.OperationRemove, adminID); err != nil {
+		return err
+	}
+
+	return b.promoteFromWaitlist(ctx, queue)
+}
+
 func (b BotUseCase) GetQueue(ctx context.Context, messageID string) (entity.Queue, error) {
 	queue, err := b.Storage.GetQueue(ctx, messageID)
 	if err != nil {
@@ -78,3 +618,106 @@ func (b BotUseCase) GetQueue(ctx context.Context, messageID string) (entity.Queu
 
 	return queue, nil
 }
+
+// GetAdminQueues returns not finished queues the user owns or administers.
+func (b BotUseCase) GetAdminQueues(ctx context.Context, userID int64) ([]entity.Queue, error) {
+	messageIDs, err := b.Storage.GetAdminQueueIDs(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get admin queues from storage with error: %w", err)
+	}
+
+	queues := make([]entity.Queue, 0, len(messageIDs))
+
+	for _, messageID := range messageIDs {
+		queue, err := b.GetQueue(ctx, messageID)
+		if errors.Is(err, storage.ErrQueueNotFound) {
+			// The queue was finished in the meantime.
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		queues = append(queues, queue)
+	}
+
+	return queues, nil
+}
+
+// CheckAdmin returns ErrNotAdmin if user isn't allowed to start, advance or finish the queue.
+func (b BotUseCase) CheckAdmin(ctx context.Context, messageID string, userID int64) error {
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	if !queue.IsAdmin(userID) {
+		return fmt.Errorf("user %d in queue %s: %w", userID, messageID, ErrNotAdmin)
+	}
+
+	return nil
+}
+
+// checkCurrentPersonAdmin returns ErrNotAdmin if user isn't allowed to change the current person,
+// and ErrHasSlots if the queue has no single current person. The checked queue is returned.
+func (b BotUseCase) checkCurrentPersonAdmin(ctx context.Context, messageID string, userID int64) (entity.Queue, error) {
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return entity.Queue{}, err
+	}
+
+	if !queue.IsAdmin(userID) {
+		return entity.Queue{}, fmt.Errorf("user %d in queue %s: %w", userID, messageID, ErrNotAdmin)
+	}
+
+	if queue.HasSlots() {
+		return entity.Queue{}, fmt.Errorf("queue %s: %w", messageID, ErrHasSlots)
+	}
+
+	return queue, nil
+}
+
+// CheckOwner returns ErrNotOwner if user isn't allowed to manage admins of the queue.
+func (b BotUseCase) CheckOwner(ctx context.Context, messageID string, userID int64) error {
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	if !queue.IsOwner(userID) {
+		return fmt.Errorf("user %d in queue %s: %w", userID, messageID, ErrNotOwner)
+	}
+
+	return nil
+}
+
+// ToggleAdmin grants admin rights to user or takes them away if user is already an admin.
+func (b BotUseCase) ToggleAdmin(ctx context.Context, messageID string, ownerID int64, userID int64) error {
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	if !queue.IsOwner(ownerID) {
+		return fmt.Errorf("user %d in queue %s: %w", ownerID, messageID, ErrNotOwner)
+	}
+
+	if userID == queue.OwnerID {
+		return nil
+	}
+
+	operation := entity.OperationAddAdmin
+	if queue.IsCoAdmin(userID) {
+		operation = entity.OperationRemoveAdmin
+		err = b.Storage.RemoveAdmin(ctx, messageID, userID)
+	} else {
+		err = b.Storage.AddAdmin(ctx, messageID, userID)
+	}
+
+	if err != nil {
+		return fmt.Errorf("couldn't toggle admin in storage with error: %w", err)
+	}
+
+	return b.addEvent(ctx, messageID, operation, ownerID)
+}
@@ -0,0 +1,509 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/i18n"
+	"QueueBot/internal/usecase/storage"
+	"QueueBot/internal/usecase/storage/memory"
+)
+
+func TestBotUseCase_QueueLifecycle(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	first := entity.User{ID: 1, Name: "First"}
+	second := entity.User{ID: 2, Name: "Second"}
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", first.ID, "ru", 0))
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", first))
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", second))
+	require.NoError(t, u.StartQueue(ctx, "123", first.ID, false))
+	require.NoError(t, u.SetNextPersonToQueue(ctx, "123", first.ID))
+
+	queue, err := u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, entity.Queue{
+		MessageID:        "123",
+		Description:      "Test",
+		Users:            []entity.User{first, second},
+		CurrentPersonIdx: 1,
+		OwnerID:          first.ID,
+		IsStarted:        true,
+		NotifyCount:      storage.DefaultNotifyCount,
+		Language:         "ru",
+		SlotCount:        storage.DefaultSlotCount,
+	}, queue)
+
+	require.NoError(t, u.FinishQueue(ctx, "123", first.ID))
+
+	_, err = u.GetQueue(ctx, "123")
+	assert.ErrorIs(t, err, storage.ErrQueueNotFound)
+
+	finished, err := u.GetFinishedQueues(ctx, second.ID)
+	require.NoError(t, err)
+	require.Len(t, finished, 1)
+	assert.Equal(t, "Test", finished[0].Description)
+	assert.Equal(t, 1, finished[0].Position)
+	assert.Equal(t, 2, finished[0].Participants)
+	assert.False(t, finished[0].Passed())
+}
+
+func TestBotUseCase_UnknownQueue(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	assert.ErrorIs(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: 1}), storage.ErrQueueNotFound)
+	assert.ErrorIs(t, u.StartQueue(ctx, "123", 1, true), storage.ErrQueueNotFound)
+	assert.ErrorIs(t, u.SetNextPersonToQueue(ctx, "123", 1), storage.ErrQueueNotFound)
+}
+
+func TestBotUseCase_Permissions(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	const (
+		owner    = int64(1)
+		coAdmin  = int64(2)
+		stranger = int64(3)
+	)
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", owner, "ru", 0))
+
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: owner}))
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: stranger}))
+
+	assert.ErrorIs(t, u.StartQueue(ctx, "123", stranger, false), ErrNotAdmin)
+	assert.ErrorIs(t, u.SetNextPersonToQueue(ctx, "123", stranger), ErrNotAdmin)
+	assert.ErrorIs(t, u.FinishQueue(ctx, "123", stranger), ErrNotAdmin)
+	assert.ErrorIs(t, u.ToggleAdmin(ctx, "123", coAdmin, coAdmin), ErrNotOwner)
+
+	require.NoError(t, u.ToggleAdmin(ctx, "123", owner, coAdmin))
+	assert.NoError(t, u.StartQueue(ctx, "123", coAdmin, false))
+	assert.NoError(t, u.SetNextPersonToQueue(ctx, "123", coAdmin))
+	assert.ErrorIs(t, u.CheckOwner(ctx, "123", coAdmin), ErrNotOwner)
+
+	require.NoError(t, u.ToggleAdmin(ctx, "123", owner, coAdmin))
+	assert.ErrorIs(t, u.CheckAdmin(ctx, "123", coAdmin), ErrNotAdmin)
+
+	// Owner can't take away own rights.
+	require.NoError(t, u.ToggleAdmin(ctx, "123", owner, owner))
+	assert.NoError(t, u.FinishQueue(ctx, "123", owner))
+}
+
+func TestBotUseCase_QueueWithoutOwner(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	// Queues created before owners were stored stay open to everyone.
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", 0, "ru", 0))
+	assert.NoError(t, u.StartQueue(ctx, "123", 5, false))
+	assert.NoError(t, u.CheckOwner(ctx, "123", 5))
+}
+
+func TestBotUseCase_SkipCurrentPerson(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	users := []entity.User{{ID: 1, Name: "First"}, {ID: 2, Name: "Second"}, {ID: 3, Name: "Third"}, {ID: 4, Name: "Fourth"}}
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", 1, "ru", 0))
+
+	for _, user := range users {
+		require.NoError(t, u.LogInOutToQueue(ctx, "123", user))
+	}
+
+	require.NoError(t, u.StartQueue(ctx, "123", 1, false))
+	require.NoError(t, u.SetNextPersonToQueue(ctx, "123", 1))
+
+	// Current person can postpone own turn.
+	require.NoError(t, u.SkipCurrentPerson(ctx, "123", 2, 1))
+
+	queue, err := u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, []entity.User{users[0], users[2], users[1], users[3]}, queue.Users)
+	assert.Equal(t, 1, queue.CurrentPersonIdx)
+
+	require.NoError(t, u.SkipCurrentPerson(ctx, "123", 1, SkipToEnd))
+
+	queue, err = u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, []entity.User{users[0], users[1], users[3], users[2]}, queue.Users)
+
+	assert.ErrorIs(t, u.SkipCurrentPerson(ctx, "123", 4, 1), ErrNotAdmin)
+	assert.ErrorIs(t, u.SkipCurrentPerson(ctx, "123", 1, -1), ErrInvalidPositions)
+
+	require.NoError(t, u.SetNextPersonToQueue(ctx, "123", 1))
+	require.NoError(t, u.SetNextPersonToQueue(ctx, "123", 1))
+	require.NoError(t, u.SetNextPersonToQueue(ctx, "123", 1))
+	assert.ErrorIs(t, u.SkipCurrentPerson(ctx, "123", 1, 1), ErrNoCurrentPerson)
+}
+
+func TestBotUseCase_PreviousAndUndo(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	first := entity.User{ID: 1, Name: "First"}
+	second := entity.User{ID: 2, Name: "Second"}
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", first.ID, "ru", 0))
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", first))
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", second))
+	require.NoError(t, u.StartQueue(ctx, "123", first.ID, false))
+	require.NoError(t, u.SetNextPersonToQueue(ctx, "123", first.ID))
+	require.NoError(t, u.SetNextPersonToQueue(ctx, "123", first.ID))
+
+	require.NoError(t, u.SetPreviousPersonToQueue(ctx, "123", first.ID))
+	queue, err := u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, 1, queue.CurrentPersonIdx)
+
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", second))
+
+	assert.ErrorIs(t, u.SetPreviousPersonToQueue(ctx, "123", second.ID), ErrNotAdmin)
+	_, err = u.Undo(ctx, "123", second.ID)
+	assert.ErrorIs(t, err, ErrNotAdmin)
+
+	for _, want := range []entity.Operation{
+		entity.OperationLeave,
+		entity.OperationPrevious,
+		entity.OperationNext,
+		entity.OperationNext,
+		entity.OperationStart,
+	} {
+		operation, err := u.Undo(ctx, "123", first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, want, operation)
+	}
+
+	queue, err = u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.False(t, queue.IsStarted)
+	assert.Equal(t, []entity.User{first, second}, queue.Users)
+
+	require.NoError(t, u.StopQueue(ctx, "123", first.ID))
+	operation, err := u.Undo(ctx, "123", first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.OperationStop, operation)
+
+	for i := 0; i < 2; i++ {
+		_, err = u.Undo(ctx, "123", first.ID)
+		require.NoError(t, err)
+	}
+
+	_, err = u.Undo(ctx, "123", first.ID)
+	assert.ErrorIs(t, err, ErrNothingToUndo)
+}
+
+func TestBotUseCase_Events(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	owner := entity.User{ID: 1, Name: "Owner"}
+	participant := entity.User{ID: 2, Name: "Participant"}
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", owner.ID, "ru", 0))
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", participant))
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", owner))
+	assert.ErrorIs(t, u.StartQueue(ctx, "123", participant.ID, true), ErrNotAdmin)
+	require.NoError(t, u.StartQueue(ctx, "123", owner.ID, true))
+	require.NoError(t, u.SetNextPersonToQueue(ctx, "123", owner.ID))
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", participant))
+	require.NoError(t, u.FinishQueue(ctx, "123", owner.ID))
+
+	events, err := u.GetEvents(ctx, "123", 0)
+	require.NoError(t, err)
+
+	type event struct {
+		operation entity.Operation
+		userID    int64
+	}
+
+	got := make([]event, 0, len(events))
+	for _, e := range events {
+		got = append(got, event{operation: e.Operation, userID: e.UserID})
+	}
+
+	assert.Equal(t, []event{
+		{operation: entity.OperationFinish, userID: owner.ID},
+		{operation: entity.OperationLeave, userID: participant.ID},
+		{operation: entity.OperationNext, userID: owner.ID},
+		{operation: entity.OperationShuffle, userID: owner.ID},
+		{operation: entity.OperationJoin, userID: owner.ID},
+		{operation: entity.OperationJoin, userID: participant.ID},
+		{operation: entity.OperationCreate, userID: owner.ID},
+	}, got)
+
+	events, err = u.GetEvents(ctx, "123", 2)
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+}
+
+type turnNotification struct {
+	userID       int64
+	peopleBefore int
+}
+
+// fakeNotifier remembers notifications and fails for unreachable users.
+type fakeNotifier struct {
+	notifications []turnNotification
+	// endings are users warned that their turn is ending.
+	endings []int64
+	// askedCheckIns are users asked to confirm their presence.
+	askedCheckIns []int64
+	unreachable   map[int64]bool
+}
+
+func (n *fakeNotifier) NotifyTurn(_ context.Context, userID int64, _ string, _ string, peopleBefore int) error {
+	if n.unreachable[userID] {
+		return ErrUserUnreachable
+	}
+
+	n.notifications = append(n.notifications, turnNotification{userID: userID, peopleBefore: peopleBefore})
+
+	return nil
+}
+
+func (n *fakeNotifier) NotifyTurnEnding(_ context.Context, userID int64, _ string, _ string, _ time.Duration) error {
+	if n.unreachable[userID] {
+		return ErrUserUnreachable
+	}
+
+	n.endings = append(n.endings, userID)
+
+	return nil
+}
+
+func (n *fakeNotifier) AskCheckIn(_ context.Context, userID int64, _ string, _ string, _ string, _ time.Duration) error {
+	if n.unreachable[userID] {
+		return ErrUserUnreachable
+	}
+
+	n.askedCheckIns = append(n.askedCheckIns, userID)
+
+	return nil
+}
+
+func TestBotUseCase_NotifyTurn(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStorage()
+	notifier := &fakeNotifier{unreachable: map[int64]bool{4: true}}
+	u := NewBotUseCase(s, notifier)
+
+	const owner = int64(1)
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", owner, "ru", 0))
+
+	for id := int64(1); id <= 5; id++ {
+		require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: id}))
+
+		if id != 3 {
+			require.NoError(t, u.Subscribe(ctx, id))
+		}
+	}
+
+	assert.ErrorIs(t, u.SwitchNotifyCount(ctx, "123", 2), ErrNotAdmin)
+	require.NoError(t, u.SwitchNotifyCount(ctx, "123", owner))
+	require.NoError(t, u.SwitchNotifyCount(ctx, "123", owner))
+
+	queue, err := u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, 3, queue.NotifyCount)
+
+	require.NoError(t, u.StartQueue(ctx, "123", owner, false))
+	require.NoError(t, u.SetNextPersonToQueue(ctx, "123", owner))
+
+	// User 3 hasn't started the bot and user 4 has blocked it.
+	assert.Equal(t, []turnNotification{
+		{userID: 2, peopleBefore: 0},
+		{userID: 5, peopleBefore: 3},
+	}, notifier.notifications)
+
+	isSubscriber, err := s.IsSubscriber(ctx, 4)
+	require.NoError(t, err)
+	assert.False(t, isSubscriber)
+}
+
+func TestBotUseCase_RemoveParticipant(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	first := entity.User{ID: 1, Name: "First"}
+	second := entity.User{ID: 2, Name: "Second"}
+	third := entity.User{ID: 3, Name: "Third"}
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", first.ID, "ru", 0))
+	for _, user := range []entity.User{first, second, third} {
+		require.NoError(t, u.LogInOutToQueue(ctx, "123", user))
+	}
+
+	require.NoError(t, u.StartQueue(ctx, "123", first.ID, false))
+	require.NoError(t, u.SetNextPersonToQueue(ctx, "123", first.ID))
+
+	assert.ErrorIs(t, u.RemoveParticipant(ctx, "123", second.ID, third.ID), ErrNotAdmin)
+	assert.ErrorIs(t, u.RemoveParticipant(ctx, "123", first.ID, 4), ErrNotParticipant)
+
+	// Second stays current after the person before them is removed.
+	require.NoError(t, u.RemoveParticipant(ctx, "123", first.ID, first.ID))
+
+	queue, err := u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, []entity.User{second, third}, queue.Users)
+	assert.Equal(t, 0, queue.CurrentPersonIdx)
+
+	operation, err := u.Undo(ctx, "123", first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.OperationRemove, operation)
+
+	queue, err = u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, []entity.User{first, second, third}, queue.Users)
+	assert.Equal(t, 1, queue.CurrentPersonIdx)
+}
+
+func TestBotUseCase_Waitlist(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	first := entity.User{ID: 1, Name: "First"}
+	second := entity.User{ID: 2, Name: "Second"}
+	third := entity.User{ID: 3, Name: "Third"}
+	fourth := entity.User{ID: 4, Name: "Fourth"}
+
+	assert.ErrorIs(t, u.CreateQueue(ctx, "123", "Test", first.ID, "ru", -1), ErrInvalidCapacity)
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", first.ID, "ru", 2))
+	for _, user := range []entity.User{first, second, third, fourth} {
+		require.NoError(t, u.LogInOutToQueue(ctx, "123", user))
+	}
+
+	queue, err := u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, []entity.User{first, second}, queue.Users)
+	assert.Equal(t, []entity.User{third, fourth}, queue.Waitlist)
+
+	// Leaving the waitlist doesn't promote anybody.
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", fourth))
+
+	queue, err = u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, []entity.User{first, second}, queue.Users)
+	assert.Equal(t, []entity.User{third}, queue.Waitlist)
+
+	// The first waitlisted person takes the place of the one who left.
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", first))
+
+	queue, err = u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, []entity.User{second, third}, queue.Users)
+	assert.Empty(t, queue.Waitlist)
+
+	events, err := u.GetEvents(ctx, "123", 3)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, entity.OperationPromote, events[0].Operation)
+	assert.Equal(t, third.ID, events[0].UserID)
+	assert.Equal(t, entity.OperationLeave, events[1].Operation)
+
+	// Undo brings back both the person who left and the waitlist.
+	operation, err := u.Undo(ctx, "123", first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.OperationLeave, operation)
+
+	queue, err = u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, []entity.User{first, second}, queue.Users)
+	assert.Equal(t, []entity.User{third}, queue.Waitlist)
+
+	// Removing a participant promotes too.
+	require.NoError(t, u.RemoveParticipant(ctx, "123", first.ID, second.ID))
+
+	queue, err = u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, []entity.User{first, third}, queue.Users)
+	assert.Empty(t, queue.Waitlist)
+}
+
+func TestBotUseCase_GetAdminQueues(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	require.NoError(t, u.CreateQueue(ctx, "1", "Owned", 1, "ru", 0))
+	require.NoError(t, u.CreateQueue(ctx, "2", "Administered", 2, "ru", 0))
+	require.NoError(t, u.ToggleAdmin(ctx, "2", 2, 1))
+	require.NoError(t, u.CreateQueue(ctx, "3", "Other", 2, "ru", 0))
+
+	queues, err := u.GetAdminQueues(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, queues, 2)
+	assert.Equal(t, "Owned", queues[0].Description)
+	assert.Equal(t, "Administered", queues[1].Description)
+}
+
+type observerFunc func(messageID string)
+
+func (f observerFunc) QueueChanged(messageID string) {
+	f(messageID)
+}
+
+func TestBotUseCase_Observer(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	var changed []string
+
+	u.Observer = observerFunc(func(messageID string) {
+		changed = append(changed, messageID)
+	})
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", 1, "ru", 0))
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: 1, Name: "First"}))
+	require.NoError(t, u.StartQueue(ctx, "123", 1, false))
+	require.NoError(t, u.SetNextPersonToQueue(ctx, "123", 1))
+
+	// Rejected changes aren't observed.
+	require.ErrorIs(t, u.SetNextPersonToQueue(ctx, "123", 2), ErrNotAdmin)
+
+	assert.Equal(t, []string{"123", "123", "123", "123"}, changed)
+
+	// Settings aren't recorded as events, but are observed too.
+	changed = nil
+
+	require.NoError(t, u.SwitchNotifyCount(ctx, "123", 1))
+	require.NoError(t, u.SwitchLanguage(ctx, "123", 1))
+	require.NoError(t, u.SwitchTurnDuration(ctx, "123", 1))
+	require.NoError(t, u.SwitchCheckInTimeout(ctx, "123", 1))
+	require.NoError(t, u.SwitchSlotCount(ctx, "123", 1))
+	require.ErrorIs(t, u.SwitchLanguage(ctx, "123", 2), ErrNotAdmin)
+
+	assert.Equal(t, []string{"123", "123", "123", "123", "123"}, changed)
+}
+
+func TestBotUseCase_SwitchLanguage(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", 1, "en", 0))
+
+	assert.ErrorIs(t, u.SwitchLanguage(ctx, "123", 2), ErrNotAdmin)
+
+	var languages []string
+
+	for range i18n.Languages {
+		require.NoError(t, u.SwitchLanguage(ctx, "123", 1))
+
+		queue, err := u.GetQueue(ctx, "123")
+		require.NoError(t, err)
+
+		languages = append(languages, queue.Language)
+	}
+
+	// Languages are switched in a circle.
+	assert.Equal(t, []string{"ru", "en"}, languages)
+}
@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"QueueBot/internal/entity"
+)
+
+// CheckInTimeouts are values of Queue.CheckInTimeout admins switch between, in order. Zero turns check-in off.
+var CheckInTimeouts = []time.Duration{0, 2 * time.Minute, 5 * time.Minute, 10 * time.Minute}
+
+// SwitchCheckInTimeout sets CheckInTimeout of the queue to the value following the current one in CheckInTimeouts.
+func (b BotUseCase) SwitchCheckInTimeout(ctx context.Context, messageID string, userID int64) error {
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	if !queue.IsAdmin(userID) {
+		return fmt.Errorf("user %d in queue %s: %w", userID, messageID, ErrNotAdmin)
+	}
+
+	next := CheckInTimeouts[(slices.Index(CheckInTimeouts, queue.CheckInTimeout)+1)%len(CheckInTimeouts)]
+
+	if err = b.Storage.SetCheckInTimeout(ctx, messageID, next); err != nil {
+		return fmt.Errorf("couldn't set check-in timeout in storage with error: %w", err)
+	}
+
+	b.queueChanged(messageID)
+
+	return nil
+}
+
+// GetCheckInQueueIDs returns message IDs of started queues where participants confirm their presence.
+func (b BotUseCase) GetCheckInQueueIDs(ctx context.Context) ([]string, error) {
+	messageIDs, err := b.Storage.GetCheckInQueueIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get check-in queues from storage with error: %w", err)
+	}
+
+	return messageIDs, nil
+}
+
+// IsCheckInDue reports whether somebody close to their turn has to be asked to confirm their presence
+// or has missed the timeout, then the queue is updated with UpdateCheckIns.
+func (b BotUseCase) IsCheckInDue(ctx context.Context, messageID string, now time.Time) (bool, error) {
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, user := range checkInCandidates(queue) {
+		checkIn := queue.CheckIns[user.ID]
+		if checkIn.AskedAt.IsZero() || isCheckInMissed(queue, checkIn, now) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// UpdateCheckIns moves to the end of the queue those who missed the timeout for the first time and removes those
+// who missed it again, then asks the new people close to their turn to confirm their presence.
+func (b BotUseCase) UpdateCheckIns(ctx context.Context, messageID string, now time.Time) error {
+	// Every move changes who is close to the turn, so the queue is read again after it.
+	for {
+		queue, err := b.GetQueue(ctx, messageID)
+		if err != nil {
+			return err
+		}
+
+		candidates := checkInCandidates(queue)
+
+		idx := slices.IndexFunc(candidates, func(user entity.User) bool {
+			return isCheckInMissed(queue, queue.CheckIns[user.ID], now)
+		})
+		if idx < 0 {
+			return b.askCheckIns(ctx, queue, candidates, now)
+		}
+
+		if err = b.missCheckIn(ctx, queue, candidates[idx].ID); err != nil {
+			return err
+		}
+	}
+}
+
+// ConfirmCheckIn records that the participant who was asked to confirm their presence is here.
+func (b BotUseCase) ConfirmCheckIn(ctx context.Context, messageID string, userID int64) error {
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	if !queue.HasUser(userID) {
+		return fmt.Errorf("user %d in queue %s: %w", userID, messageID, ErrNotParticipant)
+	}
+
+	checkIn := queue.CheckIns[userID]
+	if checkIn.AskedAt.IsZero() {
+		return fmt.Errorf("user %d in queue %s: %w", userID, messageID, ErrCheckInNotAsked)
+	}
+
+	if checkIn.IsConfirmed {
+		return nil
+	}
+
+	checkIn.IsConfirmed = true
+	if err = b.setCheckIn(ctx, messageID, userID, checkIn); err != nil {
+		return err
+	}
+
+	return b.addEvent(ctx, messageID, entity.OperationCheckIn, userID)
+}
+
+// checkInCandidates returns people who are asked to confirm their presence: the one whose turn comes next
+// and NotifyCount people after them.
+func checkInCandidates(queue entity.Queue) []entity.User {
+	if !queue.IsStarted || queue.CheckInTimeout <= 0 || queue.CurrentPersonIdx >= len(queue.Users) {
+		return nil
+	}
+
+	return queue.Users[queue.CurrentPersonIdx:min(queue.CurrentPersonIdx+queue.NotifyCount+1, len(queue.Users))]
+}
+
+func isCheckInMissed(queue entity.Queue, checkIn entity.CheckIn, now time.Time) bool {
+	return !checkIn.AskedAt.IsZero() && !checkIn.IsConfirmed && !now.Before(checkIn.AskedAt.Add(queue.CheckInTimeout))
+}
+
+// missCheckIn moves the person to the end of the queue, or removes them if they have already been moved once.
+// The person is recorded as the one who made the change, so the event tells why they were moved.
+func (b BotUseCase) missCheckIn(ctx context.Context, queue entity.Queue, userID int64) error {
+	checkIn := queue.CheckIns[userID]
+	if checkIn.Missed > 0 {
+		return b.removeAbsent(ctx, queue, userID)
+	}
+
+	if err := b.saveSnapshot(ctx, queue.MessageID, entity.OperationCheckInMoved); err != nil {
+		return err
+	}
+
+	if err := b.Storage.MoveParticipant(ctx, queue.MessageID, userID, len(queue.Users)); err != nil {
+		return fmt.Errorf("couldn't move absent person in storage with error: %w", err)
+	}
+
+	// They are asked again when their turn comes close once more.
+	if err := b.setCheckIn(ctx, queue.MessageID, userID, entity.CheckIn{Missed: checkIn.Missed + 1}); err != nil {
+		return err
+	}
+
+	return b.addEvent(ctx, queue.MessageID, entity.OperationCheckInMoved, userID)
+}
+
+func (b BotUseCase) removeAbsent(ctx context.Context, queue entity.Queue, userID int64) error {
+	idx := slices.IndexFunc(queue.Users, func(user entity.User) bool {
+		return user.ID == userID
+	})
+
+	if err := b.saveSnapshot(ctx, queue.MessageID, entity.OperationCheckInRemoved); err != nil {
+		return err
+	}
+
+	if err := b.Storage.LogInOutToQueue(ctx, queue.MessageID, queue.Users[idx]); err != nil {
+		return fmt.Errorf("couldn't remove absent person in storage with error: %w", err)
+	}
+
+	if err := b.addEvent(ctx, queue.MessageID, entity.OperationCheckInRemoved, userID); err != nil {
+		return err
+	}
+
+	return b.promoteFromWaitlist(ctx, queue)
+}
+
+// askCheckIns starts the timeout of candidates who weren't asked yet and sends them a private message.
+// People who haven't started the bot confirm with the button of the queue.
+func (b BotUseCase) askCheckIns(ctx context.Context, queue entity.Queue, candidates []entity.User, now time.Time) error {
+	for _, user := range candidates {
+		checkIn := queue.CheckIns[user.ID]
+		if !checkIn.AskedAt.IsZero() || checkIn.IsConfirmed {
+			continue
+		}
+
+		checkIn.AskedAt = now
+		if err := b.setCheckIn(ctx, queue.MessageID, user.ID, checkIn); err != nil {
+			return err
+		}
+
+		if b.Notifier != nil {
+			b.notifyUser(ctx, queue, user.ID, func() error {
+				return b.Notifier.AskCheckIn(ctx, user.ID, queue.MessageID, queue.Description, queue.Language, queue.CheckInTimeout)
+			})
+		}
+	}
+
+	return nil
+}
+
+// resetCheckIns forgets who was asked and who missed the timeout, so a restarted queue asks everyone from scratch.
+func (b BotUseCase) resetCheckIns(ctx context.Context, queue entity.Queue) error {
+	for userID := range queue.CheckIns {
+		if err := b.setCheckIn(ctx, queue.MessageID, userID, entity.CheckIn{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b BotUseCase) setCheckIn(ctx context.Context, messageID string, userID int64, checkIn entity.CheckIn) error {
+	if err := b.Storage.SetCheckIn(ctx, messageID, userID, checkIn); err != nil {
+		return fmt.Errorf("couldn't set check-in in storage with error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase/storage/memory"
+)
+
+func TestBotUseCase_SwitchCheckInTimeout(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	const owner = int64(1)
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", owner, "ru", 0))
+	assert.ErrorIs(t, u.SwitchCheckInTimeout(ctx, "123", 2), ErrNotAdmin)
+
+	// The last timeout is followed by the first one.
+	for i := 1; i <= len(CheckInTimeouts); i++ {
+		require.NoError(t, u.SwitchCheckInTimeout(ctx, "123", owner))
+
+		queue, err := u.GetQueue(ctx, "123")
+		require.NoError(t, err)
+		assert.Equal(t, CheckInTimeouts[i%len(CheckInTimeouts)], queue.CheckInTimeout)
+	}
+}
+
+func TestBotUseCase_CheckIn(t *testing.T) {
+	ctx := context.Background()
+	s := memory.NewStorage()
+	notifier := &fakeNotifier{}
+	u := NewBotUseCase(s, notifier)
+
+	const owner = int64(1)
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", owner, "ru", 0))
+
+	for id := int64(1); id <= 4; id++ {
+		require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: id}))
+		require.NoError(t, u.Subscribe(ctx, id))
+	}
+
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: 5}))
+	require.NoError(t, u.SwitchCheckInTimeout(ctx, "123", owner))
+	require.NoError(t, u.StartQueue(ctx, "123", owner, false))
+
+	start := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	timeout := CheckInTimeouts[1]
+
+	isDue, err := u.IsCheckInDue(ctx, "123", start)
+	require.NoError(t, err)
+	assert.True(t, isDue)
+
+	// The current person and NotifyCount people after them are asked.
+	require.NoError(t, u.UpdateCheckIns(ctx, "123", start))
+	assert.Equal(t, []int64{1, 2}, notifier.askedCheckIns)
+
+	assert.ErrorIs(t, u.ConfirmCheckIn(ctx, "123", 3), ErrCheckInNotAsked)
+	assert.ErrorIs(t, u.ConfirmCheckIn(ctx, "123", 6), ErrNotParticipant)
+	require.NoError(t, u.ConfirmCheckIn(ctx, "123", 1))
+	require.NoError(t, u.ConfirmCheckIn(ctx, "123", 1))
+
+	isDue, err = u.IsCheckInDue(ctx, "123", start.Add(timeout-time.Second))
+	require.NoError(t, err)
+	assert.False(t, isDue)
+
+	// The one who missed the timeout for the first time goes to the end and the next person is asked instead.
+	moved := start.Add(timeout)
+	require.NoError(t, u.UpdateCheckIns(ctx, "123", moved))
+	assert.Equal(t, []int64{1, 2, 3}, notifier.askedCheckIns)
+
+	queue, err := u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, []entity.User{{ID: 1}, {ID: 3}, {ID: 4}, {ID: 5}, {ID: 2}}, queue.Users)
+	assert.Equal(t, map[int64]entity.CheckIn{
+		1: {AskedAt: start, IsConfirmed: true},
+		2: {Missed: 1},
+		3: {AskedAt: moved},
+	}, queue.CheckIns)
+
+	require.NoError(t, u.ConfirmCheckIn(ctx, "123", 3))
+	require.NoError(t, u.SetNextPersonToQueue(ctx, "123", owner))
+	require.NoError(t, u.SetNextPersonToQueue(ctx, "123", owner))
+	require.NoError(t, u.SetNextPersonToQueue(ctx, "123", owner))
+
+	// People who haven't started the bot are asked in the queue message only.
+	asked := moved.Add(time.Minute)
+	require.NoError(t, u.UpdateCheckIns(ctx, "123", asked))
+	assert.Equal(t, []int64{1, 2, 3, 2}, notifier.askedCheckIns)
+
+	// The first miss moves the person to the end, the one who missed the timeout again is removed.
+	require.NoError(t, u.UpdateCheckIns(ctx, "123", asked.Add(timeout)))
+
+	queue, err = u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, []entity.User{{ID: 1}, {ID: 3}, {ID: 4}, {ID: 5}}, queue.Users)
+
+	events, err := u.GetEvents(ctx, "123", 2)
+	require.NoError(t, err)
+	assert.Equal(t, entity.OperationCheckInRemoved, events[0].Operation)
+	assert.Equal(t, int64(2), events[0].UserID)
+	assert.Equal(t, entity.OperationCheckInMoved, events[1].Operation)
+	assert.Equal(t, int64(5), events[1].UserID)
+
+	// A removal can be undone like any other change.
+	_, err = u.Undo(ctx, "123", owner)
+	require.NoError(t, err)
+
+	queue, err = u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, []entity.User{{ID: 1}, {ID: 3}, {ID: 4}, {ID: 2}, {ID: 5}}, queue.Users)
+}
+
+func TestBotUseCase_StartQueueResetsCheckIns(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	const owner = int64(1)
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", owner, "ru", 0))
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: 1}))
+	require.NoError(t, u.SwitchCheckInTimeout(ctx, "123", owner))
+	require.NoError(t, u.StartQueue(ctx, "123", owner, false))
+
+	start := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, u.UpdateCheckIns(ctx, "123", start))
+	require.NoError(t, u.StopQueue(ctx, "123", owner))
+	require.NoError(t, u.StartQueue(ctx, "123", owner, false))
+
+	queue, err := u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Empty(t, queue.CheckIns)
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase/storage"
+)
+
+// ScheduleQueue sets when people can join the queue and when it starts by itself.
+func (b BotUseCase) ScheduleQueue(ctx context.Context, messageID string, userID int64, schedule entity.Schedule) error {
+	if err := b.CheckAdmin(ctx, messageID, userID); err != nil {
+		return err
+	}
+
+	if !schedule.OpensAt.IsZero() && !schedule.StartsAt.IsZero() && !schedule.OpensAt.Before(schedule.StartsAt) {
+		return fmt.Errorf("opens at %s, starts at %s: %w", schedule.OpensAt, schedule.StartsAt, ErrInvalidSchedule)
+	}
+
+	if err := b.Storage.SetSchedule(ctx, messageID, schedule); err != nil {
+		return fmt.Errorf("couldn't set schedule in storage with error: %w", err)
+	}
+
+	b.queueChanged(messageID)
+
+	return nil
+}
+
+// GetDueQueueIDs returns message IDs of not started queues which have to start by now.
+func (b BotUseCase) GetDueQueueIDs(ctx context.Context, now time.Time) ([]string, error) {
+	messageIDs, err := b.Storage.GetScheduledQueueIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get scheduled queues from storage with error: %w", err)
+	}
+
+	var due []string
+
+	for _, messageID := range messageIDs {
+		queue, err := b.GetQueue(ctx, messageID)
+		if errors.Is(err, storage.ErrQueueNotFound) {
+			// The queue was finished in the meantime.
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !queue.Schedule.StartsAt.After(now) {
+			due = append(due, messageID)
+		}
+	}
+
+	return due, nil
+}
+
+// StartScheduledQueue starts the queue if its start is still due.
+// The owner is recorded as the one who started the queue.
+func (b BotUseCase) StartScheduledQueue(ctx context.Context, messageID string, now time.Time) error {
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	// The queue could be started by an admin since GetDueQueueIDs.
+	if queue.IsStarted || queue.Schedule.StartsAt.IsZero() || queue.Schedule.StartsAt.After(now) {
+		return nil
+	}
+
+	return b.StartQueue(ctx, messageID, queue.OwnerID, queue.Schedule.IsShuffle)
+}
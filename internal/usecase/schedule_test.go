@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase/storage/memory"
+)
+
+func TestBotUseCase_ScheduleQueue(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	const owner = int64(1)
+
+	opensAt := time.Date(2024, time.March, 1, 8, 0, 0, 0, time.UTC)
+	startsAt := opensAt.Add(time.Hour)
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", owner, "ru", 0))
+
+	assert.ErrorIs(t, u.ScheduleQueue(ctx, "123", 2, entity.Schedule{OpensAt: opensAt}), ErrNotAdmin)
+	assert.ErrorIs(t, u.ScheduleQueue(ctx, "123", owner, entity.Schedule{OpensAt: startsAt, StartsAt: opensAt}), ErrInvalidSchedule)
+	assert.ErrorIs(t, u.ScheduleQueue(ctx, "123", owner, entity.Schedule{OpensAt: opensAt, StartsAt: opensAt}), ErrInvalidSchedule)
+
+	schedule := entity.Schedule{OpensAt: opensAt, StartsAt: startsAt, IsShuffle: true}
+	require.NoError(t, u.ScheduleQueue(ctx, "123", owner, schedule))
+
+	queue, err := u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, schedule, queue.Schedule)
+}
+
+func TestBotUseCase_JoinWindow(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	const owner = int64(1)
+
+	opensAt := time.Date(2024, time.March, 1, 8, 0, 0, 0, time.UTC)
+	now := opensAt.Add(-time.Minute)
+	u.Now = func() time.Time { return now }
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", owner, "ru", 0))
+	require.NoError(t, u.ScheduleQueue(ctx, "123", owner, entity.Schedule{OpensAt: opensAt}))
+
+	assert.ErrorIs(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: 1}), ErrJoinNotOpen)
+
+	now = opensAt
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: 1}))
+
+	// Leaving is allowed whenever.
+	now = opensAt.Add(-time.Hour)
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: 1}))
+
+	queue, err := u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Empty(t, queue.Users)
+}
+
+func TestBotUseCase_JoinAfterScheduledStart(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	const owner = int64(1)
+
+	startsAt := time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)
+	now := startsAt.Add(-time.Minute)
+	u.Now = func() time.Time { return now }
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", owner, "ru", 0))
+	require.NoError(t, u.ScheduleQueue(ctx, "123", owner, entity.Schedule{StartsAt: startsAt}))
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: 1}))
+
+	// The queue isn't started by the scheduler yet, but it's too late to join.
+	now = startsAt
+	assert.ErrorIs(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: 2}), ErrJoinClosed)
+
+	// Leaving is allowed whenever.
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: 1}))
+	require.NoError(t, u.StartQueue(ctx, "123", owner, false))
+
+	// People join the started queue as usual.
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: 2}))
+
+	queue, err := u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, []entity.User{{ID: 2}}, queue.Users)
+}
+
+func TestBotUseCase_StartScheduledQueue(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	const owner = int64(1)
+
+	startsAt := time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)
+
+	for _, messageID := range []string{"scheduled", "manual"} {
+		require.NoError(t, u.CreateQueue(ctx, messageID, "Test", owner, "ru", 0))
+		require.NoError(t, u.LogInOutToQueue(ctx, messageID, entity.User{ID: 1}))
+		require.NoError(t, u.ScheduleQueue(ctx, messageID, owner, entity.Schedule{StartsAt: startsAt, IsShuffle: true}))
+	}
+
+	due, err := u.GetDueQueueIDs(ctx, startsAt.Add(-time.Second))
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	// The queue started by an admin doesn't start again after it's stopped.
+	require.NoError(t, u.StartQueue(ctx, "manual", owner, false))
+	require.NoError(t, u.StopQueue(ctx, "manual", owner))
+
+	due, err = u.GetDueQueueIDs(ctx, startsAt)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"scheduled"}, due)
+
+	require.NoError(t, u.StartScheduledQueue(ctx, "scheduled", startsAt.Add(-time.Second)))
+
+	queue, err := u.GetQueue(ctx, "scheduled")
+	require.NoError(t, err)
+	assert.False(t, queue.IsStarted, "queue started before its time")
+
+	require.NoError(t, u.StartScheduledQueue(ctx, "scheduled", startsAt))
+
+	queue, err = u.GetQueue(ctx, "scheduled")
+	require.NoError(t, err)
+	assert.True(t, queue.IsStarted)
+	assert.True(t, queue.Schedule.StartsAt.IsZero())
+
+	events, err := u.GetEvents(ctx, "scheduled", 1)
+	require.NoError(t, err)
+	assert.Equal(t, entity.OperationShuffle, events[0].Operation)
+	assert.Equal(t, owner, events[0].UserID)
+}
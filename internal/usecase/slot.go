@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"QueueBot/internal/entity"
+)
+
+// SlotCounts are values of Queue.SlotCount admins switch between, in order.
+var SlotCounts = []int{1, 2, 3, 4, 5}
+
+// SwitchSlotCount sets SlotCount of the queue to the value following the current one in SlotCounts.
+// Slots are filled when the queue starts.
+func (b BotUseCase) SwitchSlotCount(ctx context.Context, messageID string, userID int64) error {
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	if !queue.IsAdmin(userID) {
+		return fmt.Errorf("user %d in queue %s: %w", userID, messageID, ErrNotAdmin)
+	}
+
+	next := SlotCounts[(slices.Index(SlotCounts, queue.SlotCount)+1)%len(SlotCounts)]
+
+	if err = b.Storage.SetSlotCount(ctx, messageID, next); err != nil {
+		return fmt.Errorf("couldn't set slot count in storage with error: %w", err)
+	}
+
+	b.queueChanged(messageID)
+
+	return nil
+}
+
+// FinishSlot frees the slot, numbered from 1, and calls the first waiting person to it.
+func (b BotUseCase) FinishSlot(ctx context.Context, messageID string, userID int64, slot int) error {
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	if !queue.IsAdmin(userID) {
+		return fmt.Errorf("user %d in queue %s: %w", userID, messageID, ErrNotAdmin)
+	}
+
+	if !queue.HasSlots() || slot < 1 || slot > queue.SlotCount {
+		return fmt.Errorf("slot %d in queue %s: %w", slot, messageID, ErrInvalidSlot)
+	}
+
+	isCalled := queue.CurrentPersonIdx < len(queue.Users)
+	if !isCalled && queue.Slots[slot-1].ID == 0 {
+		return fmt.Errorf("queue %s: %w", messageID, ErrNoCurrentPerson)
+	}
+
+	if err = b.saveSnapshot(ctx, messageID, entity.OperationSlotDone); err != nil {
+		return err
+	}
+
+	if isCalled {
+		err = b.Storage.CallToSlot(ctx, messageID, slot, queue.Users[queue.CurrentPersonIdx].ID)
+	} else {
+		err = b.Storage.FreeSlot(ctx, messageID, slot)
+	}
+
+	if err != nil {
+		return fmt.Errorf("couldn't finish slot in storage with error: %w", err)
+	}
+
+	if err = b.addEvent(ctx, messageID, entity.OperationSlotDone, userID); err != nil {
+		return err
+	}
+
+	if isCalled {
+		b.notifyTurn(ctx, messageID)
+	}
+
+	return nil
+}
+
+// fillSlots calls the first people of the just started queue to all slots, the rest of slots are freed.
+func (b BotUseCase) fillSlots(ctx context.Context, messageID string) error {
+	// People are numbered by StartQueue, so their order is read again.
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	for slot := 1; slot <= queue.SlotCount; slot++ {
+		if slot <= len(queue.Users) {
+			err = b.Storage.CallToSlot(ctx, messageID, slot, queue.Users[slot-1].ID)
+		} else {
+			err = b.Storage.FreeSlot(ctx, messageID, slot)
+		}
+
+		if err != nil {
+			return fmt.Errorf("couldn't fill slots in storage with error: %w", err)
+		}
+	}
+
+	return nil
+}
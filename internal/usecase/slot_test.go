@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase/storage/memory"
+)
+
+func TestBotUseCase_SwitchSlotCount(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	const owner = int64(1)
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", owner, "ru", 0))
+	assert.ErrorIs(t, u.SwitchSlotCount(ctx, "123", 2), ErrNotAdmin)
+
+	// The last count is followed by the first one.
+	for i := 1; i <= len(SlotCounts); i++ {
+		require.NoError(t, u.SwitchSlotCount(ctx, "123", owner))
+
+		queue, err := u.GetQueue(ctx, "123")
+		require.NoError(t, err)
+		assert.Equal(t, SlotCounts[i%len(SlotCounts)], queue.SlotCount)
+	}
+}
+
+func TestBotUseCase_FinishSlot(t *testing.T) {
+	ctx := context.Background()
+	notifier := &fakeNotifier{}
+	u := NewBotUseCase(memory.NewStorage(), notifier)
+
+	const owner = int64(1)
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", owner, "ru", 0))
+
+	for id := int64(1); id <= 4; id++ {
+		require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: id}))
+		require.NoError(t, u.Subscribe(ctx, id))
+	}
+
+	// Three slots for four people.
+	require.NoError(t, u.SwitchSlotCount(ctx, "123", owner))
+	require.NoError(t, u.SwitchSlotCount(ctx, "123", owner))
+	require.NoError(t, u.StartQueue(ctx, "123", owner, false))
+
+	queue, err := u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, []entity.User{{ID: 1}, {ID: 2}, {ID: 3}}, queue.Slots)
+	assert.Equal(t, 3, queue.CurrentPersonIdx)
+
+	// There is no single current person to move.
+	assert.ErrorIs(t, u.SetNextPersonToQueue(ctx, "123", owner), ErrHasSlots)
+	assert.ErrorIs(t, u.SkipCurrentPerson(ctx, "123", 4, SkipToEnd), ErrHasSlots)
+
+	assert.ErrorIs(t, u.FinishSlot(ctx, "123", 2, 1), ErrNotAdmin)
+	assert.ErrorIs(t, u.FinishSlot(ctx, "123", owner, 4), ErrInvalidSlot)
+
+	require.NoError(t, u.FinishSlot(ctx, "123", owner, 2))
+
+	queue, err = u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, []entity.User{{ID: 1}, {ID: 4}, {ID: 3}}, queue.Slots)
+	assert.Equal(t, []turnNotification{{userID: 4, peopleBefore: 0}}, notifier.notifications)
+	assert.False(t, queue.IsOver())
+
+	// Nobody waits, so slots are only freed.
+	for _, slot := range []int{1, 2, 3} {
+		require.NoError(t, u.FinishSlot(ctx, "123", owner, slot))
+	}
+
+	assert.ErrorIs(t, u.FinishSlot(ctx, "123", owner, 1), ErrNoCurrentPerson)
+
+	queue, err = u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.True(t, queue.IsOver())
+
+	operation, err := u.Undo(ctx, "123", owner)
+	require.NoError(t, err)
+	assert.Equal(t, entity.OperationSlotDone, operation)
+
+	queue, err = u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, []entity.User{{}, {}, {ID: 3}}, queue.Slots)
+}
@@ -0,0 +1,954 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase/storage"
+)
+
+var ErrQueueExists = errors.New("queue already exists")
+
+type participant struct {
+	user entity.User
+	// joinedAt is a logical timestamp, it grows with every log in.
+	joinedAt     uint64
+	orderNumber  *int64
+	isDeleted    bool
+	isWaitlisted bool
+	// slot is where the person passes the queue now, zero if nowhere.
+	slot    int
+	checkIn entity.CheckIn
+}
+
+type queue struct {
+	messageID        string
+	description      string
+	currentUserIndex int
+	isStarted        bool
+	notifyCount      int
+	language         string
+	capacity         int
+	turnDuration     time.Duration
+	turn             entity.Turn
+	schedule         entity.Schedule
+	templateID       int64
+	slotCount        int
+	checkInTimeout   time.Duration
+	ownerID          int64
+	adminIDs         map[int64]struct{}
+	participants     map[int64]*participant
+	snapshots        []snapshot
+	// finishedAt is zero until the queue is archived.
+	finishedAt time.Time
+	// finishedClock orders archived queues, as finishedAt of two queues may be equal.
+	finishedClock uint64
+}
+
+type snapshot struct {
+	operation        entity.Operation
+	currentUserIndex int
+	isStarted        bool
+	participants     map[int64]participant
+}
+
+// Storage keeps queues in memory. It is safe for concurrent use.
+type Storage struct {
+	mu     sync.RWMutex
+	queues map[string]*queue
+	// events are kept apart from queues, so they stay after the queue is deleted.
+	events      map[string][]entity.Event
+	subscribers map[int64]struct{}
+	templates   map[int64]entity.Template
+	// lastTemplateID is the ID of the latest created template. IDs of deleted templates aren't reused.
+	lastTemplateID int64
+	clock          uint64
+}
+
+func NewStorage() *Storage {
+	return &Storage{
+		queues:      make(map[string]*queue),
+		events:      make(map[string][]entity.Event),
+		subscribers: make(map[int64]struct{}),
+		templates:   make(map[int64]entity.Template),
+	}
+}
+
+func (s *Storage) Ping(_ context.Context) error {
+	return nil
+}
+
+func (s *Storage) Close() error {
+	return nil
+}
+
+func (s *Storage) CreateQueue(
+	_ context.Context,
+	messageID string,
+	description string,
+	ownerID int64,
+	language string,
+	capacity int,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.queues[messageID]; ok {
+		return fmt.Errorf("couldn't create queue %s: %w", messageID, ErrQueueExists)
+	}
+
+	s.queues[messageID] = &queue{
+		messageID:    messageID,
+		notifyCount:  storage.DefaultNotifyCount,
+		slotCount:    storage.DefaultSlotCount,
+		description:  description,
+		ownerID:      ownerID,
+		language:     language,
+		capacity:     capacity,
+		adminIDs:     make(map[int64]struct{}),
+		participants: make(map[int64]*participant),
+	}
+
+	return nil
+}
+
+func (s *Storage) LogInOutToQueue(_ context.Context, messageID string, user entity.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	s.clock++
+
+	p, ok := q.participants[user.ID]
+	if !ok {
+		q.participants[user.ID] = &participant{user: user, joinedAt: s.clock}
+
+		return nil
+	}
+
+	p.isDeleted = !p.isDeleted
+	p.isWaitlisted = false
+	p.slot = 0
+	p.checkIn = entity.CheckIn{}
+	p.joinedAt = s.clock
+
+	return nil
+}
+
+func (s *Storage) JoinWaitlist(_ context.Context, messageID string, user entity.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	s.clock++
+
+	p, ok := q.participants[user.ID]
+	if !ok {
+		q.participants[user.ID] = &participant{user: user, joinedAt: s.clock, isWaitlisted: true}
+
+		return nil
+	}
+
+	p.isDeleted = false
+	p.isWaitlisted = true
+	p.orderNumber = nil
+	p.slot = 0
+	p.checkIn = entity.CheckIn{}
+	p.joinedAt = s.clock
+
+	return nil
+}
+
+func (s *Storage) PromoteFromWaitlist(_ context.Context, messageID string, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	p, ok := q.participants[userID]
+	if !ok || p.isDeleted || !p.isWaitlisted {
+		return fmt.Errorf("user %d in queue %s: %w", userID, messageID, storage.ErrNotWaitlisted)
+	}
+
+	s.clock++
+	p.isWaitlisted = false
+	p.joinedAt = s.clock
+
+	return nil
+}
+
+func (s *Storage) GetQueue(_ context.Context, messageID string) (entity.Queue, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return entity.Queue{}, err
+	}
+
+	var slots []entity.User
+	if q.slotCount > 1 {
+		slots = make([]entity.User, q.slotCount)
+	}
+
+	var users []entity.User
+	var checkIns map[int64]entity.CheckIn
+	for _, p := range q.sortedParticipants() {
+		if p.isDeleted || p.isWaitlisted {
+			continue
+		}
+
+		users = append(users, p.user)
+
+		if !p.checkIn.IsZero() {
+			if checkIns == nil {
+				checkIns = make(map[int64]entity.CheckIn)
+			}
+
+			checkIns[p.user.ID] = p.checkIn
+		}
+
+		if p.slot > 0 && p.slot <= len(slots) {
+			slots[p.slot-1] = p.user
+		}
+	}
+
+	var waitlist []entity.User
+	for _, p := range q.participantsByJoinTime() {
+		if !p.isDeleted && p.isWaitlisted {
+			waitlist = append(waitlist, p.user)
+		}
+	}
+
+	var adminIDs []int64
+	for adminID := range q.adminIDs {
+		adminIDs = append(adminIDs, adminID)
+	}
+
+	sort.Slice(adminIDs, func(i, j int) bool {
+		return adminIDs[i] < adminIDs[j]
+	})
+
+	return entity.Queue{
+		MessageID:        messageID,
+		Description:      q.description,
+		Users:            users,
+		CurrentPersonIdx: q.currentUserIndex,
+		OwnerID:          q.ownerID,
+		AdminIDs:         adminIDs,
+		IsStarted:        q.isStarted,
+		NotifyCount:      q.notifyCount,
+		Language:         q.language,
+		Capacity:         q.capacity,
+		Waitlist:         waitlist,
+		TurnDuration:     q.turnDuration,
+		Turn:             q.turn,
+		Schedule:         q.schedule,
+		TemplateID:       q.templateID,
+		SlotCount:        q.slotCount,
+		Slots:            slots,
+		CheckInTimeout:   q.checkInTimeout,
+		CheckIns:         checkIns,
+	}, nil
+}
+
+func (s *Storage) StartQueue(_ context.Context, messageID string, isShuffle bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	q.currentUserIndex = 0
+	q.isStarted = true
+
+	// Waitlisted people aren't in the queue, so they get no place in it.
+	var participants []*participant
+	for _, p := range q.participantsByJoinTime() {
+		if !p.isWaitlisted {
+			participants = append(participants, p)
+		}
+	}
+
+	if isShuffle {
+		rand.Shuffle(len(participants), func(i, j int) {
+			participants[i], participants[j] = participants[j], participants[i]
+		})
+	}
+
+	for idx, p := range participants {
+		orderNumber := int64(idx + 1)
+		p.orderNumber = &orderNumber
+	}
+
+	return nil
+}
+
+func (s *Storage) IncrementCurrentPerson(_ context.Context, messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	q.currentUserIndex++
+
+	return nil
+}
+
+func (s *Storage) DecrementCurrentPerson(_ context.Context, messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	q.currentUserIndex = max(q.currentUserIndex-1, 0)
+
+	return nil
+}
+
+func (s *Storage) StopQueue(_ context.Context, messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	q.isStarted = false
+
+	return nil
+}
+
+func (s *Storage) SaveSnapshot(_ context.Context, messageID string, operation entity.Operation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	participants := make(map[int64]participant, len(q.participants))
+	for id, p := range q.participants {
+		participants[id] = *p
+	}
+
+	q.snapshots = append(q.snapshots, snapshot{
+		operation:        operation,
+		currentUserIndex: q.currentUserIndex,
+		isStarted:        q.isStarted,
+		participants:     participants,
+	})
+
+	if len(q.snapshots) > storage.HistoryLimit {
+		q.snapshots = q.snapshots[len(q.snapshots)-storage.HistoryLimit:]
+	}
+
+	return nil
+}
+
+func (s *Storage) RestoreSnapshot(_ context.Context, messageID string) (entity.Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return "", err
+	}
+
+	if len(q.snapshots) == 0 {
+		return "", fmt.Errorf("queue %s: %w", messageID, storage.ErrNoSnapshots)
+	}
+
+	last := q.snapshots[len(q.snapshots)-1]
+	q.snapshots = q.snapshots[:len(q.snapshots)-1]
+
+	q.currentUserIndex = last.currentUserIndex
+	q.isStarted = last.isStarted
+	q.participants = make(map[int64]*participant, len(last.participants))
+
+	for id, p := range last.participants {
+		p := p
+		q.participants[id] = &p
+	}
+
+	return last.operation, nil
+}
+
+func (s *Storage) ArchiveQueue(_ context.Context, messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	s.clock++
+	q.finishedAt = time.Now()
+	q.finishedClock = s.clock
+	q.snapshots = nil
+
+	return nil
+}
+
+func (s *Storage) GetFinishedQueues(_ context.Context, userID int64, limit int) ([]entity.FinishedQueue, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var archived []*queue
+	for _, q := range s.queues {
+		if !q.finishedAt.IsZero() {
+			archived = append(archived, q)
+		}
+	}
+
+	sort.Slice(archived, func(i, j int) bool {
+		return archived[i].finishedClock > archived[j].finishedClock
+	})
+
+	var finished []entity.FinishedQueue
+
+	for _, q := range archived {
+		if len(finished) == limit {
+			break
+		}
+
+		userIDs := q.userIDs()
+
+		position := slices.Index(userIDs, userID)
+		if position == -1 {
+			continue
+		}
+
+		finished = append(finished, entity.FinishedQueue{
+			MessageID:        q.messageID,
+			Description:      q.description,
+			Position:         position,
+			Participants:     len(userIDs),
+			CurrentPersonIdx: q.currentUserIndex,
+			FinishedAt:       q.finishedAt,
+		})
+	}
+
+	return finished, nil
+}
+
+func (s *Storage) MoveParticipant(_ context.Context, messageID string, userID int64, position int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	userIDs, err := storage.MoveInOrder(q.userIDs(), userID, position)
+	if err != nil {
+		return fmt.Errorf("couldn't move user %d in queue %s: %w", userID, messageID, err)
+	}
+
+	for idx, id := range userIDs {
+		orderNumber := int64(idx + 1)
+		q.participants[id].orderNumber = &orderNumber
+	}
+
+	return nil
+}
+
+func (s *Storage) AddEvent(_ context.Context, messageID string, operation entity.Operation, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events[messageID] = append(s.events[messageID], entity.Event{
+		Operation: operation,
+		UserID:    userID,
+		CreatedAt: time.Now(),
+	})
+
+	return nil
+}
+
+func (s *Storage) GetEvents(_ context.Context, messageID string, limit int) ([]entity.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	queueEvents := s.events[messageID]
+
+	var events []entity.Event
+	for i := len(queueEvents) - 1; i >= 0 && len(events) < limit; i-- {
+		event := queueEvents[i]
+
+		// People who left stay among participants, so their names are known too.
+		if q, ok := s.queues[messageID]; ok {
+			if p, ok := q.participants[event.UserID]; ok {
+				event.UserName = p.user.Name
+			}
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (s *Storage) AddAdmin(_ context.Context, messageID string, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	q.adminIDs[userID] = struct{}{}
+
+	return nil
+}
+
+func (s *Storage) RemoveAdmin(_ context.Context, messageID string, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if q, ok := s.queues[messageID]; ok {
+		delete(q.adminIDs, userID)
+	}
+
+	return nil
+}
+
+func (s *Storage) GetAdminQueueIDs(_ context.Context, userID int64) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var messageIDs []string
+
+	for messageID, q := range s.queues {
+		if !q.finishedAt.IsZero() {
+			continue
+		}
+
+		if _, isAdmin := q.adminIDs[userID]; q.ownerID == userID || isAdmin {
+			messageIDs = append(messageIDs, messageID)
+		}
+	}
+
+	sort.Strings(messageIDs)
+
+	return messageIDs, nil
+}
+
+func (s *Storage) SetNotifyCount(_ context.Context, messageID string, count int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	q.notifyCount = count
+
+	return nil
+}
+
+func (s *Storage) SetLanguage(_ context.Context, messageID string, language string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	q.language = language
+
+	return nil
+}
+
+func (s *Storage) SetTurnDuration(_ context.Context, messageID string, duration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	q.turnDuration = duration
+
+	return nil
+}
+
+func (s *Storage) SetTurn(_ context.Context, messageID string, turn entity.Turn) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	q.turn = turn
+
+	return nil
+}
+
+func (s *Storage) SetSchedule(_ context.Context, messageID string, schedule entity.Schedule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	q.schedule = schedule
+
+	return nil
+}
+
+func (s *Storage) GetScheduledQueueIDs(_ context.Context) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var messageIDs []string
+
+	for messageID, q := range s.queues {
+		if q.finishedAt.IsZero() && !q.isStarted && !q.schedule.StartsAt.IsZero() {
+			messageIDs = append(messageIDs, messageID)
+		}
+	}
+
+	sort.Strings(messageIDs)
+
+	return messageIDs, nil
+}
+
+func (s *Storage) CreateTemplate(_ context.Context, template entity.Template) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastTemplateID++
+	template.ID = s.lastTemplateID
+	template.Instances = 0
+	s.templates[template.ID] = template
+
+	return template.ID, nil
+}
+
+func (s *Storage) GetTemplate(_ context.Context, templateID int64) (entity.Template, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	template, ok := s.templates[templateID]
+	if !ok {
+		return entity.Template{}, fmt.Errorf("couldn't find template %d: %w", templateID, storage.ErrTemplateNotFound)
+	}
+
+	template.Instances = s.countInstances(templateID)
+
+	return template, nil
+}
+
+func (s *Storage) GetTemplates(_ context.Context, ownerID int64) ([]entity.Template, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var templates []entity.Template
+
+	for _, template := range s.templates {
+		if template.OwnerID == ownerID {
+			template.Instances = s.countInstances(template.ID)
+			templates = append(templates, template)
+		}
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].ID < templates[j].ID
+	})
+
+	return templates, nil
+}
+
+// countInstances returns how many queues, including archived ones, were posted from the template.
+func (s *Storage) countInstances(templateID int64) int {
+	instances := 0
+
+	for _, q := range s.queues {
+		if q.templateID == templateID {
+			instances++
+		}
+	}
+
+	return instances
+}
+
+func (s *Storage) DeleteTemplate(_ context.Context, templateID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.templates[templateID]; !ok {
+		return fmt.Errorf("couldn't find template %d: %w", templateID, storage.ErrTemplateNotFound)
+	}
+
+	delete(s.templates, templateID)
+
+	return nil
+}
+
+func (s *Storage) SetQueueTemplate(_ context.Context, messageID string, templateID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	q.templateID = templateID
+
+	return nil
+}
+
+func (s *Storage) SetSlotCount(_ context.Context, messageID string, count int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	q.slotCount = count
+
+	return nil
+}
+
+func (s *Storage) CallToSlot(_ context.Context, messageID string, slot int, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	q.freeSlot(slot)
+
+	if p, ok := q.participants[userID]; ok {
+		p.slot = slot
+	}
+
+	q.currentUserIndex++
+
+	return nil
+}
+
+func (s *Storage) FreeSlot(_ context.Context, messageID string, slot int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	q.freeSlot(slot)
+
+	return nil
+}
+
+func (q *queue) freeSlot(slot int) {
+	for _, p := range q.participants {
+		if p.slot == slot {
+			p.slot = 0
+		}
+	}
+}
+
+func (s *Storage) GetTimedQueueIDs(_ context.Context) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var messageIDs []string
+
+	for messageID, q := range s.queues {
+		if q.finishedAt.IsZero() && q.isStarted && q.turnDuration > 0 {
+			messageIDs = append(messageIDs, messageID)
+		}
+	}
+
+	sort.Strings(messageIDs)
+
+	return messageIDs, nil
+}
+
+func (s *Storage) SetCheckInTimeout(_ context.Context, messageID string, timeout time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	q.checkInTimeout = timeout
+
+	return nil
+}
+
+func (s *Storage) SetCheckIn(_ context.Context, messageID string, userID int64, checkIn entity.CheckIn) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, err := s.getQueue(messageID)
+	if err != nil {
+		return err
+	}
+
+	if p, ok := q.participants[userID]; ok {
+		p.checkIn = checkIn
+	}
+
+	return nil
+}
+
+func (s *Storage) GetCheckInQueueIDs(_ context.Context) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var messageIDs []string
+
+	for messageID, q := range s.queues {
+		if q.finishedAt.IsZero() && q.isStarted && q.checkInTimeout > 0 {
+			messageIDs = append(messageIDs, messageID)
+		}
+	}
+
+	sort.Strings(messageIDs)
+
+	return messageIDs, nil
+}
+
+func (s *Storage) AddSubscriber(_ context.Context, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subscribers[userID] = struct{}{}
+
+	return nil
+}
+
+func (s *Storage) RemoveSubscriber(_ context.Context, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.subscribers, userID)
+
+	return nil
+}
+
+func (s *Storage) IsSubscriber(_ context.Context, userID int64) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.subscribers[userID]
+
+	return ok, nil
+}
+
+func (s *Storage) CountActive(_ context.Context) (queues int, participants int, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, q := range s.queues {
+		if !q.finishedAt.IsZero() {
+			continue
+		}
+
+		queues++
+		participants += len(q.userIDs())
+	}
+
+	return queues, participants, nil
+}
+
+// getQueue returns not archived queue. It must be called with mu held.
+func (s *Storage) getQueue(messageID string) (*queue, error) {
+	q, ok := s.queues[messageID]
+	if !ok || !q.finishedAt.IsZero() {
+		return nil, fmt.Errorf("couldn't find queue %s: %w", messageID, storage.ErrQueueNotFound)
+	}
+
+	return q, nil
+}
+
+func (q *queue) participantsByJoinTime() []*participant {
+	participants := make([]*participant, 0, len(q.participants))
+	for _, p := range q.participants {
+		participants = append(participants, p)
+	}
+
+	sort.Slice(participants, func(i, j int) bool {
+		return participants[i].joinedAt < participants[j].joinedAt
+	})
+
+	return participants
+}
+
+// sortedParticipants orders participants the same way sql storages do:
+// by order number with unordered ones at the end, then by join time.
+func (q *queue) sortedParticipants() []*participant {
+	participants := q.participantsByJoinTime()
+
+	sort.SliceStable(participants, func(i, j int) bool {
+		left, right := participants[i].orderNumber, participants[j].orderNumber
+		if left == nil || right == nil {
+			return left != nil && right == nil
+		}
+
+		return *left < *right
+	})
+
+	return participants
+}
+
+// userIDs returns participants who haven't left the queue in their order. Waitlisted people aren't included.
+func (q *queue) userIDs() []int64 {
+	var userIDs []int64
+	for _, p := range q.sortedParticipants() {
+		if !p.isDeleted && !p.isWaitlisted {
+			userIDs = append(userIDs, p.user.ID)
+		}
+	}
+
+	return userIDs
+}
@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase/storage"
+	"QueueBot/internal/usecase/storage/storagetest"
+)
+
+func TestStorage_Conformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		return NewStorage()
+	})
+}
+
+func TestStorage_ConcurrentLogIn(t *testing.T) {
+	s := NewStorage()
+	assert.NoError(t, s.CreateQueue(context.Background(), "123", "Test", 1, "ru", 0))
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 50; i++ {
+		wg.Add(1)
+
+		go func(id int64) {
+			defer wg.Done()
+			assert.NoError(t, s.LogInOutToQueue(context.Background(), "123", entity.User{ID: id}))
+
+			_, err := s.GetQueue(context.Background(), "123")
+			assert.NoError(t, err)
+		}(int64(i))
+	}
+	wg.Wait()
+
+	queue, err := s.GetQueue(context.Background(), "123")
+	assert.NoError(t, err)
+	assert.Len(t, queue.Users, 50)
+}
@@ -0,0 +1,157 @@
+// Package migrate applies numbered schema migrations to sql storages.
+//
+// Applied versions are kept in schema_version table. Every migration runs in its own transaction
+// together with the schema_version update, so a failed migration leaves the schema untouched.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrSchemaTooNew      = errors.New("database schema is newer than the binary")
+	ErrInvalidMigrations = errors.New("migrations must be numbered sequentially starting from 1")
+	ErrUnknownVersion    = errors.New("unknown schema version")
+)
+
+const createVersionTable = `
+CREATE TABLE IF NOT EXISTS schema_version
+(
+    version    INTEGER NOT NULL PRIMARY KEY,
+    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Version returns the latest applied migration or 0 for an empty database.
+func Version(ctx context.Context, db *sql.DB) (int, error) {
+	if _, err := db.ExecContext(ctx, createVersionTable); err != nil {
+		return 0, fmt.Errorf("couldn't create schema_version table: %w", err)
+	}
+
+	var version int
+	if err := db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("couldn't get schema version: %w", err)
+	}
+
+	return version, nil
+}
+
+// Up applies every migration newer than the database schema.
+// It refuses to work with a schema that was created by a newer binary.
+func Up(ctx context.Context, db *sql.DB, migrations []Migration) error {
+	if err := validate(migrations); err != nil {
+		return err
+	}
+
+	version, err := Version(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	if version > len(migrations) {
+		return fmt.Errorf("%w: database has version %d, latest known is %d", ErrSchemaTooNew, version, len(migrations))
+	}
+
+	for _, m := range migrations[version:] {
+		m := m
+
+		err = inTx(ctx, db, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, m.Up); err != nil {
+				return fmt.Errorf("couldn't apply migration: %w", err)
+			}
+
+			if _, err := tx.ExecContext(ctx, "INSERT INTO schema_version (version) VALUES ($1)", m.Version); err != nil {
+				return fmt.Errorf("couldn't save schema version: %w", err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Down reverts migrations one by one until the database schema has target version.
+func Down(ctx context.Context, db *sql.DB, migrations []Migration, target int) error {
+	if err := validate(migrations); err != nil {
+		return err
+	}
+
+	version, err := Version(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	if version > len(migrations) {
+		return fmt.Errorf("%w: database has version %d, latest known is %d", ErrSchemaTooNew, version, len(migrations))
+	}
+
+	if target < 0 || target > version {
+		return fmt.Errorf("%w: can't go down from %d to %d", ErrUnknownVersion, version, target)
+	}
+
+	for i := version - 1; i >= target; i-- {
+		m := migrations[i]
+
+		err = inTx(ctx, db, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, m.Down); err != nil {
+				return fmt.Errorf("couldn't revert migration: %w", err)
+			}
+
+			if _, err := tx.ExecContext(ctx, "DELETE FROM schema_version WHERE version = $1", m.Version); err != nil {
+				return fmt.Errorf("couldn't save schema version: %w", err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("reverting migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func validate(migrations []Migration) error {
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return fmt.Errorf("%w: got %d at position %d", ErrInvalidMigrations, m.Version, i)
+		}
+	}
+
+	return nil
+}
+
+func inTx(ctx context.Context, db *sql.DB, f func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("couldn't begin transaction: %w", err)
+	}
+
+	if err = f(tx); err != nil {
+		if txErr := tx.Rollback(); txErr != nil {
+			return fmt.Errorf("%w, unable to rollback: %w", err, txErr)
+		}
+
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("couldn't commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	// Sqlite driver...
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMigrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create first",
+		Up:      "CREATE TABLE first (id INTEGER);",
+		Down:    "DROP TABLE first;",
+	},
+	{
+		Version: 2,
+		Name:    "create second",
+		Up:      "CREATE TABLE second (id INTEGER); INSERT INTO second (id) VALUES (1);",
+		Down:    "DROP TABLE second;",
+	},
+}
+
+func openDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "migrate.db"))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, db.Close())
+	})
+
+	return db
+}
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&count)
+	require.NoError(t, err)
+
+	return count > 0
+}
+
+func requireVersion(t *testing.T, db *sql.DB, want int) {
+	t.Helper()
+
+	version, err := Version(context.Background(), db)
+	require.NoError(t, err)
+	require.Equal(t, want, version)
+}
+
+func TestUp(t *testing.T) {
+	db := openDB(t)
+	requireVersion(t, db, 0)
+
+	require.NoError(t, Up(context.Background(), db, testMigrations[:1]))
+	requireVersion(t, db, 1)
+	assert.True(t, tableExists(t, db, "first"))
+	assert.False(t, tableExists(t, db, "second"))
+
+	require.NoError(t, Up(context.Background(), db, testMigrations))
+	requireVersion(t, db, 2)
+	assert.True(t, tableExists(t, db, "second"))
+
+	// Nothing is left to apply, so the second run is a no-op.
+	require.NoError(t, Up(context.Background(), db, testMigrations))
+	requireVersion(t, db, 2)
+}
+
+func TestUp_SchemaTooNew(t *testing.T) {
+	db := openDB(t)
+	require.NoError(t, Up(context.Background(), db, testMigrations))
+
+	err := Up(context.Background(), db, testMigrations[:1])
+	assert.ErrorIs(t, err, ErrSchemaTooNew)
+	requireVersion(t, db, 2)
+}
+
+func TestUp_FailedMigrationIsRolledBack(t *testing.T) {
+	db := openDB(t)
+
+	broken := append([]Migration{}, testMigrations[0], Migration{
+		Version: 2,
+		Name:    "broken",
+		Up:      "CREATE TABLE second (id INTEGER); INSERT INTO missing (id) VALUES (1);",
+	})
+
+	assert.Error(t, Up(context.Background(), db, broken))
+	requireVersion(t, db, 1)
+	assert.True(t, tableExists(t, db, "first"))
+	assert.False(t, tableExists(t, db, "second"))
+}
+
+func TestUp_InvalidMigrations(t *testing.T) {
+	db := openDB(t)
+
+	err := Up(context.Background(), db, testMigrations[1:])
+	assert.ErrorIs(t, err, ErrInvalidMigrations)
+	assert.False(t, tableExists(t, db, "second"))
+}
+
+func TestDown(t *testing.T) {
+	db := openDB(t)
+	require.NoError(t, Up(context.Background(), db, testMigrations))
+
+	require.NoError(t, Down(context.Background(), db, testMigrations, 1))
+	requireVersion(t, db, 1)
+	assert.True(t, tableExists(t, db, "first"))
+	assert.False(t, tableExists(t, db, "second"))
+
+	require.NoError(t, Down(context.Background(), db, testMigrations, 0))
+	requireVersion(t, db, 0)
+	assert.False(t, tableExists(t, db, "first"))
+
+	require.NoError(t, Up(context.Background(), db, testMigrations))
+	requireVersion(t, db, 2)
+}
+
+func TestDown_UnknownVersion(t *testing.T) {
+	db := openDB(t)
+	require.NoError(t, Up(context.Background(), db, testMigrations[:1]))
+
+	assert.ErrorIs(t, Down(context.Background(), db, testMigrations, 2), ErrUnknownVersion)
+	assert.ErrorIs(t, Down(context.Background(), db, testMigrations, -1), ErrUnknownVersion)
+	requireVersion(t, db, 1)
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrParticipantNotFound = errors.New("participant not found")
+
+// MoveInOrder returns a copy of userIDs where userID is moved to position.
+// Positions past the end move user to the end.
+func MoveInOrder(userIDs []int64, userID int64, position int) ([]int64, error) {
+	from := -1
+
+	for idx, id := range userIDs {
+		if id == userID {
+			from = idx
+
+			break
+		}
+	}
+
+	if from == -1 {
+		return nil, fmt.Errorf("user %d: %w", userID, ErrParticipantNotFound)
+	}
+
+	result := make([]int64, 0, len(userIDs))
+	result = append(result, userIDs[:from]...)
+	result = append(result, userIDs[from+1:]...)
+
+	position = max(0, min(position, len(result)))
+
+	result = append(result[:position], append([]int64{userID}, result[position:]...)...)
+
+	return result, nil
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoveInOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int64
+		position int
+		want     []int64
+		wantErr  error
+	}{
+		{name: "Move back", userID: 1, position: 2, want: []int64{2, 3, 1, 4}},
+		{name: "Move forward", userID: 4, position: 0, want: []int64{4, 1, 2, 3}},
+		{name: "Same position", userID: 2, position: 1, want: []int64{1, 2, 3, 4}},
+		{name: "Past the end", userID: 2, position: 10, want: []int64{1, 3, 4, 2}},
+		{name: "Negative position", userID: 3, position: -1, want: []int64{3, 1, 2, 4}},
+		{name: "Unknown user", userID: 5, position: 0, wantErr: ErrParticipantNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userIDs := []int64{1, 2, 3, 4}
+
+			got, err := MoveInOrder(userIDs, tt.userID, tt.position)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, []int64{1, 2, 3, 4}, userIDs)
+		})
+	}
+}
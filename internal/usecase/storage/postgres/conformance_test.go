@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"QueueBot/internal/usecase/storage"
+	"QueueBot/internal/usecase/storage/storagetest"
+)
+
+// testDSNEnv points to a database which is wiped before every test case.
+const testDSNEnv = "TEST_POSTGRES_DSN"
+
+func TestDatabase_Conformance(t *testing.T) {
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		db, err := NewDatabase(dsn)
+		require.NoError(t, err)
+
+		_, err = db.db.Exec("TRUNCATE queues, queue_events, subscribers, templates CASCADE")
+		require.NoError(t, err)
+
+		t.Cleanup(func() {
+			assert.NoError(t, db.Close())
+		})
+
+		return db
+	})
+}
@@ -0,0 +1,241 @@
+package postgres
+
+import "QueueBot/internal/usecase/storage/migrate"
+
+// migrations must only be appended to. Changing an applied migration won't change existing databases.
+var migrations = []migrate.Migration{
+	{
+		Version: 1,
+		Name:    "create queues and participants",
+		Up: `
+CREATE TABLE IF NOT EXISTS queues
+(
+    message_id         TEXT    NOT NULL PRIMARY KEY,
+    description        TEXT             DEFAULT NULL,
+    current_user_index INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS participants
+(
+    message_id   TEXT        NOT NULL REFERENCES queues (message_id),
+    user_id      BIGINT      NOT NULL,
+    user_name    VARCHAR     NOT NULL,
+    joined_at    TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp(),
+    order_number BIGINT,
+    is_deleted   BOOLEAN     NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (message_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_prt_message_id ON participants (message_id, user_id, is_deleted);
+`,
+		Down: `
+DROP TABLE participants;
+DROP TABLE queues;
+`,
+	},
+	{
+		Version: 2,
+		Name:    "add queue owner and admins",
+		Up: `
+ALTER TABLE queues ADD COLUMN owner_id BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE queue_admins
+(
+    message_id TEXT   NOT NULL REFERENCES queues (message_id),
+    user_id    BIGINT NOT NULL,
+    PRIMARY KEY (message_id, user_id)
+);
+`,
+		Down: `
+DROP TABLE queue_admins;
+ALTER TABLE queues DROP COLUMN owner_id;
+`,
+	},
+	{
+		Version: 3,
+		Name:    "add started flag and undo history",
+		Up: `
+ALTER TABLE queues ADD COLUMN is_started BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE queue_history
+(
+    id                 BIGSERIAL PRIMARY KEY,
+    message_id         TEXT        NOT NULL REFERENCES queues (message_id),
+    operation          TEXT        NOT NULL,
+    current_user_index INTEGER     NOT NULL,
+    is_started         BOOLEAN     NOT NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_queue_history_message_id ON queue_history (message_id, id);
+
+CREATE TABLE participants_history
+(
+    history_id   BIGINT      NOT NULL REFERENCES queue_history (id),
+    user_id      BIGINT      NOT NULL,
+    user_name    VARCHAR     NOT NULL,
+    joined_at    TIMESTAMPTZ NOT NULL,
+    order_number BIGINT,
+    is_deleted   BOOLEAN     NOT NULL,
+    PRIMARY KEY (history_id, user_id)
+);
+`,
+		Down: `
+DROP TABLE participants_history;
+DROP TABLE queue_history;
+ALTER TABLE queues DROP COLUMN is_started;
+`,
+	},
+	{
+		Version: 4,
+		Name:    "add queue events",
+		Up: `
+CREATE TABLE queue_events
+(
+    id         BIGSERIAL PRIMARY KEY,
+    message_id TEXT        NOT NULL,
+    operation  TEXT        NOT NULL,
+    user_id    BIGINT      NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX idx_queue_events_message_id ON queue_events (message_id, id);
+`,
+		Down: `DROP TABLE queue_events;`,
+	},
+	{
+		Version: 5,
+		Name:    "archive finished queues",
+		Up:      `ALTER TABLE queues ADD COLUMN finished_at TIMESTAMPTZ;`,
+		Down:    `ALTER TABLE queues DROP COLUMN finished_at;`,
+	},
+	{
+		Version: 6,
+		Name:    "add turn notifications",
+		Up: `
+ALTER TABLE queues ADD COLUMN notify_count INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE subscribers
+(
+    user_id       BIGINT PRIMARY KEY,
+    subscribed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+`,
+		Down: `
+DROP TABLE subscribers;
+ALTER TABLE queues DROP COLUMN notify_count;
+`,
+	},
+	{
+		Version: 7,
+		Name:    "add queue language",
+		Up:      `ALTER TABLE queues ADD COLUMN language TEXT NOT NULL DEFAULT 'ru';`,
+		Down:    `ALTER TABLE queues DROP COLUMN language;`,
+	},
+	{
+		Version: 8,
+		Name:    "add queue capacity and waitlist",
+		Up: `
+ALTER TABLE queues ADD COLUMN capacity INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE participants ADD COLUMN is_waitlisted BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE participants_history ADD COLUMN is_waitlisted BOOLEAN NOT NULL DEFAULT FALSE;
+`,
+		Down: `
+ALTER TABLE participants_history DROP COLUMN is_waitlisted;
+ALTER TABLE participants DROP COLUMN is_waitlisted;
+ALTER TABLE queues DROP COLUMN capacity;
+`,
+	},
+	{
+		Version: 9,
+		Name:    "add timed turns",
+		Up: `
+ALTER TABLE queues ADD COLUMN turn_duration INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE queues ADD COLUMN turn_user_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE queues ADD COLUMN turn_started_at TIMESTAMPTZ;
+ALTER TABLE queues ADD COLUMN turn_is_warned BOOLEAN NOT NULL DEFAULT FALSE;
+`,
+		Down: `
+ALTER TABLE queues DROP COLUMN turn_is_warned;
+ALTER TABLE queues DROP COLUMN turn_started_at;
+ALTER TABLE queues DROP COLUMN turn_user_id;
+ALTER TABLE queues DROP COLUMN turn_duration;
+`,
+	},
+	{
+		Version: 10,
+		Name:    "add queue schedule",
+		Up: `
+ALTER TABLE queues ADD COLUMN opens_at TIMESTAMPTZ;
+ALTER TABLE queues ADD COLUMN starts_at TIMESTAMPTZ;
+ALTER TABLE queues ADD COLUMN is_shuffle BOOLEAN NOT NULL DEFAULT FALSE;
+`,
+		Down: `
+ALTER TABLE queues DROP COLUMN is_shuffle;
+ALTER TABLE queues DROP COLUMN starts_at;
+ALTER TABLE queues DROP COLUMN opens_at;
+`,
+	},
+	{
+		Version: 11,
+		Name:    "add queue templates",
+		Up: `
+CREATE TABLE templates
+(
+    id          BIGSERIAL PRIMARY KEY,
+    owner_id    BIGINT  NOT NULL,
+    description TEXT    NOT NULL,
+    capacity    INTEGER NOT NULL DEFAULT 0,
+    opens_at    TEXT    NOT NULL DEFAULT '',
+    starts_at   TEXT    NOT NULL DEFAULT '',
+    is_shuffle  BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX idx_templates_owner_id ON templates (owner_id);
+
+ALTER TABLE queues ADD COLUMN template_id BIGINT;
+CREATE INDEX idx_queues_template_id ON queues (template_id);
+`,
+		Down: `
+DROP INDEX idx_queues_template_id;
+ALTER TABLE queues DROP COLUMN template_id;
+DROP TABLE templates;
+`,
+	},
+	{
+		Version: 12,
+		Name:    "add service slots",
+		Up: `
+ALTER TABLE queues ADD COLUMN slot_count INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE participants ADD COLUMN slot INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE participants_history ADD COLUMN slot INTEGER NOT NULL DEFAULT 0;
+`,
+		Down: `
+ALTER TABLE participants_history DROP COLUMN slot;
+ALTER TABLE participants DROP COLUMN slot;
+ALTER TABLE queues DROP COLUMN slot_count;
+`,
+	},
+	{
+		Version: 13,
+		Name:    "add check-in",
+		Up: `
+ALTER TABLE queues ADD COLUMN check_in_timeout INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE participants ADD COLUMN check_in_asked_at TIMESTAMPTZ;
+ALTER TABLE participants ADD COLUMN is_checked_in BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE participants ADD COLUMN missed_check_ins INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE participants_history ADD COLUMN check_in_asked_at TIMESTAMPTZ;
+ALTER TABLE participants_history ADD COLUMN is_checked_in BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE participants_history ADD COLUMN missed_check_ins INTEGER NOT NULL DEFAULT 0;
+`,
+		Down: `
+ALTER TABLE participants_history DROP COLUMN missed_check_ins;
+ALTER TABLE participants_history DROP COLUMN is_checked_in;
+ALTER TABLE participants_history DROP COLUMN check_in_asked_at;
+ALTER TABLE participants DROP COLUMN missed_check_ins;
+ALTER TABLE participants DROP COLUMN is_checked_in;
+ALTER TABLE participants DROP COLUMN check_in_asked_at;
+ALTER TABLE queues DROP COLUMN check_in_timeout;
+`,
+	},
+}
@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase/storage"
+)
+
+func (s Database) DecrementCurrentPerson(ctx context.Context, messageID string) error {
+	decrementStmt, err := s.db.PrepareContext(
+		ctx,
+		"UPDATE queues SET current_user_index = GREATEST(current_user_index - 1, 0) WHERE message_id = $1",
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't prepare decrement current person statement: %w", err)
+	}
+	defer decrementStmt.Close()
+
+	result, err := decrementStmt.ExecContext(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("couldn't decrement current person: %w", err)
+	}
+
+	return checkQueueAffected(result, messageID)
+}
+
+func (s Database) StopQueue(ctx context.Context, messageID string) error {
+	stopStmt, err := s.db.PrepareContext(ctx, "UPDATE queues SET is_started = FALSE WHERE message_id = $1")
+	if err != nil {
+		return fmt.Errorf("couldn't prepare stop queue statement: %w", err)
+	}
+	defer stopStmt.Close()
+
+	result, err := stopStmt.ExecContext(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("couldn't stop queue: %w", err)
+	}
+
+	return checkQueueAffected(result, messageID)
+}
+
+func (s Database) SaveSnapshot(ctx context.Context, messageID string, operation entity.Operation) error {
+	return runInTx(ctx, s.db, func(tx *sql.Tx) error {
+		var historyID int64
+
+		err := tx.QueryRowContext(
+			ctx,
+			`INSERT INTO queue_history (message_id, operation, current_user_index, is_started)
+			SELECT message_id, $1::TEXT, current_user_index, is_started FROM queues WHERE message_id = $2 RETURNING id`,
+			string(operation), messageID,
+		).Scan(&historyID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("couldn't find queue %s: %w", messageID, storage.ErrQueueNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("couldn't save queue %s history: %w", messageID, err)
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			`INSERT INTO participants_history (history_id, user_id, user_name, joined_at, order_number, is_deleted, is_waitlisted, slot, check_in_asked_at, is_checked_in, missed_check_ins)
+			SELECT $1::BIGINT, user_id, user_name, joined_at, order_number, is_deleted, is_waitlisted, slot, check_in_asked_at, is_checked_in, missed_check_ins FROM participants WHERE message_id = $2`,
+			historyID, messageID,
+		)
+		if err != nil {
+			return fmt.Errorf("couldn't save participants history of queue %s: %w", messageID, err)
+		}
+
+		return trimHistory(ctx, tx, messageID)
+	})
+}
+
+// trimHistory forgets snapshots older than storage.HistoryLimit latest ones.
+func trimHistory(ctx context.Context, tx *sql.Tx, messageID string) error {
+	const oldSnapshots = `SELECT id FROM queue_history WHERE message_id = $1 AND id <=
+		(SELECT id FROM queue_history WHERE message_id = $2 ORDER BY id DESC LIMIT 1 OFFSET $3)`
+
+	_, err := tx.ExecContext(
+		ctx,
+		"DELETE FROM participants_history WHERE history_id IN ("+oldSnapshots+")",
+		messageID, messageID, storage.HistoryLimit,
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't trim participants history of queue %s: %w", messageID, err)
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM queue_history WHERE id IN ("+oldSnapshots+")", messageID, messageID, storage.HistoryLimit)
+	if err != nil {
+		return fmt.Errorf("couldn't trim history of queue %s: %w", messageID, err)
+	}
+
+	return nil
+}
+
+func (s Database) RestoreSnapshot(ctx context.Context, messageID string) (operation entity.Operation, err error) {
+	err = runInTx(ctx, s.db, func(tx *sql.Tx) error {
+		var historyID int64
+		var currentUserIndex int
+		var isStarted bool
+
+		err := tx.QueryRowContext(
+			ctx,
+			`SELECT id, operation, current_user_index, is_started FROM queue_history
+			WHERE message_id = $1 ORDER BY id DESC LIMIT 1`,
+			messageID,
+		).Scan(&historyID, &operation, &currentUserIndex, &isStarted)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("queue %s: %w", messageID, storage.ErrNoSnapshots)
+		}
+
+		if err != nil {
+			return fmt.Errorf("couldn't get queue %s history: %w", messageID, err)
+		}
+
+		restoreStatements := []struct {
+			name  string
+			query string
+			args  []any
+		}{
+			{
+				name:  "queue",
+				query: "UPDATE queues SET current_user_index = $1, is_started = $2 WHERE message_id = $3",
+				args:  []any{currentUserIndex, isStarted, messageID},
+			},
+			{
+				name:  "participants",
+				query: "DELETE FROM participants WHERE message_id = $1",
+				args:  []any{messageID},
+			},
+			{
+				name: "participants",
+				query: `INSERT INTO participants (message_id, user_id, user_name, joined_at, order_number, is_deleted, is_waitlisted, slot, check_in_asked_at, is_checked_in, missed_check_ins)
+				SELECT $1::TEXT, user_id, user_name, joined_at, order_number, is_deleted, is_waitlisted, slot, check_in_asked_at, is_checked_in, missed_check_ins FROM participants_history WHERE history_id = $2`,
+				args: []any{messageID, historyID},
+			},
+			{
+				name:  "participants history",
+				query: "DELETE FROM participants_history WHERE history_id = $1",
+				args:  []any{historyID},
+			},
+			{
+				name:  "queue history",
+				query: "DELETE FROM queue_history WHERE id = $1",
+				args:  []any{historyID},
+			},
+		}
+
+		for _, statement := range restoreStatements {
+			if _, err = tx.ExecContext(ctx, statement.query, statement.args...); err != nil {
+				return fmt.Errorf("couldn't restore %s of queue %s: %w", statement.name, messageID, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return operation, nil
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"QueueBot/internal/usecase/storage"
+)
+
+// MoveParticipant moves active participant to position among active participants and renumbers the queue.
+// Positions past the end of the queue move participant to the end.
+func (s Database) MoveParticipant(ctx context.Context, messageID string, userID int64, position int) error {
+	return runInTx(ctx, s.db, func(tx *sql.Tx) error {
+		userIDs, err := getParticipantsOrder(ctx, tx, messageID)
+		if err != nil {
+			return err
+		}
+
+		userIDs, err = storage.MoveInOrder(userIDs, userID, position)
+		if err != nil {
+			return fmt.Errorf("couldn't move user %d in queue %s: %w", userID, messageID, err)
+		}
+
+		return setOrderNumbers(ctx, tx, messageID, userIDs)
+	})
+}
+
+// getParticipantsOrder returns ids of active participants in the same order as GetQueue does.
+func getParticipantsOrder(ctx context.Context, tx *sql.Tx, messageID string) ([]int64, error) {
+	rows, err := tx.QueryContext(
+		ctx,
+		`SELECT user_id FROM participants WHERE message_id = $1 AND NOT is_deleted AND NOT is_waitlisted
+		ORDER BY order_number NULLS LAST, joined_at`,
+		messageID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get participants order of queue %s: %w", messageID, err)
+	}
+	defer rows.Close()
+
+	var userIDs []int64
+
+	for rows.Next() {
+		var userID int64
+		if err = rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("couldn't scan participant row in queue %s: %w", messageID, err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during iterating participant rows from queue %s: %w", messageID, err)
+	}
+
+	return userIDs, nil
+}
+
+// setOrderNumbers numbers participants from 1 in the given order.
+func setOrderNumbers(ctx context.Context, tx *sql.Tx, messageID string, userIDs []int64) error {
+	setOrderStmt, err := tx.PrepareContext(ctx, "UPDATE participants SET order_number = $1 WHERE message_id = $2 AND user_id = $3")
+	if err != nil {
+		return fmt.Errorf("couldn't prepare set order number statement: %w", err)
+	}
+	defer setOrderStmt.Close()
+
+	for idx, userID := range userIDs {
+		if _, err = setOrderStmt.ExecContext(ctx, idx+1, messageID, userID); err != nil {
+			return fmt.Errorf("couldn't set order number of user %d in queue %s: %w", userID, messageID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"QueueBot/internal/entity"
+)
+
+func (s Database) SetSchedule(ctx context.Context, messageID string, schedule entity.Schedule) error {
+	setStmt, err := s.db.PrepareContext(
+		ctx,
+		"UPDATE queues SET opens_at = $1, starts_at = $2, is_shuffle = $3 WHERE message_id = $4",
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't prepare set schedule statement: %w", err)
+	}
+	defer setStmt.Close()
+
+	result, err := setStmt.ExecContext(ctx, nullTime(schedule.OpensAt), nullTime(schedule.StartsAt), schedule.IsShuffle, messageID)
+	if err != nil {
+		return fmt.Errorf("couldn't set schedule: %w", err)
+	}
+
+	return checkQueueAffected(result, messageID)
+}
+
+func (s Database) GetScheduledQueueIDs(ctx context.Context) ([]string, error) {
+	return s.getQueueIDs(
+		ctx,
+		"scheduled",
+		"SELECT message_id FROM queues WHERE finished_at IS NULL AND NOT is_started AND starts_at IS NOT NULL ORDER BY message_id",
+	)
+}
+
+// getQueueIDs returns message IDs the query selects. Kind names the queues in errors.
+func (s Database) getQueueIDs(ctx context.Context, kind string, query string) ([]string, error) {
+	getStmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't prepare get %s queues statement: %w", kind, err)
+	}
+	defer getStmt.Close()
+
+	rows, err := getStmt.QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get %s queues: %w", kind, err)
+	}
+	defer rows.Close()
+
+	var messageIDs []string
+
+	for rows.Next() {
+		var messageID string
+		if err = rows.Scan(&messageID); err != nil {
+			return nil, fmt.Errorf("couldn't scan %s queue: %w", kind, err)
+		}
+
+		messageIDs = append(messageIDs, messageID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("couldn't read %s queues: %w", kind, err)
+	}
+
+	return messageIDs, nil
+}
+
+// nullTime stores zero time as NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
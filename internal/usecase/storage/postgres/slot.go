@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+func (s Database) SetSlotCount(ctx context.Context, messageID string, count int) error {
+	setStmt, err := s.db.PrepareContext(ctx, "UPDATE queues SET slot_count = $1 WHERE message_id = $2")
+	if err != nil {
+		return fmt.Errorf("couldn't prepare set slot count statement: %w", err)
+	}
+	defer setStmt.Close()
+
+	result, err := setStmt.ExecContext(ctx, count, messageID)
+	if err != nil {
+		return fmt.Errorf("couldn't set slot count: %w", err)
+	}
+
+	return checkQueueAffected(result, messageID)
+}
+
+func (s Database) CallToSlot(ctx context.Context, messageID string, slot int, userID int64) error {
+	return runInTx(ctx, s.db, func(tx *sql.Tx) error {
+		if err := freeSlot(ctx, tx, messageID, slot); err != nil {
+			return err
+		}
+
+		callStmt, err := tx.PrepareContext(ctx, "UPDATE participants SET slot = $1 WHERE message_id = $2 AND user_id = $3")
+		if err != nil {
+			return fmt.Errorf("couldn't prepare call to slot statement: %w", err)
+		}
+		defer callStmt.Close()
+
+		if _, err = callStmt.ExecContext(ctx, slot, messageID, userID); err != nil {
+			return fmt.Errorf("couldn't call user %d to slot %d in queue %s: %w", userID, slot, messageID, err)
+		}
+
+		incrementStmt, err := tx.PrepareContext(ctx, "UPDATE queues SET current_user_index = current_user_index + 1 WHERE message_id = $1")
+		if err != nil {
+			return fmt.Errorf("couldn't prepare increment current person statement: %w", err)
+		}
+		defer incrementStmt.Close()
+
+		result, err := incrementStmt.ExecContext(ctx, messageID)
+		if err != nil {
+			return fmt.Errorf("couldn't increment current person: %w", err)
+		}
+
+		return checkQueueAffected(result, messageID)
+	})
+}
+
+func (s Database) FreeSlot(ctx context.Context, messageID string, slot int) error {
+	return runInTx(ctx, s.db, func(tx *sql.Tx) error {
+		return freeSlot(ctx, tx, messageID, slot)
+	})
+}
+
+func freeSlot(ctx context.Context, tx *sql.Tx, messageID string, slot int) error {
+	freeStmt, err := tx.PrepareContext(ctx, "UPDATE participants SET slot = 0 WHERE message_id = $1 AND slot = $2")
+	if err != nil {
+		return fmt.Errorf("couldn't prepare free slot statement: %w", err)
+	}
+	defer freeStmt.Close()
+
+	if _, err = freeStmt.ExecContext(ctx, messageID, slot); err != nil {
+		return fmt.Errorf("couldn't free slot %d in queue %s: %w", slot, messageID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"QueueBot/internal/entity"
+)
+
+// SetTurnDuration stores the duration in whole seconds.
+func (s Database) SetTurnDuration(ctx context.Context, messageID string, duration time.Duration) error {
+	setStmt, err := s.db.PrepareContext(ctx, "UPDATE queues SET turn_duration = $1 WHERE message_id = $2")
+	if err != nil {
+		return fmt.Errorf("couldn't prepare set turn duration statement: %w", err)
+	}
+	defer setStmt.Close()
+
+	result, err := setStmt.ExecContext(ctx, int64(duration/time.Second), messageID)
+	if err != nil {
+		return fmt.Errorf("couldn't set turn duration: %w", err)
+	}
+
+	return checkQueueAffected(result, messageID)
+}
+
+func (s Database) SetTurn(ctx context.Context, messageID string, turn entity.Turn) error {
+	setStmt, err := s.db.PrepareContext(
+		ctx,
+		"UPDATE queues SET turn_user_id = $1, turn_started_at = $2, turn_is_warned = $3 WHERE message_id = $4",
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't prepare set turn statement: %w", err)
+	}
+	defer setStmt.Close()
+
+	result, err := setStmt.ExecContext(ctx, turn.UserID, nullTime(turn.StartedAt), turn.IsWarned, messageID)
+	if err != nil {
+		return fmt.Errorf("couldn't set turn: %w", err)
+	}
+
+	return checkQueueAffected(result, messageID)
+}
+
+func (s Database) GetTimedQueueIDs(ctx context.Context) ([]string, error) {
+	return s.getQueueIDs(
+		ctx,
+		"timed",
+		"SELECT message_id FROM queues WHERE finished_at IS NULL AND is_started AND turn_duration > 0 ORDER BY message_id",
+	)
+}
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase/storage"
+)
+
+func (s Database) JoinWaitlist(ctx context.Context, messageID string, user entity.User) error {
+	joinStmt, err := s.db.PrepareContext(ctx, `INSERT INTO participants(message_id, user_id, user_name, joined_at, is_waitlisted)
+	VALUES ($1, $2, $3, clock_timestamp(), TRUE) ON CONFLICT (message_id, user_id)
+	DO UPDATE SET is_deleted = FALSE, is_waitlisted = TRUE, order_number = NULL, slot = 0, check_in_asked_at = NULL,
+	is_checked_in = FALSE, missed_check_ins = 0, joined_at = clock_timestamp()`)
+	if err != nil {
+		return fmt.Errorf("couldn't prepare join waitlist statement: %w", err)
+	}
+	defer joinStmt.Close()
+
+	if _, err = joinStmt.ExecContext(ctx, messageID, user.ID, user.Name); err != nil {
+		return fmt.Errorf("couldn't add user %d to waitlist of queue %s: %w", user.ID, messageID, err)
+	}
+
+	return nil
+}
+
+func (s Database) PromoteFromWaitlist(ctx context.Context, messageID string, userID int64) error {
+	// The promoted user joins now, so they get into the queue after everybody who is already in it.
+	promoteStmt, err := s.db.PrepareContext(ctx, `UPDATE participants
+	SET is_waitlisted = FALSE, joined_at = clock_timestamp()
+	WHERE message_id = $1 AND user_id = $2 AND is_waitlisted AND NOT is_deleted`)
+	if err != nil {
+		return fmt.Errorf("couldn't prepare promote from waitlist statement: %w", err)
+	}
+	defer promoteStmt.Close()
+
+	result, err := promoteStmt.ExecContext(ctx, messageID, userID)
+	if err != nil {
+		return fmt.Errorf("couldn't promote user %d in queue %s: %w", userID, messageID, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("couldn't get affected rows: %w", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("user %d in queue %s: %w", userID, messageID, storage.ErrNotWaitlisted)
+	}
+
+	return nil
+}
+
+// getWaitlist returns waitlisted people in the order they joined.
+func (s Database) getWaitlist(ctx context.Context, messageID string) ([]entity.User, error) {
+	getWaitlistStmt, err := s.db.PrepareContext(
+		ctx,
+		"SELECT user_id, user_name FROM participants WHERE message_id = $1 AND NOT is_deleted AND is_waitlisted ORDER BY joined_at",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't prepare get waitlist statement: %w", err)
+	}
+	defer getWaitlistStmt.Close()
+
+	rows, err := getWaitlistStmt.QueryContext(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get waitlist of queue %s: %w", messageID, err)
+	}
+	defer rows.Close()
+
+	var waitlist []entity.User
+
+	for rows.Next() {
+		var user entity.User
+		if err = rows.Scan(&user.ID, &user.Name); err != nil {
+			return nil, fmt.Errorf("couldn't scan waitlist row in queue %s: %w", messageID, err)
+		}
+
+		waitlist = append(waitlist, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("couldn't read waitlist of queue %s: %w", messageID, err)
+	}
+
+	return waitlist, nil
+}
@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"QueueBot/internal/entity"
+)
+
+// getFinishedQueuesQuery numbers participants of every queue the user was in the same way GetQueue orders them.
+const getFinishedQueuesQuery = `SELECT q.message_id, q.description, q.current_user_index, q.finished_at, p.position, p.participants
+FROM (SELECT message_id,
+             user_id,
+             row_number() OVER (PARTITION BY message_id ORDER BY order_number NULLS LAST, joined_at) - 1 AS position,
+             count(*) OVER (PARTITION BY message_id)                                                     AS participants
+      FROM participants
+      WHERE is_deleted = 0
+        AND is_waitlisted = 0
+        AND message_id IN (SELECT message_id FROM participants WHERE user_id = ?)) p
+         JOIN queues q ON q.message_id = p.message_id
+WHERE p.user_id = ?
+  AND q.finished_at IS NOT NULL
+ORDER BY q.finished_at DESC
+LIMIT ?`
+
+func (s Database) GetFinishedQueues(ctx context.Context, userID int64, limit int) ([]entity.FinishedQueue, error) {
+	getFinishedStmt, err := s.db.PrepareContext(ctx, getFinishedQueuesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't prepare get finished queues statement: %w", err)
+	}
+	defer getFinishedStmt.Close()
+
+	rows, err := getFinishedStmt.QueryContext(ctx, userID, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get finished queues of user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	var queues []entity.FinishedQueue
+
+	for rows.Next() {
+		var queue entity.FinishedQueue
+		if err = rows.Scan(
+			&queue.MessageID,
+			&queue.Description,
+			&queue.CurrentPersonIdx,
+			&queue.FinishedAt,
+			&queue.Position,
+			&queue.Participants,
+		); err != nil {
+			return nil, fmt.Errorf("couldn't scan finished queue of user %d: %w", userID, err)
+		}
+
+		queues = append(queues, queue)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("couldn't read finished queues of user %d: %w", userID, err)
+	}
+
+	return queues, nil
+}
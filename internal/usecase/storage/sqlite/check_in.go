@@ -0,0 +1,51 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"QueueBot/internal/entity"
+)
+
+// SetCheckInTimeout stores the timeout in whole seconds.
+func (s Database) SetCheckInTimeout(ctx context.Context, messageID string, timeout time.Duration) error {
+	setStmt, err := s.db.PrepareContext(ctx, "UPDATE queues SET check_in_timeout = ? WHERE message_id = ?")
+	if err != nil {
+		return fmt.Errorf("couldn't prepare set check-in timeout statement: %w", err)
+	}
+	defer setStmt.Close()
+
+	result, err := setStmt.ExecContext(ctx, int64(timeout/time.Second), messageID)
+	if err != nil {
+		return fmt.Errorf("couldn't set check-in timeout: %w", err)
+	}
+
+	return checkQueueAffected(result, messageID)
+}
+
+func (s Database) SetCheckIn(ctx context.Context, messageID string, userID int64, checkIn entity.CheckIn) error {
+	setStmt, err := s.db.PrepareContext(
+		ctx,
+		"UPDATE participants SET check_in_asked_at = ?, is_checked_in = ?, missed_check_ins = ? WHERE message_id = ? AND user_id = ?",
+	)
+	if err != nil {
+		return fmt.Errorf("couldn't prepare set check-in statement: %w", err)
+	}
+	defer setStmt.Close()
+
+	_, err = setStmt.ExecContext(ctx, nullTime(checkIn.AskedAt), checkIn.IsConfirmed, checkIn.Missed, messageID, userID)
+	if err != nil {
+		return fmt.Errorf("couldn't set check-in of user %d in queue %s: %w", userID, messageID, err)
+	}
+
+	return nil
+}
+
+func (s Database) GetCheckInQueueIDs(ctx context.Context) ([]string, error) {
+	return s.getQueueIDs(
+		ctx,
+		"check-in",
+		"SELECT message_id FROM queues WHERE finished_at IS NULL AND is_started AND check_in_timeout > 0 ORDER BY message_id",
+	)
+}
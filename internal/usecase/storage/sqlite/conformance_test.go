@@ -0,0 +1,25 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"QueueBot/internal/usecase/storage"
+	"QueueBot/internal/usecase/storage/storagetest"
+)
+
+func TestDatabase_Conformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		db, err := NewDatabase(filepath.Join(t.TempDir(), "queue.db"))
+		require.NoError(t, err)
+
+		t.Cleanup(func() {
+			assert.NoError(t, db.Close())
+		})
+
+		return db
+	})
+}
@@ -1,6 +1,13 @@
 package sqlite
 
-const CreateTables string = `
+import "QueueBot/internal/usecase/storage/migrate"
+
+// migrations must only be appended to. Changing an applied migration won't change existing databases.
+var migrations = []migrate.Migration{
+	{
+		Version: 1,
+		Name:    "create queues and participants",
+		Up: `
 CREATE TABLE IF NOT EXISTS queues
 (
     message_id         TEXT NOT NULL PRIMARY KEY,
@@ -22,4 +29,221 @@ CREATE TABLE IF NOT EXISTS participants
 );
 
 CREATE INDEX IF NOT EXISTS idx_prt_message_id ON participants (message_id, user_id, isDeleted);
-`
+`,
+		Down: `
+DROP TABLE participants;
+DROP TABLE queues;
+`,
+	},
+	{
+		Version: 2,
+		Name:    "rename participants.isDeleted to is_deleted",
+		Up:      `ALTER TABLE participants RENAME COLUMN isDeleted TO is_deleted;`,
+		Down:    `ALTER TABLE participants RENAME COLUMN is_deleted TO isDeleted;`,
+	},
+	{
+		Version: 3,
+		Name:    "add queue owner and admins",
+		Up: `
+ALTER TABLE queues ADD COLUMN owner_id BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE queue_admins
+(
+    message_id TEXT   NOT NULL REFERENCES queues (message_id),
+    user_id    BIGINT NOT NULL,
+    primary key (message_id, user_id)
+);
+`,
+		Down: `
+DROP TABLE queue_admins;
+ALTER TABLE queues DROP COLUMN owner_id;
+`,
+	},
+	{
+		Version: 4,
+		Name:    "add started flag and undo history",
+		Up: `
+ALTER TABLE queues ADD COLUMN is_started INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE queue_history
+(
+    id                 INTEGER PRIMARY KEY AUTOINCREMENT,
+    message_id         TEXT    NOT NULL REFERENCES queues (message_id),
+    operation          TEXT    NOT NULL,
+    current_user_index INTEGER NOT NULL,
+    is_started         INTEGER NOT NULL,
+    created_at         DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_queue_history_message_id ON queue_history (message_id, id);
+
+CREATE TABLE participants_history
+(
+    history_id   INTEGER NOT NULL REFERENCES queue_history (id),
+    user_id      BIGINT  NOT NULL,
+    user_name    VARCHAR NOT NULL,
+    joined_at    DATETIME,
+    order_number INTEGER,
+    is_deleted   INTEGER NOT NULL,
+    primary key (history_id, user_id)
+);
+`,
+		Down: `
+DROP TABLE participants_history;
+DROP TABLE queue_history;
+ALTER TABLE queues DROP COLUMN is_started;
+`,
+	},
+	{
+		Version: 5,
+		Name:    "add queue events",
+		Up: `
+CREATE TABLE queue_events
+(
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    message_id TEXT    NOT NULL,
+    operation  TEXT    NOT NULL,
+    user_id    BIGINT  NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
+
+CREATE INDEX idx_queue_events_message_id ON queue_events (message_id, id);
+`,
+		Down: `DROP TABLE queue_events;`,
+	},
+	{
+		Version: 6,
+		Name:    "archive finished queues",
+		Up:      `ALTER TABLE queues ADD COLUMN finished_at DATETIME;`,
+		Down:    `ALTER TABLE queues DROP COLUMN finished_at;`,
+	},
+	{
+		Version: 7,
+		Name:    "add turn notifications",
+		Up: `
+ALTER TABLE queues ADD COLUMN notify_count INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE subscribers
+(
+    user_id       BIGINT PRIMARY KEY,
+    subscribed_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+`,
+		Down: `
+DROP TABLE subscribers;
+ALTER TABLE queues DROP COLUMN notify_count;
+`,
+	},
+	{
+		Version: 8,
+		Name:    "add queue language",
+		Up:      `ALTER TABLE queues ADD COLUMN language TEXT NOT NULL DEFAULT 'ru';`,
+		Down:    `ALTER TABLE queues DROP COLUMN language;`,
+	},
+	{
+		Version: 9,
+		Name:    "add queue capacity and waitlist",
+		Up: `
+ALTER TABLE queues ADD COLUMN capacity INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE participants ADD COLUMN is_waitlisted INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE participants_history ADD COLUMN is_waitlisted INTEGER NOT NULL DEFAULT 0;
+`,
+		Down: `
+ALTER TABLE participants_history DROP COLUMN is_waitlisted;
+ALTER TABLE participants DROP COLUMN is_waitlisted;
+ALTER TABLE queues DROP COLUMN capacity;
+`,
+	},
+	{
+		Version: 10,
+		Name:    "add timed turns",
+		Up: `
+ALTER TABLE queues ADD COLUMN turn_duration INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE queues ADD COLUMN turn_user_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE queues ADD COLUMN turn_started_at DATETIME;
+ALTER TABLE queues ADD COLUMN turn_is_warned INTEGER NOT NULL DEFAULT 0;
+`,
+		Down: `
+ALTER TABLE queues DROP COLUMN turn_is_warned;
+ALTER TABLE queues DROP COLUMN turn_started_at;
+ALTER TABLE queues DROP COLUMN turn_user_id;
+ALTER TABLE queues DROP COLUMN turn_duration;
+`,
+	},
+	{
+		Version: 11,
+		Name:    "add queue schedule",
+		Up: `
+ALTER TABLE queues ADD COLUMN opens_at DATETIME;
+ALTER TABLE queues ADD COLUMN starts_at DATETIME;
+ALTER TABLE queues ADD COLUMN is_shuffle INTEGER NOT NULL DEFAULT 0;
+`,
+		Down: `
+ALTER TABLE queues DROP COLUMN is_shuffle;
+ALTER TABLE queues DROP COLUMN starts_at;
+ALTER TABLE queues DROP COLUMN opens_at;
+`,
+	},
+	{
+		Version: 12,
+		Name:    "add queue templates",
+		Up: `
+CREATE TABLE templates
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    owner_id    BIGINT  NOT NULL,
+    description TEXT    NOT NULL,
+    capacity    INTEGER NOT NULL DEFAULT 0,
+    opens_at    TEXT    NOT NULL DEFAULT '',
+    starts_at   TEXT    NOT NULL DEFAULT '',
+    is_shuffle  INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_templates_owner_id ON templates (owner_id);
+
+ALTER TABLE queues ADD COLUMN template_id BIGINT;
+CREATE INDEX idx_queues_template_id ON queues (template_id);
+`,
+		Down: `
+DROP INDEX idx_queues_template_id;
+ALTER TABLE queues DROP COLUMN template_id;
+DROP TABLE templates;
+`,
+	},
+	{
+		Version: 13,
+		Name:    "add service slots",
+		Up: `
+ALTER TABLE queues ADD COLUMN slot_count INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE participants ADD COLUMN slot INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE participants_history ADD COLUMN slot INTEGER NOT NULL DEFAULT 0;
+`,
+		Down: `
+ALTER TABLE participants_history DROP COLUMN slot;
+ALTER TABLE participants DROP COLUMN slot;
+ALTER TABLE queues DROP COLUMN slot_count;
+`,
+	},
+	{
+		Version: 14,
+		Name:    "add check-in",
+		Up: `
+ALTER TABLE queues ADD COLUMN check_in_timeout INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE participants ADD COLUMN check_in_asked_at DATETIME;
+ALTER TABLE participants ADD COLUMN is_checked_in INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE participants ADD COLUMN missed_check_ins INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE participants_history ADD COLUMN check_in_asked_at DATETIME;
+ALTER TABLE participants_history ADD COLUMN is_checked_in INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE participants_history ADD COLUMN missed_check_ins INTEGER NOT NULL DEFAULT 0;
+`,
+		Down: `
+ALTER TABLE participants_history DROP COLUMN missed_check_ins;
+ALTER TABLE participants_history DROP COLUMN is_checked_in;
+ALTER TABLE participants_history DROP COLUMN check_in_asked_at;
+ALTER TABLE participants DROP COLUMN missed_check_ins;
+ALTER TABLE participants DROP COLUMN is_checked_in;
+ALTER TABLE participants DROP COLUMN check_in_asked_at;
+ALTER TABLE queues DROP COLUMN check_in_timeout;
+`,
+	},
+}
@@ -0,0 +1,60 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"QueueBot/internal/entity"
+)
+
+// AddEvent isn't linked to queues with a foreign key, so the log stays after the queue is finished.
+func (s Database) AddEvent(ctx context.Context, messageID string, operation entity.Operation, userID int64) error {
+	addStmt, err := s.db.PrepareContext(ctx, "INSERT INTO queue_events (message_id, operation, user_id) VALUES (?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("couldn't prepare add event statement: %w", err)
+	}
+	defer addStmt.Close()
+
+	if _, err = addStmt.ExecContext(ctx, messageID, string(operation), userID); err != nil {
+		return fmt.Errorf("couldn't add %s event to queue %s: %w", operation, messageID, err)
+	}
+
+	return nil
+}
+
+func (s Database) GetEvents(ctx context.Context, messageID string, limit int) ([]entity.Event, error) {
+	getEventsStmt, err := s.db.PrepareContext(
+		ctx,
+		`SELECT e.operation, e.user_id, COALESCE(p.user_name, ''), e.created_at
+		FROM queue_events e
+		LEFT JOIN participants p ON p.message_id = e.message_id AND p.user_id = e.user_id
+		WHERE e.message_id = ? ORDER BY e.id DESC LIMIT ?`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't prepare get events statement: %w", err)
+	}
+	defer getEventsStmt.Close()
+
+	rows, err := getEventsStmt.QueryContext(ctx, messageID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get events of queue %s: %w", messageID, err)
+	}
+	defer rows.Close()
+
+	var events []entity.Event
+
+	for rows.Next() {
+		var event entity.Event
+		if err = rows.Scan(&event.Operation, &event.UserID, &event.UserName, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("couldn't scan event of queue %s: %w", messageID, err)
+		}
+
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("couldn't read events of queue %s: %w", messageID, err)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,87 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase/storage/migrate"
+)
+
+// legacySchema is what CreateTables used to create on every start before migrations were introduced.
+const legacySchema = `
+CREATE TABLE IF NOT EXISTS queues
+(
+    message_id         TEXT NOT NULL PRIMARY KEY,
+    description        TEXT    DEFAULT NULL,
+    current_user_index INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS participants
+(
+    message_id   TEXT NOT NULL REFERENCES queues (message_id),
+    user_id      BIGINT  NOT NULL,
+    user_name    VARCHAR NOT NULL,
+    joined_at    DATETIME DEFAULT CURRENT_TIMESTAMP,
+    order_number INTEGER,
+    isDeleted   INTEGER NOT NULL DEFAULT 0,
+    primary key (message_id, user_id)
+);
+
+INSERT INTO queues (message_id, description) VALUES ('123', 'Legacy');
+INSERT INTO participants (message_id, user_id, user_name) VALUES ('123', 1, 'User1');
+`
+
+func TestMigrations_UpgradeLegacyDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.db")
+
+	legacy, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	_, err = legacy.Exec(legacySchema)
+	require.NoError(t, err)
+	require.NoError(t, legacy.Close())
+
+	db, err := NewDatabase(path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	version, err := migrate.Version(context.Background(), db.db)
+	require.NoError(t, err)
+	assert.Equal(t, len(migrations), version)
+
+	queue, err := db.GetQueue(context.Background(), "123")
+	require.NoError(t, err)
+	assert.Equal(t, "Legacy", queue.Description)
+	assert.Equal(t, []entity.User{{ID: 1, Name: "User1"}}, queue.Users)
+}
+
+func TestMigrations_UpAndDown(t *testing.T) {
+	db, err := NewDatabase(filepath.Join(t.TempDir(), "queue.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, migrate.Down(context.Background(), db.db, migrations, 0))
+	require.NoError(t, migrate.Up(context.Background(), db.db, migrations))
+
+	require.NoError(t, db.CreateQueue(context.Background(), "123", "Test", 1, "ru", 0))
+	require.NoError(t, db.LogInOutToQueue(context.Background(), "123", entity.User{ID: 1, Name: "User1"}))
+}
+
+func TestMigrations_RefuseNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.db")
+
+	db, err := NewDatabase(path)
+	require.NoError(t, err)
+
+	_, err = db.db.Exec("INSERT INTO schema_version (version) VALUES ($1)", len(migrations)+1)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	_, err = NewDatabase(path)
+	assert.ErrorIs(t, err, migrate.ErrSchemaTooNew)
+}
@@ -0,0 +1,64 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+func (s Database) SetNotifyCount(ctx context.Context, messageID string, count int) error {
+	setStmt, err := s.db.PrepareContext(ctx, "UPDATE queues SET notify_count = ? WHERE message_id = ?")
+	if err != nil {
+		return fmt.Errorf("couldn't prepare set notify count statement: %w", err)
+	}
+	defer setStmt.Close()
+
+	result, err := setStmt.ExecContext(ctx, count, messageID)
+	if err != nil {
+		return fmt.Errorf("couldn't set notify count: %w", err)
+	}
+
+	return checkQueueAffected(result, messageID)
+}
+
+func (s Database) AddSubscriber(ctx context.Context, userID int64) error {
+	addStmt, err := s.db.PrepareContext(ctx, "INSERT INTO subscribers (user_id) VALUES (?) ON CONFLICT DO NOTHING")
+	if err != nil {
+		return fmt.Errorf("couldn't prepare add subscriber statement: %w", err)
+	}
+	defer addStmt.Close()
+
+	if _, err = addStmt.ExecContext(ctx, userID); err != nil {
+		return fmt.Errorf("couldn't add subscriber %d: %w", userID, err)
+	}
+
+	return nil
+}
+
+func (s Database) RemoveSubscriber(ctx context.Context, userID int64) error {
+	removeStmt, err := s.db.PrepareContext(ctx, "DELETE FROM subscribers WHERE user_id = ?")
+	if err != nil {
+		return fmt.Errorf("couldn't prepare remove subscriber statement: %w", err)
+	}
+	defer removeStmt.Close()
+
+	if _, err = removeStmt.ExecContext(ctx, userID); err != nil {
+		return fmt.Errorf("couldn't remove subscriber %d: %w", userID, err)
+	}
+
+	return nil
+}
+
+func (s Database) IsSubscriber(ctx context.Context, userID int64) (bool, error) {
+	isSubscriberStmt, err := s.db.PrepareContext(ctx, "SELECT EXISTS (SELECT 1 FROM subscribers WHERE user_id = ?)")
+	if err != nil {
+		return false, fmt.Errorf("couldn't prepare is subscriber statement: %w", err)
+	}
+	defer isSubscriberStmt.Close()
+
+	var isSubscriber bool
+	if err = isSubscriberStmt.QueryRowContext(ctx, userID).Scan(&isSubscriber); err != nil {
+		return false, fmt.Errorf("couldn't check subscriber %d: %w", userID, err)
+	}
+
+	return isSubscriber, nil
+}
@@ -3,37 +3,61 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	// Sqlite driver...
 	_ "github.com/mattn/go-sqlite3"
 
 	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase/storage"
+	"QueueBot/internal/usecase/storage/migrate"
 )
 
 type Database struct {
 	db *sql.DB
 }
 
+func (s Database) Ping(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("couldn't ping database: %w", err)
+	}
+
+	return nil
+}
+
 func (s Database) Close() error {
 	return s.db.Close()
 }
 
-func (s Database) CreateQueue(ctx context.Context, messageID string, description string) error {
-	createQueueStmt, err := s.db.PrepareContext(ctx, "INSERT INTO queues (message_id, description) VALUES (?, ?)")
+func (s Database) CreateQueue(
+	ctx context.Context,
+	messageID string,
+	description string,
+	ownerID int64,
+	language string,
+	capacity int,
+) error {
+	createQueueStmt, err := s.db.PrepareContext(
+		ctx,
+		"INSERT INTO queues (message_id, description, owner_id, language, capacity) VALUES (?, ?, ?, ?, ?)",
+	)
 	if err != nil {
 		return fmt.Errorf("couldn't prepare create queue statement: %w", err)
 	}
 	defer createQueueStmt.Close()
 
-	_, err = createQueueStmt.ExecContext(ctx, messageID, description)
+	_, err = createQueueStmt.ExecContext(ctx, messageID, description, ownerID, language, capacity)
 
 	return err
 }
 
 func (s Database) LogInOutToQueue(ctx context.Context, messageID string, user entity.User) error {
-	logInOutStmt, err := s.db.PrepareContext(ctx, `INSERT INTO participants(message_id, user_id, user_name)
-	VALUES (?, ?, ?) on conflict do update set isDeleted=not isDeleted, joined_at=CURRENT_TIMESTAMP`)
+	// joined_at is stored with milliseconds, so people who join within the same second keep their order.
+	logInOutStmt, err := s.db.PrepareContext(ctx, `INSERT INTO participants(message_id, user_id, user_name, joined_at)
+	VALUES (?, ?, ?, strftime('%Y-%m-%d %H:%M:%f', 'now'))
+	on conflict do update set is_deleted=not is_deleted, is_waitlisted=0, slot=0, check_in_asked_at=NULL, is_checked_in=0, missed_check_ins=0, joined_at=strftime('%Y-%m-%d %H:%M:%f', 'now')`)
 	if err != nil {
 		return fmt.Errorf("couldn't prepare log in/out to queue statement: %w", err)
 	}
@@ -47,7 +71,7 @@ func (s Database) LogInOutToQueue(ctx context.Context, messageID string, user en
 func (s Database) GetQueue(ctx context.Context, messageID string) (entity.Queue, error) {
 	descriptionStmt, err := s.db.PrepareContext(
 		ctx,
-		"SELECT description, current_user_index FROM queues WHERE message_id = ?",
+		"SELECT description, current_user_index, owner_id, is_started, notify_count, language, capacity, turn_duration, turn_user_id, turn_started_at, turn_is_warned, opens_at, starts_at, is_shuffle, template_id, slot_count, check_in_timeout FROM queues WHERE message_id = ? AND finished_at IS NULL",
 	)
 	if err != nil {
 		return entity.Queue{}, fmt.Errorf("couldn't prepare get queue description statement: %w", err)
@@ -56,7 +80,7 @@ func (s Database) GetQueue(ctx context.Context, messageID string) (entity.Queue,
 
 	getUsersStmt, err := s.db.PrepareContext(
 		ctx,
-		"SELECT user_id, user_name FROM participants WHERE message_id = ? and isDeleted = 0 ORDER BY order_number",
+		"SELECT user_id, user_name, slot, check_in_asked_at, is_checked_in, missed_check_ins FROM participants WHERE message_id = ? and is_deleted = 0 AND is_waitlisted = 0 ORDER BY order_number NULLS LAST, joined_at",
 	)
 	if err != nil {
 		return entity.Queue{}, fmt.Errorf("couldn't prepare get queue users statement: %w", err)
@@ -65,11 +89,37 @@ func (s Database) GetQueue(ctx context.Context, messageID string) (entity.Queue,
 
 	var description string
 	var currentUserIndex int
+	var ownerID int64
+	var isStarted bool
+	var notifyCount int
+	var language string
+	var capacity int
+	var turnSeconds int64
+	var turn entity.Turn
+	var turnStartedAt sql.NullTime
+	var schedule entity.Schedule
+	var opensAt, startsAt sql.NullTime
+	var templateID sql.NullInt64
+	var slotCount int
+	var checkInSeconds int64
 	queryResult := descriptionStmt.QueryRowContext(ctx, messageID)
-	if err = queryResult.Scan(&description, &currentUserIndex); err != nil {
+	err = queryResult.Scan(
+		&description, &currentUserIndex, &ownerID, &isStarted, &notifyCount, &language, &capacity,
+		&turnSeconds, &turn.UserID, &turnStartedAt, &turn.IsWarned, &opensAt, &startsAt, &schedule.IsShuffle,
+		&templateID, &slotCount, &checkInSeconds,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Queue{}, fmt.Errorf("couldn't find queue %s: %w", messageID, storage.ErrQueueNotFound)
+		}
+
 		return entity.Queue{}, fmt.Errorf("couldn't scan description row in queue %s: %w", messageID, err)
 	}
 
+	turn.StartedAt = turnStartedAt.Time
+	schedule.OpensAt = opensAt.Time
+	schedule.StartsAt = startsAt.Time
+
 	rows, err := getUsersStmt.QueryContext(ctx, messageID)
 	if err != nil {
 		return entity.Queue{}, fmt.Errorf("couldn't get users from queue %s: %w", messageID, err)
@@ -78,35 +128,179 @@ func (s Database) GetQueue(ctx context.Context, messageID string) (entity.Queue,
 
 	var users []entity.User
 
+	var slots []entity.User
+	if slotCount > 1 {
+		slots = make([]entity.User, slotCount)
+	}
+
+	var checkIns map[int64]entity.CheckIn
+
 	for rows.Next() {
 		var user entity.User
-		if err = rows.Scan(&user.ID, &user.Name); err != nil {
+		var slot int
+		var checkIn entity.CheckIn
+		var checkInAskedAt sql.NullTime
+		err = rows.Scan(&user.ID, &user.Name, &slot, &checkInAskedAt, &checkIn.IsConfirmed, &checkIn.Missed)
+		if err != nil {
 			return entity.Queue{}, fmt.Errorf("couldn't scan user row in queue %s: %w", messageID, err)
 		}
 		users = append(users, user)
+
+		checkIn.AskedAt = checkInAskedAt.Time
+		if !checkIn.IsZero() {
+			if checkIns == nil {
+				checkIns = make(map[int64]entity.CheckIn)
+			}
+
+			checkIns[user.ID] = checkIn
+		}
+
+		// Slots beyond SlotCount are left after the count was lowered, they aren't shown.
+		if slot > 0 && slot <= len(slots) {
+			slots[slot-1] = user
+		}
 	}
 
 	if err := rows.Err(); err != nil {
 		return entity.Queue{}, fmt.Errorf("error during iterating rows from queue %s: %w", messageID, err)
 	}
 
+	adminIDs, err := s.getAdmins(ctx, messageID)
+	if err != nil {
+		return entity.Queue{}, err
+	}
+
+	waitlist, err := s.getWaitlist(ctx, messageID)
+	if err != nil {
+		return entity.Queue{}, err
+	}
+
 	return entity.Queue{
 		MessageID:        messageID,
 		Description:      description,
 		Users:            users,
 		CurrentPersonIdx: currentUserIndex,
+		OwnerID:          ownerID,
+		AdminIDs:         adminIDs,
+		IsStarted:        isStarted,
+		NotifyCount:      notifyCount,
+		Language:         language,
+		Capacity:         capacity,
+		Waitlist:         waitlist,
+		TurnDuration:     time.Duration(turnSeconds) * time.Second,
+		Turn:             turn,
+		Schedule:         schedule,
+		TemplateID:       templateID.Int64,
+		SlotCount:        slotCount,
+		Slots:            slots,
+		CheckInTimeout:   time.Duration(checkInSeconds) * time.Second,
+		CheckIns:         checkIns,
 	}, nil
 }
 
+func (s Database) getAdmins(ctx context.Context, messageID string) ([]int64, error) {
+	getAdminsStmt, err := s.db.PrepareContext(ctx, "SELECT user_id FROM queue_admins WHERE message_id = ? ORDER BY user_id")
+	if err != nil {
+		return nil, fmt.Errorf("couldn't prepare get queue admins statement: %w", err)
+	}
+	defer getAdminsStmt.Close()
+
+	rows, err := getAdminsStmt.QueryContext(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get admins of queue %s: %w", messageID, err)
+	}
+	defer rows.Close()
+
+	var adminIDs []int64
+
+	for rows.Next() {
+		var adminID int64
+		if err = rows.Scan(&adminID); err != nil {
+			return nil, fmt.Errorf("couldn't scan admin row in queue %s: %w", messageID, err)
+		}
+		adminIDs = append(adminIDs, adminID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during iterating admin rows from queue %s: %w", messageID, err)
+	}
+
+	return adminIDs, nil
+}
+
+func (s Database) AddAdmin(ctx context.Context, messageID string, userID int64) error {
+	addAdminStmt, err := s.db.PrepareContext(ctx, "INSERT INTO queue_admins (message_id, user_id) VALUES (?, ?) ON CONFLICT DO NOTHING")
+	if err != nil {
+		return fmt.Errorf("couldn't prepare add admin statement: %w", err)
+	}
+	defer addAdminStmt.Close()
+
+	if _, err = addAdminStmt.ExecContext(ctx, messageID, userID); err != nil {
+		return fmt.Errorf("couldn't add admin to queue %s: %w", messageID, err)
+	}
+
+	return nil
+}
+
+func (s Database) RemoveAdmin(ctx context.Context, messageID string, userID int64) error {
+	removeAdminStmt, err := s.db.PrepareContext(ctx, "DELETE FROM queue_admins WHERE message_id = ? AND user_id = ?")
+	if err != nil {
+		return fmt.Errorf("couldn't prepare remove admin statement: %w", err)
+	}
+	defer removeAdminStmt.Close()
+
+	if _, err = removeAdminStmt.ExecContext(ctx, messageID, userID); err != nil {
+		return fmt.Errorf("couldn't remove admin from queue %s: %w", messageID, err)
+	}
+
+	return nil
+}
+
+func (s Database) GetAdminQueueIDs(ctx context.Context, userID int64) ([]string, error) {
+	getStmt, err := s.db.PrepareContext(
+		ctx,
+		`SELECT message_id FROM queues WHERE finished_at IS NULL AND
+		(owner_id = ? OR message_id IN (SELECT message_id FROM queue_admins WHERE user_id = ?))
+		ORDER BY message_id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't prepare get admin queues statement: %w", err)
+	}
+	defer getStmt.Close()
+
+	rows, err := getStmt.QueryContext(ctx, userID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get queues of admin %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	var messageIDs []string
+
+	for rows.Next() {
+		var messageID string
+		if err = rows.Scan(&messageID); err != nil {
+			return nil, fmt.Errorf("couldn't scan queue of admin %d: %w", userID, err)
+		}
+
+		messageIDs = append(messageIDs, messageID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("couldn't read queues of admin %d: %w", userID, err)
+	}
+
+	return messageIDs, nil
+}
+
 func setParticipantsOrder(ctx context.Context, tx *sql.Tx, messageID string, isShuffle bool) (err error) {
 	var startStmt *sql.Stmt
 	if isShuffle {
 		startStmt, err = tx.PrepareContext(ctx, `UPDATE participants
 														SET order_number = random()
-														WHERE message_id = ?;`)
+														WHERE message_id = ? AND is_waitlisted = 0;`)
 	} else {
 		startStmt, err = tx.PrepareContext(ctx, `UPDATE participants SET order_number = dense_rank FROM 
-                                                      (SELECT dense_rank() OVER (ORDER BY joined_at) AS dense_rank, user_id FROM participants WHERE message_id = $1)
+                                                      (SELECT dense_rank() OVER (ORDER BY joined_at) AS dense_rank, user_id FROM participants WHERE message_id = $1 AND is_waitlisted = 0)
                                                           AS sub WHERE participants.user_id = sub.user_id AND message_id = $1`)
 	}
 
@@ -129,7 +323,7 @@ func (s Database) StartQueue(ctx context.Context, messageID string, isShuffle bo
 		return fmt.Errorf("couldn't begin transaction: %w", err)
 	}
 
-	setCurrentUserIndexStmt, err := tx.PrepareContext(ctx, "UPDATE queues SET current_user_index = 0 WHERE message_id = ?")
+	setCurrentUserIndexStmt, err := tx.PrepareContext(ctx, "UPDATE queues SET current_user_index = 0, is_started = TRUE WHERE message_id = ?")
 	if err != nil {
 		if txErr := tx.Rollback(); txErr != nil {
 			return fmt.Errorf("couldn't prepare set current user index statement: %w, unable to rollback: %w", err, txErr)
@@ -139,7 +333,11 @@ func (s Database) StartQueue(ctx context.Context, messageID string, isShuffle bo
 	}
 	defer setCurrentUserIndexStmt.Close()
 
-	_, err = setCurrentUserIndexStmt.ExecContext(ctx, messageID)
+	result, err := setCurrentUserIndexStmt.ExecContext(ctx, messageID)
+	if err == nil {
+		err = checkQueueAffected(result, messageID)
+	}
+
 	if err != nil {
 		if txErr := tx.Rollback(); txErr != nil {
 			return fmt.Errorf("couldn't set current user index: %w, unable to rollback: %w", err, txErr)
@@ -175,74 +373,90 @@ func (s Database) IncrementCurrentPerson(ctx context.Context, messageID string)
 	}
 	defer incrementStmt.Close()
 
-	_, err = incrementStmt.ExecContext(ctx, messageID)
+	result, err := incrementStmt.ExecContext(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("couldn't increment current person: %w", err)
+	}
 
-	return err
+	return checkQueueAffected(result, messageID)
 }
 
-func (s Database) DeleteQueue(ctx context.Context, messageID string) error {
-	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{})
-	if err != nil {
-		return fmt.Errorf("couldn't begin transaction: %w", err)
-	}
+// archiveQueueStatements forget undo history of a queue and mark it finished.
+// Participants, admins and current person are kept as a record of the finished queue.
+// The queue goes last, so its result tells whether the queue was found.
+var archiveQueueStatements = []struct {
+	name  string
+	query string
+}{
+	{name: "participants history", query: "DELETE FROM participants_history WHERE history_id IN (SELECT id FROM queue_history WHERE message_id = ?)"},
+	{name: "queue history", query: "DELETE FROM queue_history WHERE message_id = ?"},
+	{name: "queue", query: "UPDATE queues SET finished_at = strftime('%Y-%m-%d %H:%M:%f', 'now') WHERE message_id = ? AND finished_at IS NULL"},
+}
 
-	deleteQueueStmt, err := tx.PrepareContext(ctx, "DELETE FROM queues WHERE message_id = ?")
-	if err != nil {
-		if txErr := tx.Rollback(); txErr != nil {
-			return fmt.Errorf("couldn't prepare finish queue statement: %w, unable to rollback: %w", err, txErr)
-		}
+func (s Database) ArchiveQueue(ctx context.Context, messageID string) error {
+	return runInTx(ctx, s.db, func(tx *sql.Tx) error {
+		var result sql.Result
 
-		return fmt.Errorf("couldn't prepare finish queue statement: %w", err)
-	}
-	defer deleteQueueStmt.Close()
+		for _, statement := range archiveQueueStatements {
+			archiveStmt, err := tx.PrepareContext(ctx, statement.query)
+			if err != nil {
+				return fmt.Errorf("couldn't prepare archive %s statement: %w", statement.name, err)
+			}
+			defer archiveStmt.Close()
 
-	_, err = deleteQueueStmt.ExecContext(ctx, messageID)
-	if err != nil {
-		if txErr := tx.Rollback(); txErr != nil {
-			return fmt.Errorf("couldn't finish queue: %w, unable to rollback: %w", err, txErr)
+			if result, err = archiveStmt.ExecContext(ctx, messageID); err != nil {
+				return fmt.Errorf("couldn't archive %s: %w", statement.name, err)
+			}
 		}
 
-		return fmt.Errorf("couldn't finish queue: %w", err)
-	}
+		return checkQueueAffected(result, messageID)
+	})
+}
 
-	deleteParticipantsStmt, err := tx.PrepareContext(ctx, "DELETE FROM participants WHERE message_id = ?")
+// runInTx runs f in a transaction and commits it if f succeeds.
+func runInTx(ctx context.Context, db *sql.DB, f func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{})
 	if err != nil {
-		if txErr := tx.Rollback(); txErr != nil {
-			return fmt.Errorf("couldn't prepare delete participants statement: %w, unable to rollback: %w", err, txErr)
-		}
-
-		return fmt.Errorf("couldn't prepare delete participants statement: %w", err)
+		return fmt.Errorf("couldn't begin transaction: %w", err)
 	}
-	defer deleteParticipantsStmt.Close()
 
-	_, err = deleteParticipantsStmt.ExecContext(ctx, messageID)
-	if err != nil {
+	if err = f(tx); err != nil {
 		if txErr := tx.Rollback(); txErr != nil {
-			return fmt.Errorf("couldn't delete participants: %w, unable to rollback: %w", err, txErr)
+			return fmt.Errorf("%w, unable to rollback: %w", err, txErr)
 		}
 
-		return fmt.Errorf("couldn't delete participants: %w", err)
+		return err
 	}
 
 	if err = tx.Commit(); err != nil {
-		if txErr := tx.Rollback(); txErr != nil {
-			return fmt.Errorf("couldn't commit transaction: %w, unable to rollback: %w", err, txErr)
-		}
-
 		return fmt.Errorf("couldn't commit transaction: %w", err)
 	}
 
 	return nil
 }
 
+// checkQueueAffected reports storage.ErrQueueNotFound when an update on queues table matched nothing.
+func checkQueueAffected(result sql.Result, messageID string) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("couldn't get affected rows: %w", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("couldn't find queue %s: %w", messageID, storage.ErrQueueNotFound)
+	}
+
+	return nil
+}
+
 func NewDatabase(databasePath string) (*Database, error) {
-	db, err := sql.Open("sqlite3", fmt.Sprintf("%s?cache=shared", databasePath))
+	db, err := sql.Open("sqlite3", fmt.Sprintf("%s?cache=shared&_foreign_keys=on", databasePath))
 	if err != nil {
 		return nil, fmt.Errorf("couldn't open database: %w", err)
 	}
 
-	if _, err := db.Exec(CreateTables); err != nil {
-		return nil, fmt.Errorf("couldn't create default sqlite tables: %w", err)
+	if err := migrate.Up(context.Background(), db, migrations); err != nil {
+		return nil, fmt.Errorf("couldn't migrate sqlite database: %w", err)
 	}
 
 	return &Database{
@@ -255,3 +469,18 @@ func NewDatabaseFromDB(db *sql.DB) *Database {
 		db: db,
 	}
 }
+
+func (s Database) SetLanguage(ctx context.Context, messageID string, language string) error {
+	setStmt, err := s.db.PrepareContext(ctx, "UPDATE queues SET language = ? WHERE message_id = ?")
+	if err != nil {
+		return fmt.Errorf("couldn't prepare set language statement: %w", err)
+	}
+	defer setStmt.Close()
+
+	result, err := setStmt.ExecContext(ctx, language, messageID)
+	if err != nil {
+		return fmt.Errorf("couldn't set language: %w", err)
+	}
+
+	return checkQueueAffected(result, messageID)
+}
@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"errors"
 	"reflect"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -25,6 +27,9 @@ func TestDatabase_CreateQueue(t *testing.T) {
 	type args struct {
 		messageID   string
 		description string
+		ownerID     int64
+		language    string
+		capacity    int
 	}
 
 	type mockBehaviour func(args args)
@@ -40,13 +45,16 @@ func TestDatabase_CreateQueue(t *testing.T) {
 			args: args{
 				messageID:   "123",
 				description: "Test",
+				ownerID:     1,
+				language:    "en",
+				capacity:    15,
 			},
 			mockBehaviour: func(args args) {
 				mock.ExpectPrepare("INSERT INTO queues").WillBeClosed()
 
 				mock.
 					ExpectExec("INSERT INTO queues").
-					WithArgs("123", "Test").
+					WithArgs("123", "Test", 1, "en", 15).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			wantErr: false,
@@ -56,13 +64,15 @@ func TestDatabase_CreateQueue(t *testing.T) {
 			args: args{
 				messageID:   "1234",
 				description: "Test",
+				ownerID:     1,
+				language:    "ru",
 			},
 			mockBehaviour: func(args args) {
 				mock.ExpectPrepare("INSERT INTO queues").WillBeClosed()
 
 				mock.
 					ExpectExec("INSERT INTO queues").
-					WithArgs("1234", "Test").
+					WithArgs("1234", "Test", 1, "ru", 0).
 					WillReturnError(errAlreadyExists)
 			},
 			wantErr: true,
@@ -72,7 +82,14 @@ func TestDatabase_CreateQueue(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehaviour(tt.args)
 
-			err := db.CreateQueue(context.Background(), tt.args.messageID, tt.args.description)
+			err := db.CreateQueue(
+				context.Background(),
+				tt.args.messageID,
+				tt.args.description,
+				tt.args.ownerID,
+				tt.args.language,
+				tt.args.capacity,
+			)
 			if tt.wantErr && err == nil {
 				t.Errorf("Expected CreateQueue() to return error = %v, returned %v", tt.wantErr, err)
 			}
@@ -86,7 +103,7 @@ func TestDatabase_CreateQueue(t *testing.T) {
 	}
 }
 
-func TestDatabase_DeleteQueue(t *testing.T) {
+func TestDatabase_ArchiveQueue(t *testing.T) {
 	mockDB, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 
@@ -112,15 +129,12 @@ func TestDatabase_DeleteQueue(t *testing.T) {
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				mock.ExpectPrepare("DELETE FROM queues WHERE message_id = ?").WillBeClosed()
-				mock.ExpectExec("DELETE FROM queues WHERE message_id = ?").
-					WithArgs(args.messageID).
-					WillReturnResult(sqlmock.NewResult(1, 1))
-
-				mock.ExpectPrepare("DELETE FROM participants WHERE message_id = ?").WillBeClosed()
-				mock.ExpectExec("DELETE FROM participants WHERE message_id = ?").
-					WithArgs(args.messageID).
-					WillReturnResult(sqlmock.NewResult(1, 1))
+				for _, statement := range archiveQueueStatements {
+					mock.ExpectPrepare(regexp.QuoteMeta(statement.query)).WillBeClosed()
+					mock.ExpectExec(regexp.QuoteMeta(statement.query)).
+						WithArgs(args.messageID).
+						WillReturnResult(sqlmock.NewResult(1, 1))
+				}
 
 				mock.ExpectCommit()
 			},
@@ -130,8 +144,8 @@ func TestDatabase_DeleteQueue(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockBehaviour(tt.args)
 
-			if err := db.DeleteQueue(context.Background(), tt.args.messageID); (err != nil) != tt.wantErr {
-				t.Errorf("DeleteQueue() error = %v, wantErr %v", err, tt.wantErr)
+			if err := db.ArchiveQueue(context.Background(), tt.args.messageID); (err != nil) != tt.wantErr {
+				t.Errorf("ArchiveQueue() error = %v, wantErr %v", err, tt.wantErr)
 			}
 
 			assert.NoError(t, mock.ExpectationsWereMet())
@@ -173,24 +187,53 @@ func TestDatabase_GetQueue(t *testing.T) {
 						Name: "Test",
 					},
 				},
+				OwnerID:        1,
+				AdminIDs:       []int64{2},
+				IsStarted:      true,
+				NotifyCount:    2,
+				Language:       "en",
+				Capacity:       1,
+				Waitlist:       []entity.User{{ID: 3, Name: "Waiting"}},
+				TurnDuration:   7 * time.Minute,
+				Turn:           entity.Turn{UserID: 1},
+				Schedule:       entity.Schedule{IsShuffle: true},
+				TemplateID:     7,
+				SlotCount:      2,
+				Slots:          []entity.User{{}, {ID: 1, Name: "Test"}},
+				CheckInTimeout: 5 * time.Minute,
+				CheckIns:       map[int64]entity.CheckIn{1: {IsConfirmed: true, Missed: 1}},
 			},
 			mockBehaviour: func(args args) {
-				mock.ExpectPrepare("SELECT description, current_user_index FROM queues WHERE message_id = ?").WillBeClosed()
-				mock.ExpectPrepare("SELECT user_id, user_name FROM participants WHERE message_id = ? and isDeleted = 0 ORDER BY order_number").WillBeClosed()
+				mock.ExpectPrepare("SELECT description, current_user_index, owner_id, is_started, notify_count, language, capacity, turn_duration, turn_user_id, turn_started_at, turn_is_warned, opens_at, starts_at, is_shuffle, template_id, slot_count, check_in_timeout FROM queues WHERE message_id = ? AND finished_at IS NULL").WillBeClosed()
+				mock.ExpectPrepare("SELECT user_id, user_name, slot, check_in_asked_at, is_checked_in, missed_check_ins FROM participants WHERE message_id = ? and is_deleted = 0 AND is_waitlisted = 0 ORDER BY order_number NULLS LAST, joined_at").WillBeClosed()
 
-				rows := sqlmock.NewRows([]string{"description", "current_user_index"}).
-					AddRow("Test", 0)
+				rows := sqlmock.NewRows([]string{
+					"description", "current_user_index", "owner_id", "is_started", "notify_count", "language", "capacity",
+					"turn_duration", "turn_user_id", "turn_started_at", "turn_is_warned", "opens_at", "starts_at", "is_shuffle", "template_id",
+					"slot_count", "check_in_timeout",
+				}).
+					AddRow("Test", 0, 1, true, 2, "en", 1, 420, 1, nil, false, nil, nil, true, 7, 2, 300)
 
-				mock.ExpectQuery("SELECT description, current_user_index FROM queues WHERE message_id = ?").
+				mock.ExpectQuery("SELECT description, current_user_index, owner_id, is_started, notify_count, language, capacity, turn_duration, turn_user_id, turn_started_at, turn_is_warned, opens_at, starts_at, is_shuffle, template_id, slot_count, check_in_timeout FROM queues WHERE message_id = ? AND finished_at IS NULL").
 					WithArgs(args.messageID).
 					WillReturnRows(rows)
 
-				rows = sqlmock.NewRows([]string{"user_id", "user_name"}).
-					AddRow(1, "Test")
+				rows = sqlmock.NewRows([]string{"user_id", "user_name", "slot", "check_in_asked_at", "is_checked_in", "missed_check_ins"}).
+					AddRow(1, "Test", 2, nil, true, 1)
 
-				mock.ExpectQuery("SELECT user_id, user_name FROM participants WHERE message_id = ? and isDeleted = 0 ORDER BY order_number").
+				mock.ExpectQuery("SELECT user_id, user_name, slot, check_in_asked_at, is_checked_in, missed_check_ins FROM participants WHERE message_id = ? and is_deleted = 0 AND is_waitlisted = 0 ORDER BY order_number NULLS LAST, joined_at").
 					WithArgs(args.messageID).
 					WillReturnRows(rows)
+
+				mock.ExpectPrepare("SELECT user_id FROM queue_admins WHERE message_id = ? ORDER BY user_id").WillBeClosed()
+				mock.ExpectQuery("SELECT user_id FROM queue_admins WHERE message_id = ? ORDER BY user_id").
+					WithArgs(args.messageID).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2))
+
+				mock.ExpectPrepare("SELECT user_id, user_name FROM participants WHERE message_id = ? AND is_deleted = 0 AND is_waitlisted = 1 ORDER BY joined_at").WillBeClosed()
+				mock.ExpectQuery("SELECT user_id, user_name FROM participants WHERE message_id = ? AND is_deleted = 0 AND is_waitlisted = 1 ORDER BY joined_at").
+					WithArgs(args.messageID).
+					WillReturnRows(sqlmock.NewRows([]string{"user_id", "user_name"}).AddRow(3, "Waiting"))
 			},
 			wantErr: false,
 		},
@@ -201,10 +244,10 @@ func TestDatabase_GetQueue(t *testing.T) {
 			},
 			want: entity.Queue{},
 			mockBehaviour: func(args args) {
-				mock.ExpectPrepare("SELECT description, current_user_index FROM queues WHERE message_id = ?").WillBeClosed()
-				mock.ExpectPrepare("SELECT user_id, user_name FROM participants WHERE message_id = ? and isDeleted = 0 ORDER BY order_number").WillBeClosed()
+				mock.ExpectPrepare("SELECT description, current_user_index, owner_id, is_started, notify_count, language, capacity, turn_duration, turn_user_id, turn_started_at, turn_is_warned, opens_at, starts_at, is_shuffle, template_id, slot_count, check_in_timeout FROM queues WHERE message_id = ? AND finished_at IS NULL").WillBeClosed()
+				mock.ExpectPrepare("SELECT user_id, user_name, slot, check_in_asked_at, is_checked_in, missed_check_ins FROM participants WHERE message_id = ? and is_deleted = 0 AND is_waitlisted = 0 ORDER BY order_number NULLS LAST, joined_at").WillBeClosed()
 
-				mock.ExpectQuery("SELECT description, current_user_index FROM queues WHERE message_id = ?").
+				mock.ExpectQuery("SELECT description, current_user_index, owner_id, is_started, notify_count, language, capacity, turn_duration, turn_user_id, turn_started_at, turn_is_warned, opens_at, starts_at, is_shuffle, template_id, slot_count, check_in_timeout FROM queues WHERE message_id = ? AND finished_at IS NULL").
 					WithArgs(args.messageID).
 					WillReturnError(sql.ErrNoRows)
 			},
@@ -319,12 +362,14 @@ func TestDatabase_LogInOutToQueue(t *testing.T) {
 			},
 			wantErr: false,
 			mockBehaviour: func(args args) {
-				mock.ExpectPrepare(`INSERT INTO participants(message_id, user_id, user_name)
-												VALUES (?, ?, ?) on conflict do update set isDeleted=not isDeleted, joined_at=CURRENT_TIMESTAMP`).
+				mock.ExpectPrepare(`INSERT INTO participants(message_id, user_id, user_name, joined_at)
+												VALUES (?, ?, ?, strftime('%Y-%m-%d %H:%M:%f', 'now'))
+												on conflict do update set is_deleted=not is_deleted, is_waitlisted=0, slot=0, check_in_asked_at=NULL, is_checked_in=0, missed_check_ins=0, joined_at=strftime('%Y-%m-%d %H:%M:%f', 'now')`).
 					WillBeClosed()
 
-				mock.ExpectExec(`INSERT INTO participants(message_id, user_id, user_name)
-												VALUES (?, ?, ?) on conflict do update set isDeleted=not isDeleted, joined_at=CURRENT_TIMESTAMP`).
+				mock.ExpectExec(`INSERT INTO participants(message_id, user_id, user_name, joined_at)
+												VALUES (?, ?, ?, strftime('%Y-%m-%d %H:%M:%f', 'now'))
+												on conflict do update set is_deleted=not is_deleted, is_waitlisted=0, slot=0, check_in_asked_at=NULL, is_checked_in=0, missed_check_ins=0, joined_at=strftime('%Y-%m-%d %H:%M:%f', 'now')`).
 					WithArgs(args.messageID, args.user.ID, args.user.Name).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
@@ -340,12 +385,14 @@ func TestDatabase_LogInOutToQueue(t *testing.T) {
 			},
 			wantErr: true,
 			mockBehaviour: func(args args) {
-				mock.ExpectPrepare(`INSERT INTO participants(message_id, user_id, user_name)
-												VALUES (?, ?, ?) on conflict do update set isDeleted=not isDeleted, joined_at=CURRENT_TIMESTAMP`).
+				mock.ExpectPrepare(`INSERT INTO participants(message_id, user_id, user_name, joined_at)
+												VALUES (?, ?, ?, strftime('%Y-%m-%d %H:%M:%f', 'now'))
+												on conflict do update set is_deleted=not is_deleted, is_waitlisted=0, slot=0, check_in_asked_at=NULL, is_checked_in=0, missed_check_ins=0, joined_at=strftime('%Y-%m-%d %H:%M:%f', 'now')`).
 					WillBeClosed()
 
-				mock.ExpectExec(`INSERT INTO participants(message_id, user_id, user_name)
-												VALUES (?, ?, ?) on conflict do update set isDeleted=not isDeleted, joined_at=CURRENT_TIMESTAMP`).
+				mock.ExpectExec(`INSERT INTO participants(message_id, user_id, user_name, joined_at)
+												VALUES (?, ?, ?, strftime('%Y-%m-%d %H:%M:%f', 'now'))
+												on conflict do update set is_deleted=not is_deleted, is_waitlisted=0, slot=0, check_in_asked_at=NULL, is_checked_in=0, missed_check_ins=0, joined_at=strftime('%Y-%m-%d %H:%M:%f', 'now')`).
 					WithArgs(args.messageID, args.user.ID, args.user.Name).
 					WillReturnError(errReference)
 			},
@@ -392,18 +439,18 @@ func TestDatabase_StartQueue(t *testing.T) {
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				mock.ExpectPrepare("UPDATE queues SET current_user_index = 0 WHERE message_id = ?").WillBeClosed()
-				mock.ExpectExec("UPDATE queues SET current_user_index = 0 WHERE message_id = ?").
+				mock.ExpectPrepare("UPDATE queues SET current_user_index = 0, is_started = TRUE WHERE message_id = ?").WillBeClosed()
+				mock.ExpectExec("UPDATE queues SET current_user_index = 0, is_started = TRUE WHERE message_id = ?").
 					WithArgs(args.messageID).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
 				mock.ExpectPrepare(`UPDATE participants SET order_number = dense_rank FROM 
                                                       (SELECT dense_rank() OVER (ORDER BY joined_at) AS dense_rank, user_id 
-                                                       FROM participants WHERE message_id = $1) AS sub WHERE participants.user_id = sub.user_id 
+                                                       FROM participants WHERE message_id = $1 AND is_waitlisted = 0) AS sub WHERE participants.user_id = sub.user_id 
                                                                                                         AND message_id = $1`).WillBeClosed()
 				mock.ExpectExec(`UPDATE participants SET order_number = dense_rank FROM 
                                                       (SELECT dense_rank() OVER (ORDER BY joined_at) AS dense_rank, user_id 
-                                                       FROM participants WHERE message_id = $1) AS sub WHERE participants.user_id = sub.user_id 
+                                                       FROM participants WHERE message_id = $1 AND is_waitlisted = 0) AS sub WHERE participants.user_id = sub.user_id 
                                                                                                         AND message_id = $1`).
 					WithArgs(args.messageID).
 					WillReturnResult(sqlmock.NewResult(1, 1))
@@ -421,17 +468,17 @@ func TestDatabase_StartQueue(t *testing.T) {
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				mock.ExpectPrepare("UPDATE queues SET current_user_index = 0 WHERE message_id = ?").WillBeClosed()
-				mock.ExpectExec("UPDATE queues SET current_user_index = 0 WHERE message_id = ?").
+				mock.ExpectPrepare("UPDATE queues SET current_user_index = 0, is_started = TRUE WHERE message_id = ?").WillBeClosed()
+				mock.ExpectExec("UPDATE queues SET current_user_index = 0, is_started = TRUE WHERE message_id = ?").
 					WithArgs(args.messageID).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
 				mock.ExpectPrepare(`UPDATE participants
 														SET order_number = random()
-														WHERE message_id = ?;`).WillBeClosed()
+														WHERE message_id = ? AND is_waitlisted = 0;`).WillBeClosed()
 				mock.ExpectExec(`UPDATE participants
 														SET order_number = random()
-														WHERE message_id = ?;`).
+														WHERE message_id = ? AND is_waitlisted = 0;`).
 					WithArgs(args.messageID).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -448,8 +495,8 @@ func TestDatabase_StartQueue(t *testing.T) {
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				mock.ExpectPrepare("UPDATE queues SET current_user_index = 0 WHERE message_id = ?").WillBeClosed()
-				mock.ExpectExec("UPDATE queues SET current_user_index = 0 WHERE message_id = ?").
+				mock.ExpectPrepare("UPDATE queues SET current_user_index = 0, is_started = TRUE WHERE message_id = ?").WillBeClosed()
+				mock.ExpectExec("UPDATE queues SET current_user_index = 0, is_started = TRUE WHERE message_id = ?").
 					WithArgs(args.messageID).
 					WillReturnError(errReference)
 
@@ -466,13 +513,13 @@ func TestDatabase_StartQueue(t *testing.T) {
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				mock.ExpectPrepare("UPDATE queues SET current_user_index = 0 WHERE message_id = ?").WillBeClosed()
-				mock.ExpectExec("UPDATE queues SET current_user_index = 0 WHERE message_id = ?").
+				mock.ExpectPrepare("UPDATE queues SET current_user_index = 0, is_started = TRUE WHERE message_id = ?").WillBeClosed()
+				mock.ExpectExec("UPDATE queues SET current_user_index = 0, is_started = TRUE WHERE message_id = ?").
 					WithArgs(args.messageID).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 
-				mock.ExpectPrepare("UPDATE participants SET order_number = random() WHERE message_id = ?;").WillBeClosed()
-				mock.ExpectExec("UPDATE participants SET order_number = random() WHERE message_id = ?;").
+				mock.ExpectPrepare("UPDATE participants SET order_number = random() WHERE message_id = ? AND is_waitlisted = 0;").WillBeClosed()
+				mock.ExpectExec("UPDATE participants SET order_number = random() WHERE message_id = ? AND is_waitlisted = 0;").
 					WithArgs(args.messageID).
 					WillReturnError(errReference)
 
@@ -489,8 +536,8 @@ func TestDatabase_StartQueue(t *testing.T) {
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				mock.ExpectPrepare("UPDATE queues SET current_user_index = 0 WHERE message_id = ?").WillBeClosed()
-				mock.ExpectExec("UPDATE queues SET current_user_index = 0 WHERE message_id = ?").
+				mock.ExpectPrepare("UPDATE queues SET current_user_index = 0, is_started = TRUE WHERE message_id = ?").WillBeClosed()
+				mock.ExpectExec("UPDATE queues SET current_user_index = 0, is_started = TRUE WHERE message_id = ?").
 					WithArgs(args.messageID).
 					WillReturnError(errReference)
 
@@ -543,12 +590,12 @@ func Test_updateParticipantsInorder(t *testing.T) {
 				mock.ExpectPrepare(`UPDATE participants
 														SET order_number = dense_rank FROM 
 														(SELECT dense_rank() OVER (ORDER BY joined_at) AS dense_rank, user_id 
-														FROM participants WHERE message_id = $1) AS sub WHERE participants.user_id = sub.user_id 
+														FROM participants WHERE message_id = $1 AND is_waitlisted = 0) AS sub WHERE participants.user_id = sub.user_id 
 														AND message_id = $1`).WillBeClosed()
 				mock.ExpectExec(`UPDATE participants
 														SET order_number = dense_rank FROM 
 														(SELECT dense_rank() OVER (ORDER BY joined_at) AS dense_rank, user_id 
-														FROM participants WHERE message_id = $1) AS sub WHERE participants.user_id = sub.user_id 
+														FROM participants WHERE message_id = $1 AND is_waitlisted = 0) AS sub WHERE participants.user_id = sub.user_id 
 														AND message_id = $1`).
 					WithArgs(args.messageID).
 					WillReturnResult(sqlmock.NewResult(1, 1))
@@ -567,12 +614,12 @@ func Test_updateParticipantsInorder(t *testing.T) {
 				mock.ExpectPrepare(`UPDATE participants
 														SET order_number = dense_rank FROM 
 														(SELECT dense_rank() OVER (ORDER BY joined_at) AS dense_rank, user_id 
-														FROM participants WHERE message_id = $1) AS sub WHERE participants.user_id = sub.user_id 
+														FROM participants WHERE message_id = $1 AND is_waitlisted = 0) AS sub WHERE participants.user_id = sub.user_id 
 														AND message_id = $1`).WillBeClosed()
 				mock.ExpectExec(`UPDATE participants
 														SET order_number = dense_rank FROM 
 														(SELECT dense_rank() OVER (ORDER BY joined_at) AS dense_rank, user_id 
-														FROM participants WHERE message_id = $1) AS sub WHERE participants.user_id = sub.user_id 
+														FROM participants WHERE message_id = $1 AND is_waitlisted = 0) AS sub WHERE participants.user_id = sub.user_id 
 														AND message_id = $1`).
 					WithArgs(args.messageID).
 					WillReturnError(errReference)
@@ -588,8 +635,8 @@ func Test_updateParticipantsInorder(t *testing.T) {
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				mock.ExpectPrepare("UPDATE participants SET order_number = random() WHERE message_id = ?;").WillBeClosed()
-				mock.ExpectExec("UPDATE participants SET order_number = random() WHERE message_id = ?;").
+				mock.ExpectPrepare("UPDATE participants SET order_number = random() WHERE message_id = ? AND is_waitlisted = 0;").WillBeClosed()
+				mock.ExpectExec("UPDATE participants SET order_number = random() WHERE message_id = ? AND is_waitlisted = 0;").
 					WithArgs(args.messageID).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
@@ -604,8 +651,8 @@ func Test_updateParticipantsInorder(t *testing.T) {
 			mockBehaviour: func(args args) {
 				mock.ExpectBegin()
 
-				mock.ExpectPrepare("UPDATE participants SET order_number = random() WHERE message_id = ?;").WillBeClosed()
-				mock.ExpectExec("UPDATE participants SET order_number = random() WHERE message_id = ?;").
+				mock.ExpectPrepare("UPDATE participants SET order_number = random() WHERE message_id = ? AND is_waitlisted = 0;").WillBeClosed()
+				mock.ExpectExec("UPDATE participants SET order_number = random() WHERE message_id = ? AND is_waitlisted = 0;").
 					WithArgs(args.messageID).
 					WillReturnError(errReference)
 			},
@@ -660,3 +707,43 @@ func TestDatabase_Close(t *testing.T) {
 		})
 	}
 }
+
+func TestDatabase_Ping(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	assert.NoError(t, err)
+
+	db := NewDatabaseFromDB(mockDB)
+	type mockBehaviour func()
+
+	tests := []struct {
+		name          string
+		mockBehaviour mockBehaviour
+		wantErr       bool
+	}{
+		{
+			name: "OK",
+			mockBehaviour: func() {
+				mock.ExpectPing()
+			},
+			wantErr: false,
+		},
+		{
+			name: "Database is unreachable",
+			mockBehaviour: func() {
+				mock.ExpectPing().WillReturnError(sql.ErrConnDone)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockBehaviour()
+
+			if err = db.Ping(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("db.Ping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
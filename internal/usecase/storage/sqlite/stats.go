@@ -0,0 +1,25 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+func (s Database) CountActive(ctx context.Context) (queues int, participants int, err error) {
+	countStmt, err := s.db.PrepareContext(
+		ctx,
+		`SELECT COUNT(DISTINCT q.message_id), COUNT(p.user_id) FROM queues q
+		LEFT JOIN participants p ON p.message_id = q.message_id AND p.is_deleted = 0 AND p.is_waitlisted = 0
+		WHERE q.finished_at IS NULL`,
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("couldn't prepare count active statement: %w", err)
+	}
+	defer countStmt.Close()
+
+	if err = countStmt.QueryRowContext(ctx).Scan(&queues, &participants); err != nil {
+		return 0, 0, fmt.Errorf("couldn't count active queues: %w", err)
+	}
+
+	return queues, participants, nil
+}
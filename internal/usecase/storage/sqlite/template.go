@@ -0,0 +1,142 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase/storage"
+)
+
+// templateColumns are selected by GetTemplate and GetTemplates, Instances count queues posted from the template.
+const templateColumns = `id, owner_id, description, capacity, opens_at, starts_at, is_shuffle,
+	(SELECT COUNT(*) FROM queues WHERE queues.template_id = templates.id)`
+
+func (s Database) CreateTemplate(ctx context.Context, template entity.Template) (int64, error) {
+	createStmt, err := s.db.PrepareContext(
+		ctx,
+		"INSERT INTO templates (owner_id, description, capacity, opens_at, starts_at, is_shuffle) VALUES (?, ?, ?, ?, ?, ?)",
+	)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't prepare create template statement: %w", err)
+	}
+	defer createStmt.Close()
+
+	result, err := createStmt.ExecContext(
+		ctx,
+		template.OwnerID, template.Description, template.Capacity, template.OpensAt, template.StartsAt, template.IsShuffle,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't create template: %w", err)
+	}
+
+	templateID, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("couldn't get ID of created template: %w", err)
+	}
+
+	return templateID, nil
+}
+
+func (s Database) GetTemplate(ctx context.Context, templateID int64) (entity.Template, error) {
+	getStmt, err := s.db.PrepareContext(ctx, "SELECT "+templateColumns+" FROM templates WHERE id = ?")
+	if err != nil {
+		return entity.Template{}, fmt.Errorf("couldn't prepare get template statement: %w", err)
+	}
+	defer getStmt.Close()
+
+	template, err := scanTemplate(getStmt.QueryRowContext(ctx, templateID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Template{}, fmt.Errorf("couldn't find template %d: %w", templateID, storage.ErrTemplateNotFound)
+	}
+
+	if err != nil {
+		return entity.Template{}, fmt.Errorf("couldn't scan template %d: %w", templateID, err)
+	}
+
+	return template, nil
+}
+
+func (s Database) GetTemplates(ctx context.Context, ownerID int64) ([]entity.Template, error) {
+	getStmt, err := s.db.PrepareContext(ctx, "SELECT "+templateColumns+" FROM templates WHERE owner_id = ? ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("couldn't prepare get templates statement: %w", err)
+	}
+	defer getStmt.Close()
+
+	rows, err := getStmt.QueryContext(ctx, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []entity.Template
+
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't scan template: %w", err)
+		}
+
+		templates = append(templates, template)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("couldn't read templates: %w", err)
+	}
+
+	return templates, nil
+}
+
+func (s Database) DeleteTemplate(ctx context.Context, templateID int64) error {
+	deleteStmt, err := s.db.PrepareContext(ctx, "DELETE FROM templates WHERE id = ?")
+	if err != nil {
+		return fmt.Errorf("couldn't prepare delete template statement: %w", err)
+	}
+	defer deleteStmt.Close()
+
+	result, err := deleteStmt.ExecContext(ctx, templateID)
+	if err != nil {
+		return fmt.Errorf("couldn't delete template: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("couldn't get affected rows: %w", err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("couldn't find template %d: %w", templateID, storage.ErrTemplateNotFound)
+	}
+
+	return nil
+}
+
+func (s Database) SetQueueTemplate(ctx context.Context, messageID string, templateID int64) error {
+	setStmt, err := s.db.PrepareContext(ctx, "UPDATE queues SET template_id = ? WHERE message_id = ?")
+	if err != nil {
+		return fmt.Errorf("couldn't prepare set queue template statement: %w", err)
+	}
+	defer setStmt.Close()
+
+	result, err := setStmt.ExecContext(ctx, templateID, messageID)
+	if err != nil {
+		return fmt.Errorf("couldn't set queue template: %w", err)
+	}
+
+	return checkQueueAffected(result, messageID)
+}
+
+// scanTemplate scans templateColumns of a row.
+func scanTemplate(row interface{ Scan(dest ...any) error }) (entity.Template, error) {
+	var template entity.Template
+
+	err := row.Scan(
+		&template.ID, &template.OwnerID, &template.Description, &template.Capacity,
+		&template.OpensAt, &template.StartsAt, &template.IsShuffle, &template.Instances,
+	)
+
+	return template, err
+}
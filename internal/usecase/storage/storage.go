@@ -2,18 +2,112 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"QueueBot/internal/entity"
 )
 
+var (
+	ErrQueueNotFound = errors.New("queue not found")
+	ErrNoSnapshots   = errors.New("no saved snapshots")
+	ErrNotWaitlisted = errors.New("user is not in the waitlist")
+	// ErrTemplateNotFound is returned for unknown IDs of templates.
+	ErrTemplateNotFound = errors.New("template not found")
+)
+
+// HistoryLimit is how many snapshots are kept for every queue.
+const HistoryLimit = 20
+
+// DefaultNotifyCount is NotifyCount of a new queue.
+const DefaultNotifyCount = 1
+
+// DefaultSlotCount is SlotCount of a new queue.
+const DefaultSlotCount = 1
+
 type Storage interface {
-	CreateQueue(ctx context.Context, messageID string, description string) error
+	// CreateQueue creates the queue for at most capacity people. Zero capacity means unlimited.
+	CreateQueue(ctx context.Context, messageID string, description string, ownerID int64, language string, capacity int) error
+	// LogInOutToQueue adds user to the end of the queue or takes them out of the queue or the waitlist.
 	LogInOutToQueue(ctx context.Context, messageID string, user entity.User) error
+	// JoinWaitlist adds user to the end of the waitlist.
+	JoinWaitlist(ctx context.Context, messageID string, user entity.User) error
+	// PromoteFromWaitlist moves waitlisted user to the end of the queue. It returns ErrNotWaitlisted for other users.
+	PromoteFromWaitlist(ctx context.Context, messageID string, userID int64) error
 	GetQueue(ctx context.Context, messageID string) (entity.Queue, error)
 
 	StartQueue(ctx context.Context, messageID string, isShuffle bool) error
 	IncrementCurrentPerson(ctx context.Context, messageID string) error
-	DeleteQueue(ctx context.Context, messageID string) error
+	DecrementCurrentPerson(ctx context.Context, messageID string) error
+	StopQueue(ctx context.Context, messageID string) error
+	// ArchiveQueue finishes the queue. It isn't returned by GetQueue anymore, but stays in GetFinishedQueues.
+	ArchiveQueue(ctx context.Context, messageID string) error
+	MoveParticipant(ctx context.Context, messageID string, userID int64, position int) error
+
+	// GetFinishedQueues returns up to limit latest archived queues the user was in, newest first.
+	GetFinishedQueues(ctx context.Context, userID int64, limit int) ([]entity.FinishedQueue, error)
+
+	// SaveSnapshot remembers the current state of queue participants, so it can be brought back by RestoreSnapshot.
+	SaveSnapshot(ctx context.Context, messageID string, operation entity.Operation) error
+	// RestoreSnapshot brings back the latest snapshot and forgets it.
+	// It returns the operation which was about to be done when the snapshot was saved.
+	RestoreSnapshot(ctx context.Context, messageID string) (entity.Operation, error)
+
+	// AddEvent appends operation done by user to the queue event log.
+	// Events are never changed and are kept after the queue is finished.
+	AddEvent(ctx context.Context, messageID string, operation entity.Operation, userID int64) error
+	// GetEvents returns up to limit latest events of the queue, newest first, with names of the participants who made them.
+	GetEvents(ctx context.Context, messageID string, limit int) ([]entity.Event, error)
+
+	AddAdmin(ctx context.Context, messageID string, userID int64) error
+	RemoveAdmin(ctx context.Context, messageID string, userID int64) error
+	// GetAdminQueueIDs returns message IDs of not archived queues the user owns or administers, in ascending order.
+	// Queues without an owner aren't returned.
+	GetAdminQueueIDs(ctx context.Context, userID int64) ([]string, error)
+
+	SetNotifyCount(ctx context.Context, messageID string, count int) error
+	SetLanguage(ctx context.Context, messageID string, language string) error
+	// SetTurnDuration sets how long every turn lasts. Zero turns timing off.
+	SetTurnDuration(ctx context.Context, messageID string, duration time.Duration) error
+	// SetTurn remembers timing of the current turn, so it survives restarts.
+	SetTurn(ctx context.Context, messageID string, turn entity.Turn) error
+	// GetTimedQueueIDs returns message IDs of started, not archived queues with a turn duration, in ascending order.
+	GetTimedQueueIDs(ctx context.Context) ([]string, error)
+	SetSchedule(ctx context.Context, messageID string, schedule entity.Schedule) error
+	// GetScheduledQueueIDs returns message IDs of not started, not archived queues with a start time, in ascending order.
+	GetScheduledQueueIDs(ctx context.Context) ([]string, error)
+
+	// CreateTemplate saves the template and returns its ID. ID and Instances of the template are ignored.
+	CreateTemplate(ctx context.Context, template entity.Template) (int64, error)
+	GetTemplate(ctx context.Context, templateID int64) (entity.Template, error)
+	// GetTemplates returns templates of the owner in the order they were created.
+	GetTemplates(ctx context.Context, ownerID int64) ([]entity.Template, error)
+	// DeleteTemplate forgets the template. Queues posted from it keep its ID.
+	DeleteTemplate(ctx context.Context, templateID int64) error
+	// SetQueueTemplate links the queue to the template it was posted from.
+	SetQueueTemplate(ctx context.Context, messageID string, templateID int64) error
+
+	SetSlotCount(ctx context.Context, messageID string, count int) error
+	// CallToSlot frees the slot, puts user at it and makes the next person current.
+	CallToSlot(ctx context.Context, messageID string, slot int, userID int64) error
+	// FreeSlot frees the slot. Slots are numbered from 1.
+	FreeSlot(ctx context.Context, messageID string, slot int) error
+	// SetCheckInTimeout sets how long people near their turn have to confirm being present. Zero turns check-in off.
+	SetCheckInTimeout(ctx context.Context, messageID string, timeout time.Duration) error
+	// SetCheckIn remembers the check-in of the participant. Joining the queue again starts it over.
+	SetCheckIn(ctx context.Context, messageID string, userID int64, checkIn entity.CheckIn) error
+	// GetCheckInQueueIDs returns message IDs of started, not archived queues with a check-in timeout, in ascending order.
+	GetCheckInQueueIDs(ctx context.Context) ([]string, error)
+
+	// AddSubscriber remembers that the bot can send private messages to the user.
+	AddSubscriber(ctx context.Context, userID int64) error
+	RemoveSubscriber(ctx context.Context, userID int64) error
+	IsSubscriber(ctx context.Context, userID int64) (bool, error)
+
+	// CountActive returns how many queues aren't archived and how many people are in them.
+	CountActive(ctx context.Context) (queues int, participants int, err error)
 
+	// Ping checks that storage is reachable.
+	Ping(ctx context.Context) error
 	Close() error
 }
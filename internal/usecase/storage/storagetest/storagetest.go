@@ -0,0 +1,1055 @@
+// Package storagetest contains conformance tests that every storage.Storage implementation has to pass.
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase/storage"
+)
+
+// Factory returns an empty storage. It is called once for every test case.
+type Factory func(t *testing.T) storage.Storage
+
+const (
+	messageID = "123"
+	ownerID   = 42
+	language  = "en"
+)
+
+// Run runs the whole conformance suite against storages created by newStorage.
+func Run(t *testing.T, newStorage Factory) {
+	t.Helper()
+
+	tests := []struct {
+		name string
+		test func(t *testing.T, s storage.Storage)
+	}{
+		{name: "CreateQueue", test: testCreateQueue},
+		{name: "CreateQueue with existing message ID", test: testCreateQueueDuplicate},
+		{name: "CreateQueue with capacity", test: testCreateQueueWithCapacity},
+		{name: "GetQueue not found", test: testGetQueueNotFound},
+		{name: "LogInOutToQueue keeps join order", test: testLogInOrder},
+		{name: "LogInOutToQueue toggles participant", test: testLogInOutToggle},
+		{name: "LogInOutToQueue unknown message ID", test: testLogInUnknownQueue},
+		{name: "StartQueue straight order", test: testStartQueueStraight},
+		{name: "StartQueue shuffle order", test: testStartQueueShuffle},
+		{name: "StartQueue resets current person", test: testStartQueueResetsCurrent},
+		{name: "StartQueue unknown message ID", test: testStartQueueUnknown},
+		{name: "Join after start goes to the end", test: testJoinAfterStart},
+		{name: "JoinWaitlist keeps people out of the queue", test: testJoinWaitlist},
+		{name: "LogInOutToQueue takes people out of the waitlist", test: testLeaveWaitlist},
+		{name: "PromoteFromWaitlist goes to the end", test: testPromoteFromWaitlist},
+		{name: "PromoteFromWaitlist after start", test: testPromoteAfterStart},
+		{name: "PromoteFromWaitlist not waitlisted", test: testPromoteNotWaitlisted},
+		{name: "IncrementCurrentPerson", test: testIncrementCurrentPerson},
+		{name: "IncrementCurrentPerson unknown message ID", test: testIncrementUnknown},
+		{name: "ArchiveQueue", test: testArchiveQueue},
+		{name: "ArchiveQueue unknown message ID", test: testArchiveUnknown},
+		{name: "GetFinishedQueues", test: testGetFinishedQueues},
+		{name: "MoveParticipant", test: testMoveParticipant},
+		{name: "MoveParticipant past the end", test: testMoveParticipantToEnd},
+		{name: "MoveParticipant unknown participant", test: testMoveUnknownParticipant},
+		{name: "DecrementCurrentPerson", test: testDecrementCurrentPerson},
+		{name: "StopQueue", test: testStopQueue},
+		{name: "RestoreSnapshot", test: testRestoreSnapshot},
+		{name: "RestoreSnapshot keeps order of left participant", test: testRestoreLeftParticipant},
+		{name: "RestoreSnapshot without snapshots", test: testRestoreWithoutSnapshots},
+		{name: "RestoreSnapshot brings back waitlist", test: testRestoreWaitlist},
+		{name: "SaveSnapshot keeps limited history", test: testSnapshotLimit},
+		{name: "AddAdmin and RemoveAdmin", test: testAdmins},
+		{name: "AddAdmin unknown message ID", test: testAddAdminUnknown},
+		{name: "GetAdminQueueIDs", test: testGetAdminQueueIDs},
+		{name: "SetNotifyCount", test: testSetNotifyCount},
+		{name: "SetNotifyCount unknown message ID", test: testSetNotifyCountUnknown},
+		{name: "SetLanguage", test: testSetLanguage},
+		{name: "SetLanguage unknown message ID", test: testSetLanguageUnknown},
+		{name: "SetTurnDuration", test: testSetTurnDuration},
+		{name: "SetTurnDuration unknown message ID", test: testSetTurnDurationUnknown},
+		{name: "SetTurn", test: testSetTurn},
+		{name: "SetTurn unknown message ID", test: testSetTurnUnknown},
+		{name: "GetTimedQueueIDs", test: testGetTimedQueueIDs},
+		{name: "SetSchedule", test: testSetSchedule},
+		{name: "SetSchedule unknown message ID", test: testSetScheduleUnknown},
+		{name: "GetScheduledQueueIDs", test: testGetScheduledQueueIDs},
+		{name: "CreateTemplate and GetTemplate", test: testTemplates},
+		{name: "GetTemplates of the owner", test: testGetTemplates},
+		{name: "DeleteTemplate", test: testDeleteTemplate},
+		{name: "SetQueueTemplate counts instances", test: testSetQueueTemplate},
+		{name: "SetQueueTemplate unknown message ID", test: testSetQueueTemplateUnknown},
+		{name: "SetSlotCount", test: testSetSlotCount},
+		{name: "SetSlotCount unknown message ID", test: testSetSlotCountUnknown},
+		{name: "CallToSlot and FreeSlot", test: testSlots},
+		{name: "CallToSlot unknown message ID", test: testCallToSlotUnknown},
+		{name: "LogInOutToQueue frees the slot", test: testLeaveSlot},
+		{name: "RestoreSnapshot brings back slots", test: testRestoreSlots},
+		{name: "SetCheckInTimeout", test: testSetCheckInTimeout},
+		{name: "SetCheckInTimeout unknown message ID", test: testSetCheckInTimeoutUnknown},
+		{name: "SetCheckIn", test: testSetCheckIn},
+		{name: "LogInOutToQueue resets the check-in", test: testRejoinResetsCheckIn},
+		{name: "RestoreSnapshot brings back check-ins", test: testRestoreCheckIns},
+		{name: "GetCheckInQueueIDs", test: testGetCheckInQueueIDs},
+		{name: "AddSubscriber and RemoveSubscriber", test: testSubscribers},
+		{name: "GetEvents newest first", test: testEvents},
+		{name: "GetEvents after ArchiveQueue", test: testEventsAfterDelete},
+		{name: "GetEvents with names", test: testEventsWithNames},
+		{name: "CountActive", test: testCountActive},
+		{name: "Ping", test: testPing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStorage(t))
+		})
+	}
+}
+
+func user(id int64) entity.User {
+	return entity.User{ID: id, Name: fmt.Sprintf("User%d", id)}
+}
+
+// logInOut toggles user and waits a bit, so every join gets its own timestamp.
+func logInOut(t *testing.T, s storage.Storage, u entity.User) {
+	t.Helper()
+
+	require.NoError(t, s.LogInOutToQueue(context.Background(), messageID, u))
+	time.Sleep(2 * time.Millisecond)
+}
+
+// joinWaitlist adds user to the waitlist and waits a bit like logInOut.
+func joinWaitlist(t *testing.T, s storage.Storage, u entity.User) {
+	t.Helper()
+
+	require.NoError(t, s.JoinWaitlist(context.Background(), messageID, u))
+	time.Sleep(2 * time.Millisecond)
+}
+
+func createQueue(t *testing.T, s storage.Storage, users ...entity.User) {
+	t.Helper()
+
+	require.NoError(t, s.CreateQueue(context.Background(), messageID, "Test", ownerID, language, 0))
+
+	for _, u := range users {
+		logInOut(t, s, u)
+	}
+}
+
+func getQueue(t *testing.T, s storage.Storage) entity.Queue {
+	t.Helper()
+
+	queue, err := s.GetQueue(context.Background(), messageID)
+	require.NoError(t, err)
+
+	return queue
+}
+
+func testCreateQueue(t *testing.T, s storage.Storage) {
+	createQueue(t, s)
+
+	assert.Equal(t, entity.Queue{
+		MessageID:   messageID,
+		Description: "Test",
+		OwnerID:     ownerID,
+		NotifyCount: storage.DefaultNotifyCount,
+		Language:    language,
+		SlotCount:   storage.DefaultSlotCount,
+	}, getQueue(t, s))
+}
+
+func testCreateQueueDuplicate(t *testing.T, s storage.Storage) {
+	createQueue(t, s)
+
+	assert.Error(t, s.CreateQueue(context.Background(), messageID, "Other", ownerID, language, 0))
+	assert.Equal(t, "Test", getQueue(t, s).Description)
+}
+
+func testCreateQueueWithCapacity(t *testing.T, s storage.Storage) {
+	require.NoError(t, s.CreateQueue(context.Background(), messageID, "Test", ownerID, language, 15))
+
+	assert.Equal(t, 15, getQueue(t, s).Capacity)
+}
+
+func testGetQueueNotFound(t *testing.T, s storage.Storage) {
+	_, err := s.GetQueue(context.Background(), messageID)
+	assert.ErrorIs(t, err, storage.ErrQueueNotFound)
+}
+
+func testLogInOrder(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(3), user(1), user(2))
+
+	assert.Equal(t, []entity.User{user(3), user(1), user(2)}, getQueue(t, s).Users)
+}
+
+func testLogInOutToggle(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2), user(3))
+
+	logInOut(t, s, user(1))
+	assert.Equal(t, []entity.User{user(2), user(3)}, getQueue(t, s).Users)
+
+	logInOut(t, s, user(1))
+	assert.Equal(t, []entity.User{user(2), user(3), user(1)}, getQueue(t, s).Users)
+}
+
+func testLogInUnknownQueue(t *testing.T, s storage.Storage) {
+	assert.Error(t, s.LogInOutToQueue(context.Background(), messageID, user(1)))
+}
+
+func testStartQueueStraight(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(2), user(1), user(3))
+	logInOut(t, s, user(2))
+	logInOut(t, s, user(2))
+
+	require.NoError(t, s.StartQueue(context.Background(), messageID, false))
+
+	queue := getQueue(t, s)
+	assert.Equal(t, []entity.User{user(1), user(3), user(2)}, queue.Users)
+	assert.Equal(t, 0, queue.CurrentPersonIdx)
+	assert.True(t, queue.IsStarted)
+}
+
+func testStartQueueShuffle(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2), user(3), user(4))
+	logInOut(t, s, user(4))
+
+	require.NoError(t, s.StartQueue(context.Background(), messageID, true))
+
+	queue := getQueue(t, s)
+	assert.ElementsMatch(t, []entity.User{user(1), user(2), user(3)}, queue.Users)
+	assert.Equal(t, 0, queue.CurrentPersonIdx)
+}
+
+func testStartQueueResetsCurrent(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2))
+	require.NoError(t, s.StartQueue(context.Background(), messageID, false))
+	require.NoError(t, s.IncrementCurrentPerson(context.Background(), messageID))
+
+	require.NoError(t, s.StartQueue(context.Background(), messageID, false))
+	assert.Equal(t, 0, getQueue(t, s).CurrentPersonIdx)
+}
+
+func testStartQueueUnknown(t *testing.T, s storage.Storage) {
+	err := s.StartQueue(context.Background(), messageID, false)
+	assert.ErrorIs(t, err, storage.ErrQueueNotFound)
+}
+
+func testJoinAfterStart(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2))
+	require.NoError(t, s.StartQueue(context.Background(), messageID, true))
+
+	logInOut(t, s, user(3))
+
+	queue := getQueue(t, s)
+	require.Len(t, queue.Users, 3)
+	assert.Equal(t, user(3), queue.Users[2])
+}
+
+func testIncrementCurrentPerson(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2))
+	require.NoError(t, s.StartQueue(context.Background(), messageID, false))
+
+	require.NoError(t, s.IncrementCurrentPerson(context.Background(), messageID))
+	assert.Equal(t, 1, getQueue(t, s).CurrentPersonIdx)
+
+	require.NoError(t, s.IncrementCurrentPerson(context.Background(), messageID))
+	assert.Equal(t, 2, getQueue(t, s).CurrentPersonIdx)
+}
+
+func testIncrementUnknown(t *testing.T, s storage.Storage) {
+	err := s.IncrementCurrentPerson(context.Background(), messageID)
+	assert.ErrorIs(t, err, storage.ErrQueueNotFound)
+}
+
+func testArchiveQueue(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2))
+	require.NoError(t, s.AddAdmin(context.Background(), messageID, 1))
+	require.NoError(t, s.SaveSnapshot(context.Background(), messageID, entity.OperationStart))
+
+	require.NoError(t, s.ArchiveQueue(context.Background(), messageID))
+
+	_, err := s.GetQueue(context.Background(), messageID)
+	assert.ErrorIs(t, err, storage.ErrQueueNotFound)
+
+	assert.ErrorIs(t, s.ArchiveQueue(context.Background(), messageID), storage.ErrQueueNotFound)
+	assert.Error(t, s.CreateQueue(context.Background(), messageID, "Test", ownerID, language, 0))
+}
+
+func testArchiveUnknown(t *testing.T, s storage.Storage) {
+	assert.ErrorIs(t, s.ArchiveQueue(context.Background(), messageID), storage.ErrQueueNotFound)
+}
+
+func testGetFinishedQueues(t *testing.T, s storage.Storage) {
+	const activeMessageID = "456"
+
+	createQueue(t, s, user(1), user(2), user(3))
+	require.NoError(t, s.StartQueue(context.Background(), messageID, false))
+	require.NoError(t, s.IncrementCurrentPerson(context.Background(), messageID))
+	require.NoError(t, s.ArchiveQueue(context.Background(), messageID))
+
+	require.NoError(t, s.CreateQueue(context.Background(), activeMessageID, "Active", ownerID, language, 0))
+	require.NoError(t, s.LogInOutToQueue(context.Background(), activeMessageID, user(2)))
+
+	finished, err := s.GetFinishedQueues(context.Background(), 2, 10)
+	require.NoError(t, err)
+	require.Len(t, finished, 1)
+
+	assert.Equal(t, messageID, finished[0].MessageID)
+	assert.Equal(t, "Test", finished[0].Description)
+	assert.Equal(t, 1, finished[0].Position)
+	assert.Equal(t, 3, finished[0].Participants)
+	assert.Equal(t, 1, finished[0].CurrentPersonIdx)
+	assert.False(t, finished[0].FinishedAt.IsZero())
+	assert.False(t, finished[0].Passed())
+
+	finished, err = s.GetFinishedQueues(context.Background(), 1, 10)
+	require.NoError(t, err)
+	require.Len(t, finished, 1)
+	assert.True(t, finished[0].Passed())
+
+	time.Sleep(2 * time.Millisecond)
+	require.NoError(t, s.ArchiveQueue(context.Background(), activeMessageID))
+
+	finished, err = s.GetFinishedQueues(context.Background(), 2, 10)
+	require.NoError(t, err)
+	require.Len(t, finished, 2)
+	assert.Equal(t, activeMessageID, finished[0].MessageID)
+	assert.Equal(t, 0, finished[0].Position)
+	assert.Equal(t, 1, finished[0].Participants)
+
+	finished, err = s.GetFinishedQueues(context.Background(), 2, 1)
+	require.NoError(t, err)
+	assert.Len(t, finished, 1)
+
+	finished, err = s.GetFinishedQueues(context.Background(), 4, 10)
+	require.NoError(t, err)
+	assert.Empty(t, finished)
+}
+
+func testAdmins(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2), user(3))
+
+	require.NoError(t, s.AddAdmin(context.Background(), messageID, 3))
+	require.NoError(t, s.AddAdmin(context.Background(), messageID, 1))
+	require.NoError(t, s.AddAdmin(context.Background(), messageID, 3))
+	assert.Equal(t, []int64{1, 3}, getQueue(t, s).AdminIDs)
+
+	require.NoError(t, s.RemoveAdmin(context.Background(), messageID, 3))
+	assert.Equal(t, []int64{1}, getQueue(t, s).AdminIDs)
+
+	require.NoError(t, s.RemoveAdmin(context.Background(), messageID, 1))
+	assert.Empty(t, getQueue(t, s).AdminIDs)
+}
+
+func testAddAdminUnknown(t *testing.T, s storage.Storage) {
+	assert.Error(t, s.AddAdmin(context.Background(), messageID, 1))
+}
+
+func testMoveParticipant(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2), user(3), user(4))
+	require.NoError(t, s.StartQueue(context.Background(), messageID, false))
+
+	require.NoError(t, s.MoveParticipant(context.Background(), messageID, 1, 2))
+	assert.Equal(t, []entity.User{user(2), user(3), user(1), user(4)}, getQueue(t, s).Users)
+
+	require.NoError(t, s.MoveParticipant(context.Background(), messageID, 4, 0))
+	assert.Equal(t, []entity.User{user(4), user(2), user(3), user(1)}, getQueue(t, s).Users)
+}
+
+func testMoveParticipantToEnd(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2), user(3))
+	require.NoError(t, s.StartQueue(context.Background(), messageID, false))
+	logInOut(t, s, user(4))
+
+	require.NoError(t, s.MoveParticipant(context.Background(), messageID, 2, 100))
+	assert.Equal(t, []entity.User{user(1), user(3), user(4), user(2)}, getQueue(t, s).Users)
+}
+
+func testMoveUnknownParticipant(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2))
+	logInOut(t, s, user(2))
+
+	err := s.MoveParticipant(context.Background(), messageID, 2, 0)
+	assert.ErrorIs(t, err, storage.ErrParticipantNotFound)
+	assert.Equal(t, []entity.User{user(1)}, getQueue(t, s).Users)
+}
+
+func testDecrementCurrentPerson(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2))
+	require.NoError(t, s.StartQueue(context.Background(), messageID, false))
+	require.NoError(t, s.IncrementCurrentPerson(context.Background(), messageID))
+
+	require.NoError(t, s.DecrementCurrentPerson(context.Background(), messageID))
+	assert.Equal(t, 0, getQueue(t, s).CurrentPersonIdx)
+
+	require.NoError(t, s.DecrementCurrentPerson(context.Background(), messageID))
+	assert.Equal(t, 0, getQueue(t, s).CurrentPersonIdx)
+
+	err := s.DecrementCurrentPerson(context.Background(), "unknown")
+	assert.ErrorIs(t, err, storage.ErrQueueNotFound)
+}
+
+func testStopQueue(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1))
+	assert.False(t, getQueue(t, s).IsStarted)
+
+	require.NoError(t, s.StartQueue(context.Background(), messageID, false))
+	require.NoError(t, s.StopQueue(context.Background(), messageID))
+	assert.False(t, getQueue(t, s).IsStarted)
+
+	assert.ErrorIs(t, s.StopQueue(context.Background(), "unknown"), storage.ErrQueueNotFound)
+}
+
+func testRestoreSnapshot(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2), user(3))
+	before := getQueue(t, s)
+
+	require.NoError(t, s.SaveSnapshot(context.Background(), messageID, entity.OperationShuffle))
+	require.NoError(t, s.StartQueue(context.Background(), messageID, true))
+	started := getQueue(t, s)
+
+	require.NoError(t, s.SaveSnapshot(context.Background(), messageID, entity.OperationNext))
+	require.NoError(t, s.IncrementCurrentPerson(context.Background(), messageID))
+
+	operation, err := s.RestoreSnapshot(context.Background(), messageID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.OperationNext, operation)
+	assert.Equal(t, started, getQueue(t, s))
+
+	operation, err = s.RestoreSnapshot(context.Background(), messageID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.OperationShuffle, operation)
+	assert.Equal(t, before, getQueue(t, s))
+
+	// Restored queue keeps working as usual.
+	logInOut(t, s, user(4))
+	require.NoError(t, s.StartQueue(context.Background(), messageID, false))
+	assert.Equal(t, []entity.User{user(1), user(2), user(3), user(4)}, getQueue(t, s).Users)
+}
+
+func testRestoreLeftParticipant(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2), user(3))
+	require.NoError(t, s.StartQueue(context.Background(), messageID, false))
+
+	require.NoError(t, s.SaveSnapshot(context.Background(), messageID, entity.OperationLeave))
+	logInOut(t, s, user(1))
+	assert.Equal(t, []entity.User{user(2), user(3)}, getQueue(t, s).Users)
+
+	_, err := s.RestoreSnapshot(context.Background(), messageID)
+	require.NoError(t, err)
+	assert.Equal(t, []entity.User{user(1), user(2), user(3)}, getQueue(t, s).Users)
+}
+
+func testRestoreWithoutSnapshots(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1))
+
+	_, err := s.RestoreSnapshot(context.Background(), messageID)
+	assert.ErrorIs(t, err, storage.ErrNoSnapshots)
+
+	assert.ErrorIs(t, s.SaveSnapshot(context.Background(), "unknown", entity.OperationNext), storage.ErrQueueNotFound)
+}
+
+func testSnapshotLimit(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1))
+	require.NoError(t, s.StartQueue(context.Background(), messageID, false))
+
+	for i := 0; i < storage.HistoryLimit+5; i++ {
+		require.NoError(t, s.SaveSnapshot(context.Background(), messageID, entity.OperationNext))
+		require.NoError(t, s.IncrementCurrentPerson(context.Background(), messageID))
+	}
+
+	for i := 0; i < storage.HistoryLimit; i++ {
+		_, err := s.RestoreSnapshot(context.Background(), messageID)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 5, getQueue(t, s).CurrentPersonIdx)
+
+	_, err := s.RestoreSnapshot(context.Background(), messageID)
+	assert.ErrorIs(t, err, storage.ErrNoSnapshots)
+}
+
+func testEvents(t *testing.T, s storage.Storage) {
+	createQueue(t, s)
+
+	operations := []entity.Operation{entity.OperationCreate, entity.OperationJoin, entity.OperationStart, entity.OperationNext}
+	for i, operation := range operations {
+		require.NoError(t, s.AddEvent(context.Background(), messageID, operation, int64(i)))
+	}
+
+	events, err := s.GetEvents(context.Background(), messageID, 3)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	for i, event := range events {
+		assert.Equal(t, operations[len(operations)-1-i], event.Operation)
+		assert.Equal(t, int64(len(operations)-1-i), event.UserID)
+		assert.False(t, event.CreatedAt.IsZero())
+	}
+
+	events, err = s.GetEvents(context.Background(), "unknown", 3)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func testEventsAfterDelete(t *testing.T, s storage.Storage) {
+	createQueue(t, s)
+	require.NoError(t, s.AddEvent(context.Background(), messageID, entity.OperationFinish, ownerID))
+	require.NoError(t, s.ArchiveQueue(context.Background(), messageID))
+
+	events, err := s.GetEvents(context.Background(), messageID, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, entity.OperationFinish, events[0].Operation)
+}
+
+func testEventsWithNames(t *testing.T, s storage.Storage) {
+	stayed := entity.User{ID: 2, Name: "Stayed"}
+	left := entity.User{ID: 3, Name: "Left"}
+
+	createQueue(t, s, stayed, left)
+	logInOut(t, s, left)
+
+	for _, userID := range []int64{ownerID, stayed.ID, left.ID} {
+		require.NoError(t, s.AddEvent(context.Background(), messageID, entity.OperationJoin, userID))
+	}
+
+	events, err := s.GetEvents(context.Background(), messageID, 3)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	assert.Equal(t, left.Name, events[0].UserName)
+	assert.Equal(t, stayed.Name, events[1].UserName)
+	assert.Empty(t, events[2].UserName, "owner never joined")
+}
+
+func testSetNotifyCount(t *testing.T, s storage.Storage) {
+	createQueue(t, s)
+
+	require.NoError(t, s.SetNotifyCount(context.Background(), messageID, 3))
+	assert.Equal(t, 3, getQueue(t, s).NotifyCount)
+}
+
+func testSetNotifyCountUnknown(t *testing.T, s storage.Storage) {
+	assert.ErrorIs(t, s.SetNotifyCount(context.Background(), messageID, 3), storage.ErrQueueNotFound)
+}
+
+func testSetLanguage(t *testing.T, s storage.Storage) {
+	createQueue(t, s)
+
+	require.NoError(t, s.SetLanguage(context.Background(), messageID, "ru"))
+	assert.Equal(t, "ru", getQueue(t, s).Language)
+}
+
+func testSetLanguageUnknown(t *testing.T, s storage.Storage) {
+	assert.ErrorIs(t, s.SetLanguage(context.Background(), messageID, "ru"), storage.ErrQueueNotFound)
+}
+
+func testSetTurnDuration(t *testing.T, s storage.Storage) {
+	createQueue(t, s)
+
+	require.NoError(t, s.SetTurnDuration(context.Background(), messageID, 7*time.Minute))
+	assert.Equal(t, 7*time.Minute, getQueue(t, s).TurnDuration)
+
+	require.NoError(t, s.SetTurnDuration(context.Background(), messageID, 0))
+	assert.Zero(t, getQueue(t, s).TurnDuration)
+}
+
+func testSetTurnDurationUnknown(t *testing.T, s storage.Storage) {
+	assert.ErrorIs(t, s.SetTurnDuration(context.Background(), messageID, time.Minute), storage.ErrQueueNotFound)
+}
+
+func testSetTurn(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1))
+	assert.Equal(t, entity.Turn{}, getQueue(t, s).Turn)
+
+	startedAt := time.Date(2024, time.March, 1, 10, 30, 15, 0, time.UTC)
+	require.NoError(t, s.SetTurn(context.Background(), messageID, entity.Turn{UserID: 1, StartedAt: startedAt, IsWarned: true}))
+
+	turn := getQueue(t, s).Turn
+	assert.Equal(t, int64(1), turn.UserID)
+	assert.True(t, startedAt.Equal(turn.StartedAt), "got %s", turn.StartedAt)
+	assert.True(t, turn.IsWarned)
+
+	require.NoError(t, s.SetTurn(context.Background(), messageID, entity.Turn{}))
+	assert.Equal(t, entity.Turn{}, getQueue(t, s).Turn)
+}
+
+func testSetTurnUnknown(t *testing.T, s storage.Storage) {
+	assert.ErrorIs(t, s.SetTurn(context.Background(), messageID, entity.Turn{UserID: 1}), storage.ErrQueueNotFound)
+}
+
+func testSetSchedule(t *testing.T, s storage.Storage) {
+	createQueue(t, s)
+	assert.Equal(t, entity.Schedule{}, getQueue(t, s).Schedule)
+
+	opensAt := time.Date(2024, time.March, 1, 8, 0, 0, 0, time.UTC)
+	startsAt := opensAt.Add(time.Hour)
+	require.NoError(t, s.SetSchedule(context.Background(), messageID, entity.Schedule{
+		OpensAt:   opensAt,
+		StartsAt:  startsAt,
+		IsShuffle: true,
+	}))
+
+	schedule := getQueue(t, s).Schedule
+	assert.True(t, opensAt.Equal(schedule.OpensAt), "got %s", schedule.OpensAt)
+	assert.True(t, startsAt.Equal(schedule.StartsAt), "got %s", schedule.StartsAt)
+	assert.True(t, schedule.IsShuffle)
+
+	require.NoError(t, s.SetSchedule(context.Background(), messageID, entity.Schedule{}))
+	assert.Equal(t, entity.Schedule{}, getQueue(t, s).Schedule)
+}
+
+func testSetScheduleUnknown(t *testing.T, s storage.Storage) {
+	assert.ErrorIs(t, s.SetSchedule(context.Background(), messageID, entity.Schedule{}), storage.ErrQueueNotFound)
+}
+
+func testGetScheduledQueueIDs(t *testing.T, s storage.Storage) {
+	const (
+		startedMessageID     = "started"
+		unscheduledMessageID = "unscheduled"
+		openingMessageID     = "opening"
+		archivedMessageID    = "archived"
+	)
+
+	startsAt := time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC)
+	schedules := map[string]entity.Schedule{
+		messageID:         {StartsAt: startsAt},
+		startedMessageID:  {StartsAt: startsAt},
+		openingMessageID:  {OpensAt: startsAt},
+		archivedMessageID: {StartsAt: startsAt},
+	}
+
+	for _, id := range []string{messageID, startedMessageID, unscheduledMessageID, openingMessageID, archivedMessageID} {
+		require.NoError(t, s.CreateQueue(context.Background(), id, "Test", ownerID, language, 0))
+		require.NoError(t, s.SetSchedule(context.Background(), id, schedules[id]))
+	}
+
+	require.NoError(t, s.StartQueue(context.Background(), startedMessageID, false))
+	require.NoError(t, s.ArchiveQueue(context.Background(), archivedMessageID))
+
+	messageIDs, err := s.GetScheduledQueueIDs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{messageID}, messageIDs)
+}
+
+func testTemplates(t *testing.T, s storage.Storage) {
+	template := entity.Template{
+		OwnerID:     ownerID,
+		Description: "Лаба по ОС",
+		Capacity:    15,
+		OpensAt:     "08:00",
+		StartsAt:    "09:00",
+		IsShuffle:   true,
+	}
+
+	templateID, err := s.CreateTemplate(context.Background(), template)
+	require.NoError(t, err)
+
+	got, err := s.GetTemplate(context.Background(), templateID)
+	require.NoError(t, err)
+
+	template.ID = templateID
+	assert.Equal(t, template, got)
+
+	_, err = s.GetTemplate(context.Background(), templateID+1)
+	assert.ErrorIs(t, err, storage.ErrTemplateNotFound)
+}
+
+func testGetTemplates(t *testing.T, s storage.Storage) {
+	templates, err := s.GetTemplates(context.Background(), ownerID)
+	require.NoError(t, err)
+	assert.Empty(t, templates)
+
+	for _, template := range []entity.Template{
+		{OwnerID: ownerID, Description: "First"},
+		{OwnerID: 1, Description: "Other"},
+		{OwnerID: ownerID, Description: "Second"},
+	} {
+		_, err = s.CreateTemplate(context.Background(), template)
+		require.NoError(t, err)
+	}
+
+	templates, err = s.GetTemplates(context.Background(), ownerID)
+	require.NoError(t, err)
+	require.Len(t, templates, 2)
+	assert.Equal(t, "First", templates[0].Description)
+	assert.Equal(t, "Second", templates[1].Description)
+}
+
+func testDeleteTemplate(t *testing.T, s storage.Storage) {
+	templateID, err := s.CreateTemplate(context.Background(), entity.Template{OwnerID: ownerID, Description: "Test"})
+	require.NoError(t, err)
+
+	require.NoError(t, s.DeleteTemplate(context.Background(), templateID))
+
+	_, err = s.GetTemplate(context.Background(), templateID)
+	assert.ErrorIs(t, err, storage.ErrTemplateNotFound)
+	assert.ErrorIs(t, s.DeleteTemplate(context.Background(), templateID), storage.ErrTemplateNotFound)
+
+	// IDs of deleted templates aren't reused, so old queues don't count as instances of new templates.
+	newTemplateID, err := s.CreateTemplate(context.Background(), entity.Template{OwnerID: ownerID, Description: "Test"})
+	require.NoError(t, err)
+	assert.NotEqual(t, templateID, newTemplateID)
+}
+
+func testSetQueueTemplate(t *testing.T, s storage.Storage) {
+	const archivedMessageID = "archived"
+
+	templateID, err := s.CreateTemplate(context.Background(), entity.Template{OwnerID: ownerID, Description: "Test"})
+	require.NoError(t, err)
+
+	createQueue(t, s)
+	assert.Zero(t, getQueue(t, s).TemplateID)
+
+	require.NoError(t, s.CreateQueue(context.Background(), archivedMessageID, "Test", ownerID, language, 0))
+	require.NoError(t, s.SetQueueTemplate(context.Background(), archivedMessageID, templateID))
+	require.NoError(t, s.ArchiveQueue(context.Background(), archivedMessageID))
+
+	require.NoError(t, s.SetQueueTemplate(context.Background(), messageID, templateID))
+	assert.Equal(t, templateID, getQueue(t, s).TemplateID)
+
+	// Archived queues are still instances of the template.
+	template, err := s.GetTemplate(context.Background(), templateID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, template.Instances)
+}
+
+func testSetQueueTemplateUnknown(t *testing.T, s storage.Storage) {
+	assert.ErrorIs(t, s.SetQueueTemplate(context.Background(), messageID, 1), storage.ErrQueueNotFound)
+}
+
+func testSetSlotCount(t *testing.T, s storage.Storage) {
+	createQueue(t, s)
+
+	require.NoError(t, s.SetSlotCount(context.Background(), messageID, 3))
+
+	queue := getQueue(t, s)
+	assert.Equal(t, 3, queue.SlotCount)
+	assert.Equal(t, []entity.User{{}, {}, {}}, queue.Slots)
+}
+
+func testSetSlotCountUnknown(t *testing.T, s storage.Storage) {
+	assert.ErrorIs(t, s.SetSlotCount(context.Background(), messageID, 2), storage.ErrQueueNotFound)
+}
+
+func testSlots(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2), user(3))
+	require.NoError(t, s.SetSlotCount(context.Background(), messageID, 2))
+	require.NoError(t, s.StartQueue(context.Background(), messageID, false))
+
+	require.NoError(t, s.CallToSlot(context.Background(), messageID, 2, 1))
+	require.NoError(t, s.CallToSlot(context.Background(), messageID, 1, 2))
+
+	queue := getQueue(t, s)
+	assert.Equal(t, []entity.User{user(2), user(1)}, queue.Slots)
+	assert.Equal(t, 2, queue.CurrentPersonIdx)
+
+	// The person at the slot is replaced by the called one.
+	require.NoError(t, s.CallToSlot(context.Background(), messageID, 2, 3))
+	require.NoError(t, s.FreeSlot(context.Background(), messageID, 1))
+
+	queue = getQueue(t, s)
+	assert.Equal(t, []entity.User{{}, user(3)}, queue.Slots)
+	assert.Equal(t, 3, queue.CurrentPersonIdx)
+}
+
+func testCallToSlotUnknown(t *testing.T, s storage.Storage) {
+	assert.ErrorIs(t, s.CallToSlot(context.Background(), messageID, 1, 1), storage.ErrQueueNotFound)
+}
+
+func testLeaveSlot(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1))
+	require.NoError(t, s.SetSlotCount(context.Background(), messageID, 2))
+	require.NoError(t, s.StartQueue(context.Background(), messageID, false))
+	require.NoError(t, s.CallToSlot(context.Background(), messageID, 1, 1))
+
+	logInOut(t, s, user(1))
+	logInOut(t, s, user(1))
+
+	assert.Equal(t, []entity.User{{}, {}}, getQueue(t, s).Slots)
+}
+
+func testRestoreSlots(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2))
+	require.NoError(t, s.SetSlotCount(context.Background(), messageID, 2))
+	require.NoError(t, s.StartQueue(context.Background(), messageID, false))
+	require.NoError(t, s.CallToSlot(context.Background(), messageID, 1, 1))
+
+	require.NoError(t, s.SaveSnapshot(context.Background(), messageID, entity.OperationSlotDone))
+	require.NoError(t, s.CallToSlot(context.Background(), messageID, 1, 2))
+
+	_, err := s.RestoreSnapshot(context.Background(), messageID)
+	require.NoError(t, err)
+
+	queue := getQueue(t, s)
+	assert.Equal(t, []entity.User{user(1), {}}, queue.Slots)
+	assert.Equal(t, 1, queue.CurrentPersonIdx)
+}
+
+func testSetCheckInTimeout(t *testing.T, s storage.Storage) {
+	createQueue(t, s)
+	assert.Zero(t, getQueue(t, s).CheckInTimeout)
+
+	require.NoError(t, s.SetCheckInTimeout(context.Background(), messageID, 5*time.Minute))
+	assert.Equal(t, 5*time.Minute, getQueue(t, s).CheckInTimeout)
+}
+
+func testSetCheckInTimeoutUnknown(t *testing.T, s storage.Storage) {
+	assert.ErrorIs(t, s.SetCheckInTimeout(context.Background(), messageID, time.Minute), storage.ErrQueueNotFound)
+}
+
+func testSetCheckIn(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2))
+	assert.Empty(t, getQueue(t, s).CheckIns)
+
+	askedAt := time.Date(2024, time.March, 1, 10, 30, 15, 0, time.UTC)
+	require.NoError(t, s.SetCheckIn(context.Background(), messageID, 1, entity.CheckIn{AskedAt: askedAt, IsConfirmed: true}))
+	require.NoError(t, s.SetCheckIn(context.Background(), messageID, 2, entity.CheckIn{Missed: 1}))
+
+	checkIns := getQueue(t, s).CheckIns
+	require.Len(t, checkIns, 2)
+	assert.True(t, askedAt.Equal(checkIns[1].AskedAt), "got %s", checkIns[1].AskedAt)
+	assert.True(t, checkIns[1].IsConfirmed)
+	assert.Equal(t, entity.CheckIn{Missed: 1}, checkIns[2])
+
+	require.NoError(t, s.SetCheckIn(context.Background(), messageID, 1, entity.CheckIn{}))
+	assert.Equal(t, map[int64]entity.CheckIn{2: {Missed: 1}}, getQueue(t, s).CheckIns)
+}
+
+func testRejoinResetsCheckIn(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1))
+	require.NoError(t, s.SetCheckIn(context.Background(), messageID, 1, entity.CheckIn{Missed: 1}))
+
+	logInOut(t, s, user(1))
+	logInOut(t, s, user(1))
+
+	assert.Empty(t, getQueue(t, s).CheckIns)
+}
+
+func testRestoreCheckIns(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2))
+	require.NoError(t, s.SetCheckIn(context.Background(), messageID, 1, entity.CheckIn{Missed: 1}))
+
+	require.NoError(t, s.SaveSnapshot(context.Background(), messageID, entity.OperationCheckInRemoved))
+	logInOut(t, s, user(1))
+
+	_, err := s.RestoreSnapshot(context.Background(), messageID)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[int64]entity.CheckIn{1: {Missed: 1}}, getQueue(t, s).CheckIns)
+}
+
+func testGetCheckInQueueIDs(t *testing.T, s storage.Storage) {
+	const (
+		notStartedMessageID = "not started"
+		withoutCheckInID    = "without check-in"
+		archivedMessageID   = "archived"
+	)
+
+	for _, id := range []string{messageID, notStartedMessageID, withoutCheckInID, archivedMessageID} {
+		require.NoError(t, s.CreateQueue(context.Background(), id, "Test", ownerID, language, 0))
+
+		if id != withoutCheckInID {
+			require.NoError(t, s.SetCheckInTimeout(context.Background(), id, time.Minute))
+		}
+
+		if id != notStartedMessageID {
+			require.NoError(t, s.StartQueue(context.Background(), id, false))
+		}
+	}
+
+	require.NoError(t, s.ArchiveQueue(context.Background(), archivedMessageID))
+
+	messageIDs, err := s.GetCheckInQueueIDs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{messageID}, messageIDs)
+}
+
+func testGetTimedQueueIDs(t *testing.T, s storage.Storage) {
+	const (
+		notStartedMessageID = "not started"
+		untimedMessageID    = "untimed"
+		archivedMessageID   = "archived"
+	)
+
+	for _, id := range []string{messageID, notStartedMessageID, untimedMessageID, archivedMessageID} {
+		require.NoError(t, s.CreateQueue(context.Background(), id, "Test", ownerID, language, 0))
+
+		if id != untimedMessageID {
+			require.NoError(t, s.SetTurnDuration(context.Background(), id, time.Minute))
+		}
+
+		if id != notStartedMessageID {
+			require.NoError(t, s.StartQueue(context.Background(), id, false))
+		}
+	}
+
+	require.NoError(t, s.ArchiveQueue(context.Background(), archivedMessageID))
+
+	messageIDs, err := s.GetTimedQueueIDs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{messageID}, messageIDs)
+}
+
+func testSubscribers(t *testing.T, s storage.Storage) {
+	isSubscriber, err := s.IsSubscriber(context.Background(), 1)
+	require.NoError(t, err)
+	assert.False(t, isSubscriber)
+
+	require.NoError(t, s.AddSubscriber(context.Background(), 1))
+	require.NoError(t, s.AddSubscriber(context.Background(), 1))
+
+	isSubscriber, err = s.IsSubscriber(context.Background(), 1)
+	require.NoError(t, err)
+	assert.True(t, isSubscriber)
+
+	require.NoError(t, s.RemoveSubscriber(context.Background(), 1))
+
+	isSubscriber, err = s.IsSubscriber(context.Background(), 1)
+	require.NoError(t, err)
+	assert.False(t, isSubscriber)
+}
+
+func testCountActive(t *testing.T, s storage.Storage) {
+	queues, participants, err := s.CountActive(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, queues)
+	assert.Zero(t, participants)
+
+	createQueue(t, s, user(1), user(2), user(3))
+	logInOut(t, s, user(2))
+	// Waitlisted people aren't in the queue yet.
+	joinWaitlist(t, s, user(4))
+
+	const emptyMessageID = "empty"
+	require.NoError(t, s.CreateQueue(context.Background(), emptyMessageID, "Empty", ownerID, language, 0))
+
+	queues, participants, err = s.CountActive(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, queues)
+	assert.Equal(t, 2, participants)
+
+	require.NoError(t, s.ArchiveQueue(context.Background(), messageID))
+
+	queues, participants, err = s.CountActive(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, queues)
+	assert.Zero(t, participants)
+}
+
+func testPing(t *testing.T, s storage.Storage) {
+	assert.NoError(t, s.Ping(context.Background()))
+}
+
+func testGetAdminQueueIDs(t *testing.T, s storage.Storage) {
+	const (
+		adminMessageID    = "admin"
+		archivedMessageID = "archived"
+		otherMessageID    = "other"
+	)
+
+	createQueue(t, s)
+	require.NoError(t, s.CreateQueue(context.Background(), adminMessageID, "Admin", 1, language, 0))
+	require.NoError(t, s.AddAdmin(context.Background(), adminMessageID, ownerID))
+	require.NoError(t, s.CreateQueue(context.Background(), archivedMessageID, "Archived", ownerID, language, 0))
+	require.NoError(t, s.ArchiveQueue(context.Background(), archivedMessageID))
+	require.NoError(t, s.CreateQueue(context.Background(), otherMessageID, "Other", 1, language, 0))
+
+	messageIDs, err := s.GetAdminQueueIDs(context.Background(), ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{messageID, adminMessageID}, messageIDs)
+
+	messageIDs, err = s.GetAdminQueueIDs(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Empty(t, messageIDs)
+}
+
+func testJoinWaitlist(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2))
+	joinWaitlist(t, s, user(4))
+	joinWaitlist(t, s, user(3))
+
+	queue := getQueue(t, s)
+	assert.Equal(t, []entity.User{user(1), user(2)}, queue.Users)
+	assert.Equal(t, []entity.User{user(4), user(3)}, queue.Waitlist)
+}
+
+func testLeaveWaitlist(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1))
+	joinWaitlist(t, s, user(2))
+
+	logInOut(t, s, user(2))
+
+	queue := getQueue(t, s)
+	assert.Equal(t, []entity.User{user(1)}, queue.Users)
+	assert.Empty(t, queue.Waitlist)
+
+	// Joining again doesn't bring the user back to the waitlist.
+	logInOut(t, s, user(2))
+
+	queue = getQueue(t, s)
+	assert.Equal(t, []entity.User{user(1), user(2)}, queue.Users)
+	assert.Empty(t, queue.Waitlist)
+}
+
+func testPromoteFromWaitlist(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2))
+	joinWaitlist(t, s, user(3))
+	joinWaitlist(t, s, user(4))
+	logInOut(t, s, user(1))
+
+	require.NoError(t, s.PromoteFromWaitlist(context.Background(), messageID, 3))
+
+	queue := getQueue(t, s)
+	assert.Equal(t, []entity.User{user(2), user(3)}, queue.Users)
+	assert.Equal(t, []entity.User{user(4)}, queue.Waitlist)
+}
+
+func testPromoteAfterStart(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1), user(2))
+	joinWaitlist(t, s, user(3))
+
+	require.NoError(t, s.StartQueue(context.Background(), messageID, true))
+
+	queue := getQueue(t, s)
+	assert.ElementsMatch(t, []entity.User{user(1), user(2)}, queue.Users)
+	assert.Equal(t, []entity.User{user(3)}, queue.Waitlist)
+
+	require.NoError(t, s.PromoteFromWaitlist(context.Background(), messageID, 3))
+
+	queue = getQueue(t, s)
+	require.Len(t, queue.Users, 3)
+	assert.Equal(t, user(3), queue.Users[2])
+	assert.Empty(t, queue.Waitlist)
+}
+
+func testPromoteNotWaitlisted(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1))
+	joinWaitlist(t, s, user(2))
+	logInOut(t, s, user(2))
+
+	for _, userID := range []int64{1, 2, 3} {
+		assert.ErrorIs(t, s.PromoteFromWaitlist(context.Background(), messageID, userID), storage.ErrNotWaitlisted)
+	}
+}
+
+func testRestoreWaitlist(t *testing.T, s storage.Storage) {
+	createQueue(t, s, user(1))
+	joinWaitlist(t, s, user(2))
+
+	require.NoError(t, s.SaveSnapshot(context.Background(), messageID, entity.OperationLeave))
+	logInOut(t, s, user(1))
+	require.NoError(t, s.PromoteFromWaitlist(context.Background(), messageID, 2))
+
+	_, err := s.RestoreSnapshot(context.Background(), messageID)
+	require.NoError(t, err)
+
+	queue := getQueue(t, s)
+	assert.Equal(t, []entity.User{user(1)}, queue.Users)
+	assert.Equal(t, []entity.User{user(2)}, queue.Waitlist)
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase/storage"
+)
+
+// CreateTemplate saves the template of its owner and returns its ID.
+func (b BotUseCase) CreateTemplate(ctx context.Context, template entity.Template) (int64, error) {
+	if err := validateTemplate(template); err != nil {
+		return 0, err
+	}
+
+	templateID, err := b.Storage.CreateTemplate(ctx, template)
+	if err != nil {
+		return 0, fmt.Errorf("couldn't create template in storage with error: %w", err)
+	}
+
+	return templateID, nil
+}
+
+// validateTemplate checks that queues can be posted from the template.
+func validateTemplate(template entity.Template) error {
+	if template.Description == "" || template.Capacity < 0 {
+		return ErrInvalidTemplate
+	}
+
+	if !template.IsScheduled() {
+		return nil
+	}
+
+	// Clock times are checked on any day, they mean the same every day.
+	if _, err := template.Schedule(time.Time{}); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+	}
+
+	return nil
+}
+
+// GetTemplate returns the template of the user. Templates are private, so others get storage.ErrTemplateNotFound.
+func (b BotUseCase) GetTemplate(ctx context.Context, templateID int64, userID int64) (entity.Template, error) {
+	template, err := b.Storage.GetTemplate(ctx, templateID)
+	if err != nil {
+		return entity.Template{}, fmt.Errorf("couldn't get template from storage with error: %w", err)
+	}
+
+	if template.OwnerID != userID {
+		return entity.Template{}, fmt.Errorf("template %d of another user: %w", templateID, storage.ErrTemplateNotFound)
+	}
+
+	return template, nil
+}
+
+func (b BotUseCase) GetTemplates(ctx context.Context, ownerID int64) ([]entity.Template, error) {
+	templates, err := b.Storage.GetTemplates(ctx, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get templates from storage with error: %w", err)
+	}
+
+	return templates, nil
+}
+
+// DeleteTemplate deletes the template of the user. Queues posted from it aren't changed.
+func (b BotUseCase) DeleteTemplate(ctx context.Context, templateID int64, userID int64) error {
+	if _, err := b.GetTemplate(ctx, templateID, userID); err != nil {
+		return err
+	}
+
+	if err := b.Storage.DeleteTemplate(ctx, templateID); err != nil {
+		return fmt.Errorf("couldn't delete template in storage with error: %w", err)
+	}
+
+	return nil
+}
+
+// SetQueueTemplate links the queue to the template of the user it was posted from.
+func (b BotUseCase) SetQueueTemplate(ctx context.Context, messageID string, userID int64, templateID int64) error {
+	if err := b.CheckAdmin(ctx, messageID, userID); err != nil {
+		return err
+	}
+
+	if _, err := b.GetTemplate(ctx, templateID, userID); err != nil {
+		return err
+	}
+
+	if err := b.Storage.SetQueueTemplate(ctx, messageID, templateID); err != nil {
+		return fmt.Errorf("couldn't set queue template in storage with error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase/storage"
+	"QueueBot/internal/usecase/storage/memory"
+)
+
+func TestBotUseCase_CreateTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template entity.Template
+		wantErr  error
+	}{
+		{name: "Unscheduled", template: entity.Template{Description: "Лаба по ОС", Capacity: 15}},
+		{name: "Scheduled", template: entity.Template{Description: "Лаба по ОС", OpensAt: "08:00", StartsAt: "09:00"}},
+		{name: "Without description", template: entity.Template{}, wantErr: ErrInvalidTemplate},
+		{name: "Negative capacity", template: entity.Template{Description: "Лаба", Capacity: -1}, wantErr: ErrInvalidTemplate},
+		{name: "Without start", template: entity.Template{Description: "Лаба", OpensAt: "08:00"}, wantErr: ErrInvalidTemplate},
+		{
+			name:     "Invalid clock time",
+			template: entity.Template{Description: "Лаба", OpensAt: "08:00", StartsAt: "25:00"},
+			wantErr:  ErrInvalidTemplate,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := NewBotUseCase(memory.NewStorage(), nil)
+
+			_, err := u.CreateTemplate(context.Background(), tt.template)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestBotUseCase_TemplatesArePrivate(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	const owner, other = int64(1), int64(2)
+
+	templateID, err := u.CreateTemplate(ctx, entity.Template{OwnerID: owner, Description: "Лаба по ОС"})
+	require.NoError(t, err)
+
+	_, err = u.GetTemplate(ctx, templateID, other)
+	assert.ErrorIs(t, err, storage.ErrTemplateNotFound)
+	assert.ErrorIs(t, u.DeleteTemplate(ctx, templateID, other), storage.ErrTemplateNotFound)
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Лаба по ОС", other, "ru", 0))
+	assert.ErrorIs(t, u.SetQueueTemplate(ctx, "123", other, templateID), storage.ErrTemplateNotFound)
+
+	require.NoError(t, u.DeleteTemplate(ctx, templateID, owner))
+
+	templates, err := u.GetTemplates(ctx, owner)
+	require.NoError(t, err)
+	assert.Empty(t, templates)
+}
+
+func TestBotUseCase_SetQueueTemplate(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	const owner = int64(1)
+
+	templateID, err := u.CreateTemplate(ctx, entity.Template{OwnerID: owner, Description: "Лаба по ОС"})
+	require.NoError(t, err)
+
+	for _, messageID := range []string{"123", "456"} {
+		require.NoError(t, u.CreateQueue(ctx, messageID, "Лаба по ОС", owner, "ru", 0))
+		require.NoError(t, u.SetQueueTemplate(ctx, messageID, owner, templateID))
+	}
+
+	queue, err := u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, templateID, queue.TemplateID)
+
+	templates, err := u.GetTemplates(ctx, owner)
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	assert.Equal(t, 2, templates[0].Instances)
+}
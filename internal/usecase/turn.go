@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"QueueBot/internal/entity"
+)
+
+// TurnDurations are values of Queue.TurnDuration admins switch between, in order. Zero turns timing off.
+var TurnDurations = []time.Duration{0, 5 * time.Minute, 7 * time.Minute, 10 * time.Minute, 15 * time.Minute}
+
+// TurnWarning is how long before the end of the turn the current person is warned.
+const TurnWarning = time.Minute
+
+// SwitchTurnDuration sets TurnDuration of the queue to the value following the current one in TurnDurations.
+// The current turn is timed from scratch.
+func (b BotUseCase) SwitchTurnDuration(ctx context.Context, messageID string, userID int64) error {
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	if !queue.IsAdmin(userID) {
+		return fmt.Errorf("user %d in queue %s: %w", userID, messageID, ErrNotAdmin)
+	}
+
+	next := TurnDurations[(slices.Index(TurnDurations, queue.TurnDuration)+1)%len(TurnDurations)]
+
+	if err = b.Storage.SetTurnDuration(ctx, messageID, next); err != nil {
+		return fmt.Errorf("couldn't set turn duration in storage with error: %w", err)
+	}
+
+	if err = b.setTurn(ctx, messageID, entity.Turn{}); err != nil {
+		return err
+	}
+
+	b.queueChanged(messageID)
+
+	return nil
+}
+
+// GetTimedQueueIDs returns message IDs of started queues with a turn duration.
+func (b BotUseCase) GetTimedQueueIDs(ctx context.Context) ([]string, error) {
+	messageIDs, err := b.Storage.GetTimedQueueIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get timed queues from storage with error: %w", err)
+	}
+
+	return messageIDs, nil
+}
+
+// CheckTurn reports whether the turn of the current person has to be updated with UpdateTurn: the person isn't timed
+// yet, has to be warned near the end of the turn or their turn is over. It doesn't change the queue.
+func (b BotUseCase) CheckTurn(ctx context.Context, messageID string, now time.Time) (bool, error) {
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return false, err
+	}
+
+	if !isTimed(queue) {
+		return false, nil
+	}
+
+	if !isTurnStarted(queue) {
+		return true, nil
+	}
+
+	left := turnLeft(queue, now)
+
+	return left <= 0 || left <= TurnWarning && !queue.Turn.IsWarned, nil
+}
+
+// UpdateTurn starts timing the current person when they have changed and warns them near the end of the turn.
+// When the turn is over, the next person is set to the queue and timed. The owner is recorded as the one who advanced it.
+func (b BotUseCase) UpdateTurn(ctx context.Context, messageID string, now time.Time) error {
+	queue, err := b.GetQueue(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	// The queue could be changed by an admin since CheckTurn.
+	if !isTimed(queue) {
+		return nil
+	}
+
+	current := queue.Users[queue.CurrentPersonIdx]
+	if !isTurnStarted(queue) {
+		return b.setTurn(ctx, messageID, entity.Turn{UserID: current.ID, StartedAt: now})
+	}
+
+	left := turnLeft(queue, now)
+	if left <= 0 {
+		if err = b.SetNextPersonToQueue(ctx, messageID, queue.OwnerID); err != nil {
+			return err
+		}
+
+		return b.UpdateTurn(ctx, messageID, now)
+	}
+
+	if left > TurnWarning || queue.Turn.IsWarned {
+		return nil
+	}
+
+	if b.Notifier != nil {
+		b.notifyUser(ctx, queue, current.ID, func() error {
+			return b.Notifier.NotifyTurnEnding(ctx, current.ID, queue.Description, queue.Language, left)
+		})
+	}
+
+	turn := queue.Turn
+	turn.IsWarned = true
+
+	return b.setTurn(ctx, messageID, turn)
+}
+
+// isTimed reports whether the queue has the current person whose turn is timed. Slots are finished by admins,
+// so they aren't timed.
+func isTimed(queue entity.Queue) bool {
+	return queue.IsStarted && queue.TurnDuration > 0 && !queue.HasSlots() && queue.CurrentPersonIdx < len(queue.Users)
+}
+
+// isTurnStarted reports whether the current person is the one being timed.
+func isTurnStarted(queue entity.Queue) bool {
+	return queue.Turn.UserID == queue.Users[queue.CurrentPersonIdx].ID && !queue.Turn.StartedAt.IsZero()
+}
+
+func turnLeft(queue entity.Queue, now time.Time) time.Duration {
+	return queue.Turn.StartedAt.Add(queue.TurnDuration).Sub(now)
+}
+
+func (b BotUseCase) setTurn(ctx context.Context, messageID string, turn entity.Turn) error {
+	if err := b.Storage.SetTurn(ctx, messageID, turn); err != nil {
+		return fmt.Errorf("couldn't set turn in storage with error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"QueueBot/internal/entity"
+	"QueueBot/internal/usecase/storage/memory"
+)
+
+func TestBotUseCase_SwitchTurnDuration(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	const owner = int64(1)
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", owner, "ru", 0))
+	assert.ErrorIs(t, u.SwitchTurnDuration(ctx, "123", 2), ErrNotAdmin)
+
+	// The last duration is followed by the first one.
+	for i := 1; i <= len(TurnDurations); i++ {
+		require.NoError(t, u.SwitchTurnDuration(ctx, "123", owner))
+
+		queue, err := u.GetQueue(ctx, "123")
+		require.NoError(t, err)
+		assert.Equal(t, TurnDurations[i%len(TurnDurations)], queue.TurnDuration)
+	}
+}
+
+func TestBotUseCase_TimedTurns(t *testing.T) {
+	ctx := context.Background()
+	notifier := &fakeNotifier{}
+	u := NewBotUseCase(memory.NewStorage(), notifier)
+
+	const owner = int64(1)
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", owner, "ru", 0))
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: 1}))
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: 2}))
+	require.NoError(t, u.Subscribe(ctx, 1))
+	require.NoError(t, u.SwitchTurnDuration(ctx, "123", owner))
+	require.NoError(t, u.StartQueue(ctx, "123", owner, false))
+
+	start := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+	duration := TurnDurations[1]
+
+	// Checking doesn't change the queue, the first person is timed by UpdateTurn.
+	for i := 0; i < 2; i++ {
+		isDue, err := u.CheckTurn(ctx, "123", start)
+		require.NoError(t, err)
+		assert.True(t, isDue)
+	}
+
+	require.NoError(t, u.UpdateTurn(ctx, "123", start))
+
+	queue, err := u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, entity.Turn{UserID: 1, StartedAt: start}, queue.Turn)
+
+	isDue, err := u.CheckTurn(ctx, "123", start.Add(duration-TurnWarning-time.Second))
+	require.NoError(t, err)
+	assert.False(t, isDue)
+
+	// The person is warned once.
+	warning := start.Add(duration - TurnWarning/2)
+
+	isDue, err = u.CheckTurn(ctx, "123", warning)
+	require.NoError(t, err)
+	assert.True(t, isDue)
+
+	require.NoError(t, u.UpdateTurn(ctx, "123", warning))
+	require.NoError(t, u.UpdateTurn(ctx, "123", warning))
+	assert.Equal(t, []int64{1}, notifier.endings)
+
+	isDue, err = u.CheckTurn(ctx, "123", warning)
+	require.NoError(t, err)
+	assert.False(t, isDue)
+
+	isDue, err = u.CheckTurn(ctx, "123", start.Add(duration))
+	require.NoError(t, err)
+	assert.True(t, isDue)
+
+	// The queue is advanced once and the next person is timed right away.
+	next := start.Add(duration + time.Second)
+	require.NoError(t, u.UpdateTurn(ctx, "123", next))
+	require.NoError(t, u.UpdateTurn(ctx, "123", next))
+
+	queue, err = u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, 1, queue.CurrentPersonIdx)
+	assert.Equal(t, entity.Turn{UserID: 2, StartedAt: next}, queue.Turn)
+
+	events, err := u.GetEvents(ctx, "123", 1)
+	require.NoError(t, err)
+	assert.Equal(t, entity.OperationNext, events[0].Operation)
+	assert.Equal(t, owner, events[0].UserID)
+}
+
+func TestBotUseCase_TimedTurnsAfterManualChange(t *testing.T) {
+	ctx := context.Background()
+	u := NewBotUseCase(memory.NewStorage(), nil)
+
+	const owner = int64(1)
+
+	require.NoError(t, u.CreateQueue(ctx, "123", "Test", owner, "ru", 0))
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: 1}))
+	require.NoError(t, u.LogInOutToQueue(ctx, "123", entity.User{ID: 2}))
+	require.NoError(t, u.SwitchTurnDuration(ctx, "123", owner))
+	require.NoError(t, u.StartQueue(ctx, "123", owner, false))
+
+	start := time.Date(2024, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, u.UpdateTurn(ctx, "123", start))
+
+	// The admin advances the queue, the next person gets the whole turn.
+	require.NoError(t, u.SetNextPersonToQueue(ctx, "123", owner))
+	require.NoError(t, u.UpdateTurn(ctx, "123", start.Add(TurnDurations[1])))
+
+	queue, err := u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, entity.Turn{UserID: 2, StartedAt: start.Add(TurnDurations[1])}, queue.Turn)
+
+	// Restarted queue times the first person from scratch.
+	require.NoError(t, u.StopQueue(ctx, "123", owner))
+	require.NoError(t, u.StartQueue(ctx, "123", owner, false))
+
+	queue, err = u.GetQueue(ctx, "123")
+	require.NoError(t, err)
+	assert.Equal(t, entity.Turn{}, queue.Turn)
+}